@@ -2,11 +2,15 @@ package main
 
 import (
 	"log"
+	"os"
 
+	"github.com/elokanugrah/go-order-system/internal/auth"
 	"github.com/elokanugrah/go-order-system/internal/config"
 	"github.com/elokanugrah/go-order-system/internal/database"
-	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/elokanugrah/go-order-system/internal/repository/file"
 	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/tax"
 	"github.com/elokanugrah/go-order-system/internal/usecase"
 
 	httpDelivery "github.com/elokanugrah/go-order-system/internal/delivery/http"
@@ -17,32 +21,95 @@ import (
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.ValidateAPI(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	db := database.NewConnection(cfg)
 	defer db.Close()
 	// --- WIRING / DEPENDENCY INJECTION ---
-
-	// Initialize Message Broker
-	mb, err := messagebroker.NewRabbitMQBroker(cfg.RabbitMQURL)
-	if err != nil {
-		log.Fatalf("Failed to initialize message broker: %v", err)
-	}
+	// Events are written to the outbox table and published by the relay service (cmd/relay).
 
 	// Initialize Repository Layer
 	productRepo := postgres.NewProductRepository(db)
 	orderRepo := postgres.NewOrderRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+	cartRepo := postgres.NewCartRepository(db)
+	couponRepo := postgres.NewCouponRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	addressRepo := postgres.NewAddressRepository(db)
+	paymentRepo := postgres.NewPaymentRepository(db)
+	returnRepo := postgres.NewReturnRepository(db)
+	shipmentRepo := postgres.NewShipmentRepository(db)
 	txManager := postgres.NewTransactionManager(db)
 
+	var rateProvider usecase.RateProvider = postgres.NewExchangeRateRepository(db)
+	if cfg.RatesFile != "" {
+		fileRates, err := file.NewRateProvider(cfg.RatesFile)
+		if err != nil {
+			log.Fatalf("Failed to load exchange rates: %v", err)
+		}
+		rateProvider = fileRates
+	}
+
+	taxRules := tax.DefaultRules()
+	if cfg.TaxRulesFile != "" {
+		fileRules, err := tax.LoadRules(cfg.TaxRulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load tax rules: %v", err)
+		}
+		taxRules = fileRules
+	}
+	taxCalculator, err := tax.NewRuleCalculator(taxRules)
+	if err != nil {
+		log.Fatalf("Invalid tax rules: %v", err)
+	}
+
+	paymentGateway, err := payment.NewGateway(cfg.PaymentProvider, cfg.AppEnv)
+	if err != nil {
+		log.Fatalf("Invalid payment configuration: %v", err)
+	}
+
 	// Initialize Usecase Layer
-	productUseCase := usecase.NewProductUseCase(productRepo)
-	orderUseCase := usecase.NewOrderUseCase(orderRepo, productRepo, txManager, mb)
+	productUseCase := usecase.NewProductUseCase(productRepo, taxCalculator)
+	promotionUseCase := usecase.NewPromotionUseCase(couponRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, addressRepo, orderRepo)
+	orderUseCase := usecase.NewOrderUseCase(orderRepo, productRepo, txManager, outboxRepo, idempotencyRepo, rateProvider, promotionUseCase, taxCalculator, userUseCase, paymentRepo, shipmentRepo, returnRepo)
+	cartUseCase := usecase.NewCartUseCase(cartRepo, productRepo, rateProvider, txManager, orderUseCase)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo)
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, orderRepo, txManager, paymentGateway, orderUseCase)
+	returnUseCase := usecase.NewReturnUseCase(returnRepo, orderRepo, productRepo, txManager, outboxRepo, paymentUseCase)
+	shipmentUseCase := usecase.NewShipmentUseCase(shipmentRepo, orderRepo, txManager, outboxRepo, orderUseCase)
+
+	jwtConfig := auth.JWTConfig{
+		Algorithm: cfg.JWTAlgorithm,
+		Secret:    []byte(cfg.JWTSecret),
+		Issuer:    cfg.JWTIssuer,
+		Audience:  cfg.JWTAudience,
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		jwtConfig.PublicKeyPEM, err = os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			log.Fatalf("Failed to read JWT public key: %v", err)
+		}
+	}
+	tokenVerifier, err := auth.NewJWTVerifier(jwtConfig)
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+
+	webhookVerifier, err := payment.NewWebhookVerifier([]byte(cfg.PaymentWebhookSecret))
+	if err != nil {
+		log.Fatalf("Invalid payment webhook configuration: %v", err)
+	}
 
 	// Initialize Delivery Layer (Handler)
-	// For now, orderUseCase is nil because we haven't built it completely.
-	apiHandler := httpDelivery.NewHandler(productUseCase, orderUseCase)
+	apiHandler := httpDelivery.NewHandler(productUseCase, orderUseCase, cartUseCase, promotionUseCase, apiKeyUseCase, userUseCase, paymentUseCase, returnUseCase, shipmentUseCase)
 
 	// Setup Router and Start Server
-	router := httpDelivery.SetupRouter(apiHandler)
+	router := httpDelivery.SetupRouter(apiHandler, tokenVerifier, apiKeyUseCase, webhookVerifier)
 
 	log.Printf("Starting server on port %s", cfg.ServerPort)
 	if err := router.Run(":" + cfg.ServerPort); err != nil {
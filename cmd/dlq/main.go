@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// dlq inspects or replays the dead-letter queue of a work queue.
+//
+//	go run ./cmd/dlq -queue orders.created            # print up to 10 messages, leave them in place
+//	go run ./cmd/dlq -queue orders.created -replay    # move up to 10 messages back to the work queue
+func main() {
+	queueName := flag.String("queue", "orders.created", "work queue whose dead-letter queue is used")
+	replay := flag.Bool("replay", false, "move the messages back to the work queue instead of only printing them")
+	limit := flag.Int("limit", 10, "maximum number of messages to handle")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	conn, err := amqp.Dial(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to connect to RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("FATAL: Failed to open a channel: %v", err)
+	}
+	// Closing the channel returns every message that was not acked to the dead-letter queue.
+	defer ch.Close()
+
+	if err := messagebroker.DeclareQueue(ch, *queueName); err != nil {
+		log.Fatalf("FATAL: Failed to declare queues: %v", err)
+	}
+
+	dlqName := messagebroker.DeadLetterQueueName(*queueName)
+	handled := 0
+	for handled < *limit {
+		d, ok, err := ch.Get(dlqName, false)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to get message from %s: %v", dlqName, err)
+		}
+		if !ok {
+			break // Queue is empty.
+		}
+		handled++
+
+		fmt.Printf("#%d retries=%d deaths=%v\n%s\n\n", handled, messagebroker.RetryCount(d.Headers), d.Headers["x-death"], d.Body)
+
+		if *replay {
+			if err := replayMessage(ch, *queueName, d); err != nil {
+				log.Fatalf("FATAL: Failed to replay message: %v", err)
+			}
+		}
+	}
+
+	if *replay {
+		log.Printf("Replayed %d message(s) from %s to %s.", handled, dlqName, *queueName)
+	} else {
+		log.Printf("Inspected %d message(s) in %s. Use -replay to move them back to %s.", handled, dlqName, *queueName)
+	}
+}
+
+// replayMessage republishes a dead-lettered message to its work queue with a fresh
+// retry budget, then removes it from the dead-letter queue.
+func replayMessage(ch *amqp.Channel, queueName string, d amqp.Delivery) error {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		if k == messagebroker.RetryCountHeader || k == "x-death" {
+			continue
+		}
+		headers[k] = v
+	}
+
+	err := ch.PublishWithContext(context.Background(),
+		"",        // exchange (default)
+		queueName, // routing key (queue name)
+		false,     // mandatory
+		false,     // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			Body:         d.Body,
+			DeliveryMode: amqp.Persistent,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	return d.Ack(false)
+}
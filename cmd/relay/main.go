@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	log.Println("Starting Outbox Relay Service...")
+
+	cfg := config.Load()
+
+	db := database.NewConnection(cfg)
+	defer db.Close()
+
+	mb, err := messagebroker.NewRabbitMQBroker(cfg.RabbitMQURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize message broker: %v", err)
+	}
+
+	outboxRepo := postgres.NewOutboxRepository(db)
+	txManager := postgres.NewTransactionManager(db)
+	relay := usecase.NewOutboxRelay(outboxRepo, txManager, mb, cfg.OutboxBatchSize)
+
+	// Handles graceful shutdown on receiving SIGINT or SIGTERM signals.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Relay is polling the outbox every %s. To exit press CTRL+C", cfg.OutboxPollInterval)
+	relay.Run(ctx, cfg.OutboxPollInterval)
+
+	log.Println("Relay exited gracefully.")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/elokanugrah/go-order-system/internal/config"
 	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
 
 	"github.com/go-faker/faker/v4"
 	_ "github.com/lib/pq" // PostgreSQL driver
@@ -52,9 +53,9 @@ func seedProducts(db *sql.DB) error {
 
 	for i := 0; i < 50; i++ {
 		// Generate fake data
-		name := fmt.Sprintf("%s %s", faker.Word(), faker.Word()) // e.g., "Awesome Gadget"
-		price := float64(rand.Intn(1000000) + 5000)              // Price between 5,000 and 1,005,000
-		quantity := rand.Intn(100) + 10                          // Quantity between 10 and 110
+		name := fmt.Sprintf("%s %s", faker.Word(), faker.Word())         // e.g., "Awesome Gadget"
+		price := domain.NewMoney(int64(rand.Intn(1000000)+5000)*100, "") // Price between 5,000 and 1,005,000
+		quantity := rand.Intn(100) + 10                                  // Quantity between 10 and 110
 		now := time.Now()
 
 		// Execute the prepared statement within the transaction
@@ -1,14 +1,16 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/events"
+	"github.com/elokanugrah/go-order-system/internal/worker"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -30,59 +32,115 @@ func main() {
 	}
 	defer ch.Close()
 
-	// Declare the queue to make sure it exists
-	q, err := ch.QueueDeclare(
-		"orders.created",
-		true, false, false, false, nil,
-	)
-	if err != nil {
-		log.Fatalf("Failed to declare a queue: %v", err)
-	}
+	w := worker.New(ch, worker.Config{
+		ConsumerName: "order-worker",
+		Concurrency:  cfg.WorkerConcurrency,
+		Prefetch:     cfg.WorkerPrefetch,
+		MaxRetries:   cfg.WorkerMaxRetries,
+		RetryDelay:   cfg.WorkerRetryDelay,
+	})
 
-	// Start consuming messages from the queue
-	msgs, err := ch.Consume(
-		q.Name,
-		"order-worker", // consumer name
-		true,           // auto-ack
-		false,          // exclusive
-		false,          // no-local
-		false,          // no-wait
-		nil,
-	)
-	if err != nil {
-		log.Fatalf("Failed to register a consumer: %v", err)
+	// Register a handler per event type; each one is consumed from its own queue.
+	// Every type the API publishes needs a handler, or its queue is never drained.
+	w.Handle(events.TypeOrderCreated, handleOrderCreated)
+	for _, t := range []events.Type{events.TypeOrderPaid, events.TypeOrderShipped, events.TypeOrderCompleted, events.TypeOrderCancelled} {
+		w.Handle(t, handleOrderStatusChanged)
+	}
+	w.Handle(events.TypeReturnRequested, handleReturnRequested)
+	for _, t := range []events.Type{events.TypeReturnApproved, events.TypeReturnRejected, events.TypeReturnReceived} {
+		w.Handle(t, handleReturnStatusChanged)
+	}
+	w.Handle(events.TypeShipmentCreated, handleShipmentCreated)
+	for _, t := range []events.Type{events.TypeShipmentInTransit, events.TypeShipmentDelivered} {
+		w.Handle(t, handleShipmentStatusChanged)
 	}
 
-	// Goroutine to process messages
-	go func() {
-		for d := range msgs {
-			log.Printf("Received a message: %s", d.Body)
-			processMessage(d.Body)
-		}
-	}()
+	// Handles graceful shutdown on receiving SIGINT or SIGTERM signals.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	log.Printf("Worker is waiting for messages. To exit press CTRL+C")
 
-	// Handles graceful shutdown on receiving SIGINT or SIGTERM signals.
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Println("Shutting down worker...")
+	if err := w.Run(ctx); err != nil {
+		log.Printf("Worker stopped: %v", err)
+		return
+	}
 
 	log.Println("Worker exited gracefully.")
 }
 
-// A helper function to process the message payload.
-func processMessage(body []byte) {
+// handleOrderCreated sends the order confirmation.
+func handleOrderCreated(ctx context.Context, env *events.Envelope) error {
+	var payload events.OrderCreated
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
+	}
+
 	time.Sleep(2 * time.Second) // Simulate a 2-second task
 
-	var payload map[string]interface{}
-	if err := json.Unmarshal(body, &payload); err == nil {
-		if orderID, ok := payload["order_id"]; ok {
-			log.Printf("[WORKER] Finished processing confirmation for Order ID: %.0f", orderID)
-		}
-	} else {
-		log.Printf("[WORKER] ERROR: Failed to unmarshal message: %v", err)
+	log.Printf("[WORKER] Finished processing confirmation for Order ID: %d (event %s, correlation %s, %d items, total %s)",
+		payload.OrderID, env.EventID, env.CorrelationID, len(payload.Items), payload.TotalAmount)
+	return nil
+}
+
+// handleOrderStatusChanged notifies the customer that the order was paid, shipped,
+// completed or cancelled.
+func handleOrderStatusChanged(ctx context.Context, env *events.Envelope) error {
+	var payload events.OrderStatusChanged
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
+	}
+
+	log.Printf("[WORKER] Notified user %d that order %d is %s (event %s, correlation %s)",
+		payload.UserID, payload.OrderID, payload.Status, env.EventID, env.CorrelationID)
+	return nil
+}
+
+// handleReturnRequested notifies staff of a new return to review.
+func handleReturnRequested(ctx context.Context, env *events.Envelope) error {
+	var payload events.ReturnRequested
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
+	}
+
+	log.Printf("[WORKER] Return %d of order %d requested: %d items, refund %s (event %s, correlation %s)",
+		payload.ReturnID, payload.OrderID, len(payload.Items), payload.RefundAmount, env.EventID, env.CorrelationID)
+	return nil
+}
+
+// handleReturnStatusChanged notifies the customer that the return was approved,
+// rejected or received and refunded.
+func handleReturnStatusChanged(ctx context.Context, env *events.Envelope) error {
+	var payload events.ReturnStatusChanged
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
 	}
+
+	log.Printf("[WORKER] Notified user %d that return %d is %s (event %s, correlation %s)",
+		payload.UserID, payload.ReturnID, payload.Status, env.EventID, env.CorrelationID)
+	return nil
+}
+
+// handleShipmentCreated sends the customer the tracking number of a new shipment.
+func handleShipmentCreated(ctx context.Context, env *events.Envelope) error {
+	var payload events.ShipmentCreated
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
+	}
+
+	log.Printf("[WORKER] Shipment %d of order %d handed to %s as %s (event %s, correlation %s)",
+		payload.ShipmentID, payload.OrderID, payload.Carrier, payload.TrackingNumber, env.EventID, env.CorrelationID)
+	return nil
+}
+
+// handleShipmentStatusChanged notifies the customer that a shipment is in transit or delivered.
+func handleShipmentStatusChanged(ctx context.Context, env *events.Envelope) error {
+	var payload events.ShipmentStatusChanged
+	if err := env.DecodePayload(&payload); err != nil {
+		return fmt.Errorf("%w: %v", worker.ErrPermanent, err)
+	}
+
+	log.Printf("[WORKER] Shipment %d of order %d is %s (event %s, correlation %s)",
+		payload.ShipmentID, payload.OrderID, payload.Status, env.EventID, env.CorrelationID)
+	return nil
 }
@@ -0,0 +1,247 @@
+// Package auth verifies the credentials API clients authenticate with.
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+var (
+	ErrInvalidToken = domain.NewError(domain.KindUnauthenticated, "invalid_token", "invalid token")
+	ErrTokenExpired = domain.NewError(domain.KindUnauthenticated, "token_expired", "token has expired")
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+
+	// leeway tolerates clock skew between the token issuer and this service.
+	leeway = 30 * time.Second
+)
+
+// JWTConfig configures how tokens are verified.
+type JWTConfig struct {
+	Algorithm    string // HS256 or RS256.
+	Secret       []byte // HMAC key for HS256.
+	PublicKeyPEM []byte // PEM encoded RSA public key for RS256.
+	Issuer       string // Expected "iss" claim; not checked when empty.
+	Audience     string // Expected "aud" claim; not checked when empty.
+}
+
+// JWTVerifier verifies signed JSON Web Tokens and turns their claims into a principal.
+// Tokens must be signed with the configured algorithm, carry the user ID in "sub",
+// an expiry in "exp" and optionally a list of "roles".
+type JWTVerifier struct {
+	algorithm string
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string
+	audience  string
+}
+
+// NewJWTVerifier returns a verifier for the configured algorithm and key.
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		algorithm: cfg.Algorithm,
+		issuer:    cfg.Issuer,
+		audience:  cfg.Audience,
+	}
+
+	switch cfg.Algorithm {
+	case HS256:
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		v.secret = cfg.Secret
+	case RS256:
+		key, err := parseRSAPublicKey(cfg.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		v.publicKey = key
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	return v, nil
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+}
+
+type claims struct {
+	Subject   string        `json:"sub"`
+	Issuer    string        `json:"iss"`
+	Audience  audience      `json:"aud"`
+	ExpiresAt *json.Number  `json:"exp"`
+	NotBefore *json.Number  `json:"nbf"`
+	Roles     []domain.Role `json:"roles"`
+}
+
+// audience accepts the "aud" claim as a single string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Verify checks the signature and claims of a token and returns its principal.
+// Errors wrap ErrInvalidToken, or ErrTokenExpired for tokens past their expiry.
+func (v *JWTVerifier) Verify(token string) (*domain.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	// The algorithm is fixed by configuration, never chosen by the token.
+	if h.Algorithm != v.algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if !v.validSignature(parts[0]+"."+parts[1], signature) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.validateClaims(&c); err != nil {
+		return nil, err
+	}
+
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, fmt.Errorf("%w: subject must be a user ID", ErrInvalidToken)
+	}
+
+	return &domain.Principal{UserID: userID, Roles: c.Roles}, nil
+}
+
+func (v *JWTVerifier) validSignature(signingInput string, signature []byte) bool {
+	switch v.algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		return hmac.Equal(signature, mac.Sum(nil))
+	case RS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+func (v *JWTVerifier) validateClaims(c *claims) error {
+	now := time.Now()
+
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("%w: missing expiry", ErrInvalidToken)
+	}
+	exp, err := numericDate(*c.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%w: malformed expiry", ErrInvalidToken)
+	}
+	if !now.Before(exp.Add(leeway)) {
+		return ErrTokenExpired
+	}
+
+	if c.NotBefore != nil {
+		nbf, err := numericDate(*c.NotBefore)
+		if err != nil {
+			return fmt.Errorf("%w: malformed not-before", ErrInvalidToken)
+		}
+		if now.Add(leeway).Before(nbf) {
+			return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+		}
+	}
+
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !containsString(c.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericDate converts a JWT NumericDate, in seconds since the epoch, into a time.
+func numericDate(n json.Number) (time.Time, error) {
+	seconds, err := n.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRSAPublicKey parses a PEM encoded PKIX or PKCS #1 RSA public key.
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("RS256 public key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("RS256 public key is not an RSA key")
+		}
+		return rsaKey, nil
+	}
+
+	key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing RS256 public key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signHS256 builds a token with the given header algorithm and claims, signed with testSecret.
+func signHS256(t *testing.T, alg string, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": alg, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, testSecret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": RS256, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "42",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"admin"},
+	}
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{Algorithm: HS256, Secret: testSecret, Issuer: "shop", Audience: "orders"})
+	require.NoError(t, err)
+
+	withClaims := func(changes map[string]interface{}) map[string]interface{} {
+		claims := validClaims()
+		claims["iss"] = "shop"
+		claims["aud"] = []string{"orders", "carts"}
+		for k, v := range changes {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		principal, err := verifier.Verify(signHS256(t, HS256, withClaims(nil)))
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.Principal{UserID: 42, Roles: []domain.Role{domain.RoleAdmin}}, principal)
+	})
+
+	t.Run("Fail - Expired", func(t *testing.T) {
+		token := signHS256(t, HS256, withClaims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()}))
+
+		_, err := verifier.Verify(token)
+
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("Success - Expired Within Leeway", func(t *testing.T) {
+		token := signHS256(t, HS256, withClaims(map[string]interface{}{"exp": time.Now().Add(-10 * time.Second).Unix()}))
+
+		_, err := verifier.Verify(token)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fail - Tampered Claims", func(t *testing.T) {
+		parts := strings.Split(signHS256(t, HS256, withClaims(nil)), ".")
+		parts[1] = encodeSegment(t, withClaims(map[string]interface{}{"sub": "1"}))
+
+		_, err := verifier.Verify(strings.Join(parts, "."))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Fail - Tampered Signature", func(t *testing.T) {
+		token := signHS256(t, HS256, withClaims(nil))
+		// Change the first character of the signature; the last one may only hold padding bits.
+		i := strings.LastIndex(token, ".") + 1
+		first := "A"
+		if token[i] == 'A' {
+			first = "B"
+		}
+
+		_, err := verifier.Verify(token[:i] + first + token[i+1:])
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Fail - Unsigned Token", func(t *testing.T) {
+		token := encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, withClaims(nil)) + "."
+
+		_, err := verifier.Verify(token)
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Fail - Invalid Claims", func(t *testing.T) {
+		cases := map[string]map[string]interface{}{
+			"missing expiry":    {"exp": nil},
+			"not valid yet":     {"nbf": time.Now().Add(time.Hour).Unix()},
+			"wrong issuer":      {"iss": "someone-else"},
+			"wrong audience":    {"aud": "payments"},
+			"subject not an ID": {"sub": "alice"},
+		}
+		for name, changes := range cases {
+			_, err := verifier.Verify(signHS256(t, HS256, withClaims(changes)))
+			assert.ErrorIs(t, err, ErrInvalidToken, name)
+		}
+	})
+
+	t.Run("Fail - Malformed", func(t *testing.T) {
+		_, err := verifier.Verify("not-a-token")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestJWTVerifier_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	verifier, err := NewJWTVerifier(JWTConfig{Algorithm: RS256, PublicKeyPEM: publicKeyPEM})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		principal, err := verifier.Verify(signRS256(t, key, validClaims()))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), principal.UserID)
+	})
+
+	t.Run("Fail - Signed By Another Key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(signRS256(t, otherKey, validClaims()))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Fail - HS256 Signed With Public Key", func(t *testing.T) {
+		// Algorithm confusion: an attacker signs an HS256 token using the public key as HMAC secret.
+		signingInput := encodeSegment(t, map[string]string{"alg": HS256}) + "." + encodeSegment(t, validClaims())
+		mac := hmac.New(sha256.New, publicKeyPEM)
+		mac.Write([]byte(signingInput))
+		token := signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+		_, err := verifier.Verify(token)
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Fail - Expired", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		_, err := verifier.Verify(signRS256(t, key, claims))
+
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+}
+
+func TestNewJWTVerifier_InvalidConfig(t *testing.T) {
+	_, err := NewJWTVerifier(JWTConfig{Algorithm: HS256, Secret: []byte("short")})
+	assert.Error(t, err)
+
+	_, err = NewJWTVerifier(JWTConfig{Algorithm: RS256, PublicKeyPEM: []byte("not pem")})
+	assert.Error(t, err)
+
+	_, err = NewJWTVerifier(JWTConfig{Algorithm: "none"})
+	assert.Error(t, err)
+}
@@ -5,12 +5,16 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
+	// AppEnv is the environment the service runs in: development, test or production.
+	AppEnv     string `env:"APP_ENV" envDefault:"production"`
 	ServerPort string `env:"SERVER_PORT" envDefault:"9000"`
 
 	DBHost     string `env:"DB_HOST,required"`
@@ -20,6 +24,32 @@ type Config struct {
 	DBName     string `env:"DB_NAME,required"`
 
 	RabbitMQURL string `env:"RABBITMQ_URL,required"`
+
+	OutboxPollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+
+	WorkerConcurrency int           `env:"WORKER_CONCURRENCY" envDefault:"4"`
+	WorkerPrefetch    int           `env:"WORKER_PREFETCH" envDefault:"8"`
+	WorkerMaxRetries  int           `env:"WORKER_MAX_RETRIES" envDefault:"5"`
+	WorkerRetryDelay  time.Duration `env:"WORKER_RETRY_DELAY" envDefault:"10s"`
+
+	// RatesFile points to a JSON file of exchange rates. When empty, rates are read from the exchange_rates table.
+	RatesFile string `env:"RATES_FILE"`
+	// TaxRulesFile points to a JSON file of tax rules. When empty, the Indonesian VAT rules are used.
+	TaxRulesFile string `env:"TAX_RULES_FILE"`
+
+	// JWTAlgorithm is HS256, verified with JWTSecret, or RS256, verified with the PEM public key in JWTPublicKeyFile.
+	JWTAlgorithm     string `env:"JWT_ALGORITHM" envDefault:"HS256"`
+	JWTSecret        string `env:"JWT_SECRET"`
+	JWTPublicKeyFile string `env:"JWT_PUBLIC_KEY_FILE"`
+	JWTIssuer        string `env:"JWT_ISSUER"`
+	JWTAudience      string `env:"JWT_AUDIENCE"`
+
+	// PaymentProvider selects the payment gateway. Only "fake" is built in, and it is
+	// refused unless AppEnv is development or test.
+	PaymentProvider string `env:"PAYMENT_PROVIDER" envDefault:"fake"`
+	// PaymentWebhookSecret is shared with the payment provider to sign its webhooks, at least 32 bytes.
+	PaymentWebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET"`
 }
 
 func (c *Config) DSN() string {
@@ -28,6 +58,30 @@ func (c *Config) DSN() string {
 	)
 }
 
+// ValidateAPI reports the settings the API needs on top of the ones every service needs.
+// They cannot be required by their tags, because the worker and the relay share Config.
+func (c *Config) ValidateAPI() error {
+	var missing []string
+	switch c.JWTAlgorithm {
+	case "HS256":
+		if c.JWTSecret == "" {
+			missing = append(missing, "JWT_SECRET")
+		}
+	case "RS256":
+		if c.JWTPublicKeyFile == "" {
+			missing = append(missing, "JWT_PUBLIC_KEY_FILE")
+		}
+	}
+	if c.PaymentWebhookSecret == "" {
+		missing = append(missing, "PAYMENT_WEBHOOK_SECRET")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("required environment variables are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func Load() *Config {
 	loadDotEnv()
 
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ValidateAPI(t *testing.T) {
+	t.Run("should accept a complete configuration", func(t *testing.T) {
+		cfg := &Config{JWTAlgorithm: "HS256", JWTSecret: "secret", PaymentWebhookSecret: "secret"}
+
+		assert.NoError(t, cfg.ValidateAPI())
+	})
+
+	t.Run("should name the missing secrets", func(t *testing.T) {
+		cfg := &Config{JWTAlgorithm: "HS256"}
+
+		err := cfg.ValidateAPI()
+
+		assert.EqualError(t, err, "required environment variables are not set: JWT_SECRET, PAYMENT_WEBHOOK_SECRET")
+	})
+
+	t.Run("should require the public key for RS256", func(t *testing.T) {
+		cfg := &Config{JWTAlgorithm: "RS256", PaymentWebhookSecret: "secret"}
+
+		assert.EqualError(t, cfg.ValidateAPI(), "required environment variables are not set: JWT_PUBLIC_KEY_FILE")
+	})
+}
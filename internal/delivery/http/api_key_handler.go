@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type createAPIKeyRequest struct {
+	Name      string         `json:"name" binding:"required"`
+	Scopes    []domain.Scope `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
+
+// CreateAPIKey mints an API key. The response is the only time the plain key is shown.
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req createAPIKeyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	created, err := h.apiKeyUseCase.CreateAPIKey(c.Request.Context(), dto.CreateAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUseCase.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeyUseCase.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
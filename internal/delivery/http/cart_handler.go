@@ -0,0 +1,156 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type createCartRequest struct {
+	Currency string `json:"currency" binding:"omitempty,len=3,uppercase"`
+}
+
+type addCartItemRequest struct {
+	ProductID int64 `json:"product_id" binding:"required"`
+	Quantity  int   `json:"quantity" binding:"required,gt=0"`
+}
+
+// checkoutRequest is optional; without a body the order gets no coupon, the default
+// tax region and no shipping address.
+type checkoutRequest struct {
+	CouponCode string `json:"coupon_code"`
+	Region     string `json:"region" binding:"omitempty,uppercase"`
+
+	// At most one of these may be given.
+	ShippingAddressID int64                 `json:"shipping_address_id"`
+	ShippingAddress   *domain.PostalAddress `json:"shipping_address"`
+}
+
+type updateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+func (h *Handler) CreateCart(c *gin.Context) {
+	var req createCartRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cart, err := h.cartUseCase.CreateCart(c.Request.Context(), dto.CreateCartInput{
+		UserID:   domain.PrincipalFromContext(c.Request.Context()).UserID,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cart)
+}
+
+func (h *Handler) GetCart(c *gin.Context) {
+	cartID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartUseCase.GetCart(c.Request.Context(), cartID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *Handler) AddCartItem(c *gin.Context) {
+	cartID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req addCartItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cart, err := h.cartUseCase.AddItem(c.Request.Context(), cartID, req.ProductID, req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *Handler) UpdateCartItem(c *gin.Context) {
+	cartID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	productID, ok := parseIDParam(c, "product_id")
+	if !ok {
+		return
+	}
+
+	var req updateCartItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	cart, err := h.cartUseCase.UpdateItemQuantity(c.Request.Context(), cartID, productID, req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *Handler) RemoveCartItem(c *gin.Context) {
+	cartID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	productID, ok := parseIDParam(c, "product_id")
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartUseCase.RemoveItem(c.Request.Context(), cartID, productID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// CheckoutCart turns the cart into an order.
+func (h *Handler) CheckoutCart(c *gin.Context) {
+	cartID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req checkoutRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	order, err := h.cartUseCase.Checkout(c.Request.Context(), cartID, dto.CheckoutInput{
+		CouponCode: req.CouponCode,
+		Region:     req.Region,
+
+		ShippingAddressID: req.ShippingAddressID,
+		ShippingAddress:   req.ShippingAddress,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
@@ -1,17 +1,77 @@
 package http
 
 import (
+	"strconv"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	productUseCase *usecase.ProductUseCase
-	orderUseCase   *usecase.OrderUseCase
+	productUseCase   *usecase.ProductUseCase
+	orderUseCase     *usecase.OrderUseCase
+	cartUseCase      *usecase.CartUseCase
+	promotionUseCase *usecase.PromotionUseCase
+	apiKeyUseCase    *usecase.APIKeyUseCase
+	userUseCase      *usecase.UserUseCase
+	paymentUseCase   *usecase.PaymentUseCase
+	returnUseCase    *usecase.ReturnUseCase
+	shipmentUseCase  *usecase.ShipmentUseCase
 }
 
-func NewHandler(puc *usecase.ProductUseCase, ouc *usecase.OrderUseCase) *Handler {
+func NewHandler(puc *usecase.ProductUseCase, ouc *usecase.OrderUseCase, cuc *usecase.CartUseCase, pmuc *usecase.PromotionUseCase, akuc *usecase.APIKeyUseCase, uuc *usecase.UserUseCase, payuc *usecase.PaymentUseCase, ruc *usecase.ReturnUseCase, suc *usecase.ShipmentUseCase) *Handler {
 	return &Handler{
-		productUseCase: puc,
-		orderUseCase:   ouc,
+		productUseCase:   puc,
+		orderUseCase:     ouc,
+		cartUseCase:      cuc,
+		promotionUseCase: pmuc,
+		apiKeyUseCase:    akuc,
+		userUseCase:      uuc,
+		paymentUseCase:   payuc,
+		returnUseCase:    ruc,
+		shipmentUseCase:  suc,
+	}
+}
+
+// Handlers do not write error responses themselves. They record the error with
+// c.Error and return, and ErrorHandler renders it as a problem.
+
+// bindJSON decodes and validates the request body into obj. If that fails, it records
+// a bind error and reports false.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
+
+// parseIDParam parses the path parameter name as an ID. If it is not one, it records
+// a validation error and reports false.
+func parseIDParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		c.Error(domain.NewFieldError(domain.ErrInvalidInput, name, "must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
+
+// parsePage parses the page and pageSize query parameters, which default to 1 and 10.
+// If either is invalid, it records a validation error and reports false.
+func parsePage(c *gin.Context) (page, pageSize int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.Error(domain.NewFieldError(domain.ErrInvalidInput, "page", "must be a positive integer"))
+		return 0, 0, false
 	}
+
+	pageSize, err = strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize <= 0 {
+		c.Error(domain.NewFieldError(domain.ErrInvalidInput, "pageSize", "must be a positive integer"))
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
 }
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elokanugrah/go-order-system/internal/auth"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/events"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// CorrelationIDHeader is the header used to pass a correlation ID in and out of the API.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// CorrelationID takes the correlation ID from the request header, or generates one,
+// and stores it in the request context so that published events carry it.
+func CorrelationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CorrelationIDHeader)
+		if id == "" {
+			var err error
+			if id, err = events.NewID(); err != nil {
+				c.Next()
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(events.WithCorrelationID(c.Request.Context(), id))
+		c.Header(CorrelationIDHeader, id)
+		c.Next()
+	}
+}
+
+// TokenVerifier turns a bearer token into the principal it was issued to.
+type TokenVerifier interface {
+	Verify(token string) (*domain.Principal, error)
+}
+
+// APIKeyAuthenticator turns an API key into the principal of the machine client using it.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+// Authenticate requires either an "Authorization: Bearer <token>" header from a user
+// or an "Authorization: ApiKey <key>" header from a machine client, and stores the
+// principal of the credential in the request context.
+func Authenticate(tokens TokenVerifier, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, credential, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+
+		var principal *domain.Principal
+		var err error
+		switch {
+		case credential == "":
+			unauthorized(c, "Bearer", fmt.Errorf("%w: missing bearer token or API key", usecase.ErrUnauthenticated))
+			return
+		case strings.EqualFold(scheme, "Bearer"):
+			principal, err = tokens.Verify(credential)
+			if err != nil {
+				if !errors.Is(err, auth.ErrTokenExpired) {
+					err = auth.ErrInvalidToken // Do not tell clients why a token was refused.
+				}
+				unauthorized(c, `Bearer error="invalid_token"`, err)
+				return
+			}
+		case strings.EqualFold(scheme, "ApiKey"):
+			principal, err = apiKeys.AuthenticateAPIKey(c.Request.Context(), credential)
+			if errors.Is(err, domain.ErrInvalidAPIKey) {
+				unauthorized(c, "ApiKey", domain.ErrInvalidAPIKey)
+				return
+			}
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+		default:
+			unauthorized(c, "Bearer", fmt.Errorf("%w: unsupported authorization scheme", usecase.ErrUnauthenticated))
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// WebhookVerifier checks that a webhook body was signed by the payment provider.
+type WebhookVerifier interface {
+	Verify(payload []byte, signature string) error
+}
+
+// VerifyWebhook rejects webhook requests whose body does not match the signature in
+// the payment.SignatureHeader header. The body is left for the handler to read.
+func VerifyWebhook(webhooks WebhookVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if err := webhooks.Verify(body, c.GetHeader(payment.SignatureHeader)); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// unauthorized rejects the request with a challenge for the expected credentials.
+func unauthorized(c *gin.Context, challenge string, err error) {
+	c.Header("WWW-Authenticate", challenge)
+	c.Error(err)
+	c.Abort()
+}
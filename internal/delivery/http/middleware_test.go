@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/auth"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubVerifier accepts the credentials in its map and returns err for all others.
+type stubVerifier struct {
+	principals map[string]*domain.Principal
+	err        error
+}
+
+func (v stubVerifier) Verify(token string) (*domain.Principal, error) {
+	if p, ok := v.principals[token]; ok {
+		return p, nil
+	}
+	return nil, v.err
+}
+
+func (v stubVerifier) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	return v.Verify(key)
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokens := stubVerifier{
+		principals: map[string]*domain.Principal{"admin-token": {UserID: 1, Roles: []domain.Role{domain.RoleAdmin}}},
+		err:        auth.ErrInvalidToken,
+	}
+	apiKeys := stubVerifier{
+		principals: map[string]*domain.Principal{"gos_1.secret": {APIKeyID: 7, Scopes: []domain.Scope{domain.ScopeReadAnyOrder}}},
+		err:        domain.ErrInvalidAPIKey,
+	}
+
+	newRouter := func(tokens TokenVerifier, apiKeys APIKeyAuthenticator) *gin.Engine {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.GET("/", Authenticate(tokens, apiKeys), func(c *gin.Context) {
+			c.JSON(http.StatusOK, domain.PrincipalFromContext(c.Request.Context()))
+		})
+		return router
+	}
+	send := func(router *gin.Engine, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	router := newRouter(tokens, apiKeys)
+
+	t.Run("Success - Bearer Token", func(t *testing.T) {
+		rec := send(router, "Bearer admin-token")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"UserID":1`)
+	})
+
+	t.Run("Success - API Key", func(t *testing.T) {
+		rec := send(router, "ApiKey gos_1.secret")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"APIKeyID":7`)
+	})
+
+	t.Run("Fail - Missing Credentials", func(t *testing.T) {
+		rec := send(router, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `"code":"authentication_required"`)
+	})
+
+	t.Run("Fail - Invalid Token", func(t *testing.T) {
+		rec := send(router, "Bearer tampered-token")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid token","instance":"/","code":"invalid_token"}`, rec.Body.String())
+	})
+
+	t.Run("Fail - Expired Token", func(t *testing.T) {
+		rec := send(newRouter(stubVerifier{err: auth.ErrTokenExpired}, apiKeys), "Bearer old-token")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"token_expired"`)
+	})
+
+	t.Run("Fail - Invalid API Key", func(t *testing.T) {
+		rec := send(router, "ApiKey gos_1.wrong")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "ApiKey", rec.Header().Get("WWW-Authenticate"))
+		assert.Contains(t, rec.Body.String(), `"code":"invalid_api_key"`)
+	})
+
+	t.Run("Fail - API Key Lookup Error", func(t *testing.T) {
+		rec := send(newRouter(tokens, stubVerifier{err: errors.New("db down")}), "ApiKey gos_1.secret")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "db down")
+	})
+
+	t.Run("Fail - Unsupported Scheme", func(t *testing.T) {
+		rec := send(router, "Basic dXNlcjpwYXNz")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := []byte(strings.Repeat("s", 32))
+	webhooks, err := payment.NewWebhookVerifier(secret)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/webhook", VerifyWebhook(webhooks), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	send := func(body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set(payment.SignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"type":"payment.authorized","payment_id":"ch_1"}`
+
+	t.Run("Success - Signed Body", func(t *testing.T) {
+		rec := send(body, payment.Sign(secret, []byte(body), time.Now()))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("Fail - Tampered Body", func(t *testing.T) {
+		rec := send(`{"type":"payment.authorized","payment_id":"ch_2"}`, payment.Sign(secret, []byte(body), time.Now()))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"invalid_webhook_signature"`)
+	})
+
+	t.Run("Fail - Missing Signature", func(t *testing.T) {
+		rec := send(body, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -1,17 +1,33 @@
 package http
 
 import (
-	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/dto"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// IdempotencyKeyHeader lets clients retry POST /orders without creating duplicate orders.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
 type createOrderRequest struct {
-	UserID int64              `json:"user_id" binding:"required"`
-	Items  []orderItemRequest `json:"items" binding:"required,min=1"`
+	UserID     int64              `json:"user_id"` // Defaults to the calling user; required for API keys. Ordering for others needs the users:act-for-any scope.
+	Currency   string             `json:"currency" binding:"omitempty,len=3,uppercase"`
+	CouponCode string             `json:"coupon_code"`
+	Region     string             `json:"region" binding:"omitempty,uppercase"`
+	Items      []orderItemRequest `json:"items" binding:"required,min=1,dive"`
+
+	// At most one of these may be given.
+	ShippingAddressID int64                 `json:"shipping_address_id"`
+	ShippingAddress   *domain.PostalAddress `json:"shipping_address"`
 }
 
 type orderItemRequest struct {
@@ -21,9 +37,7 @@ type orderItemRequest struct {
 
 func (h *Handler) CreateOrder(c *gin.Context) {
 	var req createOrderRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -34,20 +48,117 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 			Quantity:  item.Quantity,
 		}
 	}
+	userID := req.UserID
+	if userID == 0 {
+		userID = domain.PrincipalFromContext(c.Request.Context()).UserID
+	}
 	input := dto.CreateOrderInput{
-		UserID: req.UserID,
-		Items:  usecaseItems,
+		UserID:     userID,
+		Currency:   req.Currency,
+		CouponCode: req.CouponCode,
+		Region:     req.Region,
+		Items:      usecaseItems,
+
+		ShippingAddressID: req.ShippingAddressID,
+		ShippingAddress:   req.ShippingAddress,
+	}
+
+	idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		c.Error(domain.NewFieldError(domain.ErrInvalidInput, IdempotencyKeyHeader, "must be at most 255 characters"))
+		return
+	}
+
+	var createdOrder *domain.Order
+	var replayed bool
+	var err error
+	if idempotencyKey != "" {
+		createdOrder, replayed, err = h.orderUseCase.CreateOrderWithIdempotencyKey(c.Request.Context(), idempotencyKey, input)
+	} else {
+		createdOrder, err = h.orderUseCase.CreateOrder(c.Request.Context(), input)
+	}
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	createdOrder, err := h.orderUseCase.CreateOrder(c.Request.Context(), input)
+	if replayed {
+		c.Header(IdempotentReplayedHeader, "true")
+	}
+	c.JSON(http.StatusCreated, createdOrder)
+}
+
+func (h *Handler) GetOrderByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUseCase.GetOrderByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrInsufficientStock) {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()}) // 409 Conflict is a good choice for stock issues
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// ListOrders lists the orders of the user in the user_id query parameter,
+// or of the calling user when it is omitted. API keys must give user_id.
+func (h *Handler) ListOrders(c *gin.Context) {
+	userID := domain.PrincipalFromContext(c.Request.Context()).UserID
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || userID <= 0 {
+			c.Error(domain.NewFieldError(domain.ErrInvalidInput, "user_id", "must be a positive integer"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order: " + err.Error()})
+	}
+
+	page, pageSize, ok := parsePage(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdOrder)
+	orders, err := h.orderUseCase.ListOrdersByUser(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": orders})
+}
+
+func (h *Handler) PayOrder(c *gin.Context) {
+	h.transitionOrder(c, domain.StatusPaid)
+}
+
+func (h *Handler) ShipOrder(c *gin.Context) {
+	h.transitionOrder(c, domain.StatusShipped)
+}
+
+func (h *Handler) CompleteOrder(c *gin.Context) {
+	h.transitionOrder(c, domain.StatusCompleted)
+}
+
+// CancelOrder cancels the order and restores the stock of its items.
+func (h *Handler) CancelOrder(c *gin.Context) {
+	h.transitionOrder(c, domain.StatusCancelled)
+}
+
+// transitionOrder moves the order in the path to the given status.
+func (h *Handler) transitionOrder(c *gin.Context, status domain.OrderStatus) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUseCase.TransitionOrder(c.Request.Context(), id, status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
 }
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type paymentWebhookRequest struct {
+	Type      domain.PaymentEvent `json:"type" binding:"required"`
+	PaymentID string              `json:"payment_id" binding:"required"`
+}
+
+// CreatePayment charges a pending order at the payment provider. The order is paid
+// once the provider confirms the payment through PaymentWebhook.
+func (h *Handler) CreatePayment(c *gin.Context) {
+	orderID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentUseCase.CreatePayment(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// PaymentWebhook receives the payment events of the provider. Its signature is
+// checked by VerifyWebhook before.
+func (h *Handler) PaymentWebhook(c *gin.Context) {
+	var req paymentWebhookRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.paymentUseCase.HandlePaymentEvent(c.Request.Context(), req.Type, req.PaymentID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
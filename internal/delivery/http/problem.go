@@ -0,0 +1,242 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of every error response, following RFC 7807. Code is a stable,
+// machine-readable identifier of the error, and Errors lists the invalid fields of
+// the request, if any. ProductIDs lists the unknown products of an order, and
+// Shortages the items that are short of stock.
+type Problem struct {
+	Type       string            `json:"type"`
+	Title      string            `json:"title"`
+	Status     int               `json:"status"`
+	Detail     string            `json:"detail,omitempty"`
+	Instance   string            `json:"instance,omitempty"`
+	Code       string            `json:"code"`
+	Errors     []FieldProblem    `json:"errors,omitempty"`
+	ProductIDs []int64           `json:"product_ids,omitempty"`
+	Shortages  []ShortageProblem `json:"shortages,omitempty"`
+}
+
+// FieldProblem describes why one field of the request is invalid.
+type FieldProblem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ShortageProblem tells how many units of a product were requested and how many
+// are in stock, so that clients can lower the quantity.
+type ShortageProblem struct {
+	ProductID int64 `json:"product_id"`
+	Requested int   `json:"requested"`
+	Available int   `json:"available"`
+}
+
+// kindStatuses maps the kinds of domain errors to the status codes they are reported with.
+var kindStatuses = map[domain.ErrorKind]int{
+	domain.KindInvalid:         http.StatusBadRequest,
+	domain.KindUnauthenticated: http.StatusUnauthorized,
+	domain.KindForbidden:       http.StatusForbidden,
+	domain.KindNotFound:        http.StatusNotFound,
+	domain.KindConflict:        http.StatusConflict,
+	domain.KindUnprocessable:   http.StatusUnprocessableEntity,
+}
+
+var errRouteNotFound = domain.NewError(domain.KindNotFound, "route_not_found", "no such endpoint")
+
+func init() {
+	// Report validation errors with the JSON names of the fields, as clients send them.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// ErrorHandler renders the last error recorded with c.Error as an
+// application/problem+json response, unless a response was already written.
+// Errors of unknown kind are logged and reported as 500 without their details.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last()
+		problem := newProblem(err)
+		if problem.Status == http.StatusInternalServerError {
+			log.Printf("ERROR: %s %s: %v", c.Request.Method, c.Request.URL.Path, err.Err)
+		}
+		problem.Instance = c.Request.URL.Path
+
+		c.Header("Content-Type", ProblemContentType)
+		c.AbortWithStatusJSON(problem.Status, problem)
+	}
+}
+
+// NotFound records that no route matches the request.
+func NotFound(c *gin.Context) {
+	c.Error(errRouteNotFound)
+}
+
+func newProblem(err *gin.Error) Problem {
+	if err.IsType(gin.ErrorTypeBind) {
+		return bindProblem(err.Err)
+	}
+
+	var coded *domain.Error
+	if !errors.As(err.Err, &coded) {
+		return Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+			Detail: "An internal error occurred",
+			Code:   "internal_error",
+		}
+	}
+
+	status, ok := kindStatuses[coded.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Err.Error(),
+		Code:   coded.Code,
+	}
+
+	var fieldErr *domain.FieldError
+	if errors.As(err.Err, &fieldErr) {
+		problem.Errors = []FieldProblem{{Field: fieldErr.Field, Message: fieldErr.Message}}
+	}
+
+	var unknownErr *usecase.UnknownProductsError
+	if errors.As(err.Err, &unknownErr) {
+		problem.ProductIDs = unknownErr.ProductIDs
+	}
+
+	var stockErr *domain.InsufficientStockError
+	if errors.As(err.Err, &stockErr) {
+		for _, s := range stockErr.Shortages {
+			problem.Shortages = append(problem.Shortages, ShortageProblem{ProductID: s.ProductID, Requested: s.Requested, Available: s.Available})
+		}
+	}
+
+	return problem
+}
+
+// bindProblem reports a request body that could not be decoded or failed validation.
+func bindProblem(err error) Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: "The request body is invalid",
+		Code:   "invalid_request",
+	}
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var coded *domain.Error
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			problem.Errors = append(problem.Errors, FieldProblem{Field: fieldPath(fe), Message: validationMessage(fe)})
+		}
+	case errors.As(err, &typeErr):
+		problem.Errors = []FieldProblem{{Field: indexPath(typeErr.Field), Message: "must be of type " + typeErr.Type.String()}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		problem.Detail = "The request body is not valid JSON"
+	case errors.As(err, &coded):
+		// A value the domain rejected while decoding, such as a malformed amount of money.
+		problem.Detail = err.Error()
+		problem.Code = coded.Code
+	}
+
+	return problem
+}
+
+// fieldPath returns the path of an invalid field without the name of the request type,
+// e.g. "items[0].quantity".
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// indexPath writes the array indexes in a path from encoding/json in brackets, like
+// the validator does: "items.0.quantity" becomes "items[0].quantity".
+func indexPath(path string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// validationMessage explains a failed validation rule in words.
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		switch fe.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must contain at least %s item(s)", fe.Param())
+		case reflect.String:
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters long", fe.Param())
+	case "uppercase":
+		return "must be upper case"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type itemRequest struct {
+		Quantity int `json:"quantity" binding:"required,gt=0"`
+	}
+	type request struct {
+		Currency string        `json:"currency" binding:"omitempty,len=3"`
+		Items    []itemRequest `json:"items" binding:"required,min=1,dive"`
+	}
+
+	errNotFound := domain.NewError(domain.KindNotFound, "thing_not_found", "thing not found")
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.NoRoute(NotFound)
+	router.GET("/things/:id", func(c *gin.Context) {
+		if _, ok := parseIDParam(c, "id"); !ok {
+			return
+		}
+		c.Error(errNotFound)
+	})
+	router.GET("/referenced", func(c *gin.Context) {
+		c.Error(domain.Unprocessable(fmt.Errorf("%w: 42", errNotFound)))
+	})
+	router.GET("/address", func(c *gin.Context) {
+		c.Error(domain.PostalAddress{}.Validate())
+	})
+	router.GET("/unknown-products", func(c *gin.Context) {
+		c.Error(&usecase.UnknownProductsError{ProductIDs: []int64{3, 7}})
+	})
+	router.GET("/out-of-stock", func(c *gin.Context) {
+		c.Error(&domain.InsufficientStockError{Shortages: []domain.StockShortage{
+			{ProductID: 1, Requested: 5, Available: 2},
+			{ProductID: 4, Requested: 1, Available: 0},
+		}})
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		c.Error(errors.New("pq: connection refused"))
+	})
+	router.POST("/things", func(c *gin.Context) {
+		var req request
+		if !bindJSON(c, &req) {
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	send := func(method, path, body string) (*httptest.ResponseRecorder, Problem) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var problem Problem
+		if rec.Code >= 400 {
+			assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		}
+		return rec, problem
+	}
+
+	t.Run("should report coded errors with the status of their kind", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/things/7", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, Problem{
+			Type:     "about:blank",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "thing not found",
+			Instance: "/things/7",
+			Code:     "thing_not_found",
+		}, problem)
+	})
+
+	t.Run("should report entities referenced by the input as unprocessable", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/referenced", "")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "thing_not_found", problem.Code)
+		assert.Equal(t, "thing not found: 42", problem.Detail)
+	})
+
+	t.Run("should list the field of a validation error", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/address", "")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_address", problem.Code)
+		assert.Equal(t, []FieldProblem{{Field: "recipient_name", Message: "cannot be empty"}}, problem.Errors)
+	})
+
+	t.Run("should reject malformed path parameters", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/things/abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_input", problem.Code)
+		assert.Equal(t, []FieldProblem{{Field: "id", Message: "must be a positive integer"}}, problem.Errors)
+	})
+
+	t.Run("should list every invalid field of the body by its JSON path", func(t *testing.T) {
+		rec, problem := send(http.MethodPost, "/things", `{"currency": "RUPIAH", "items": [{"quantity": 1}, {"quantity": 0}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_request", problem.Code)
+		assert.ElementsMatch(t, []FieldProblem{
+			{Field: "currency", Message: "must be exactly 3 characters long"},
+			{Field: "items[1].quantity", Message: "is required"},
+		}, problem.Errors)
+	})
+
+	t.Run("should report fields of the wrong type", func(t *testing.T) {
+		rec, problem := send(http.MethodPost, "/things", `{"items": [{"quantity": "two"}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, []FieldProblem{{Field: "items[0].quantity", Message: "must be of type int"}}, problem.Errors)
+	})
+
+	t.Run("should report malformed JSON", func(t *testing.T) {
+		rec, problem := send(http.MethodPost, "/things", `{"items": [`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "The request body is not valid JSON", problem.Detail)
+	})
+
+	t.Run("should list the unknown products of an order", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/unknown-products", "")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "unknown_product", problem.Code)
+		assert.Equal(t, "one or more products not found: 3, 7", problem.Detail)
+		assert.Equal(t, []int64{3, 7}, problem.ProductIDs)
+	})
+
+	t.Run("should list the stock shortages of an order", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/out-of-stock", "")
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "insufficient_stock", problem.Code)
+		assert.Equal(t, []ShortageProblem{
+			{ProductID: 1, Requested: 5, Available: 2},
+			{ProductID: 4, Requested: 1, Available: 0},
+		}, problem.Shortages)
+	})
+
+	t.Run("should hide the details of internal errors", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/internal", "")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal_error", problem.Code)
+		assert.NotContains(t, rec.Body.String(), "pq:")
+	})
+
+	t.Run("should report unknown routes", func(t *testing.T) {
+		rec, problem := send(http.MethodGet, "/nowhere", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "route_not_found", problem.Code)
+	})
+}
@@ -1,43 +1,43 @@
 package http
 
 import (
-	"errors"
 	"net/http"
-	"strconv"
 
+	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/dto"
-	"github.com/elokanugrah/go-order-system/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
 type createProductRequest struct {
-	Name     string  `json:"name" binding:"required"`
-	Price    float64 `json:"price" binding:"required,gt=0"`
-	Quantity int     `json:"quantity" binding:"required,gte=0"`
+	Name        string             `json:"name" binding:"required"`
+	Price       domain.Money       `json:"price"` // validated by the use case, binding tags don't apply to structs
+	TaxCategory domain.TaxCategory `json:"tax_category"`
+	Quantity    int                `json:"quantity" binding:"required,gte=0"`
 }
 
 type updateProductRequest struct {
-	Name     string  `json:"name" binding:"required"`
-	Price    float64 `json:"price" binding:"required,gt=0"`
-	Quantity int     `json:"quantity" binding:"required,gte=0"`
+	Name        string             `json:"name" binding:"required"`
+	Price       domain.Money       `json:"price"` // validated by the use case, binding tags don't apply to structs
+	TaxCategory domain.TaxCategory `json:"tax_category"`
+	Quantity    int                `json:"quantity" binding:"required,gte=0"`
 }
 
 func (h *Handler) CreateProduct(c *gin.Context) {
 	var req createProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	input := dto.CreateProductInput{
-		Name:     req.Name,
-		Price:    req.Price,
-		Quantity: req.Quantity,
+		Name:        req.Name,
+		Price:       req.Price,
+		TaxCategory: req.TaxCategory,
+		Quantity:    req.Quantity,
 	}
 
 	product, err := h.productUseCase.CreateProduct(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -45,20 +45,14 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 }
 
 func (h *Handler) GetProductByID(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID format"})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
 		return
 	}
 
 	product, err := h.productUseCase.GetProductByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, usecase.ErrProductNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		c.Error(err)
 		return
 	}
 
@@ -66,24 +60,14 @@ func (h *Handler) GetProductByID(c *gin.Context) {
 }
 
 func (h *Handler) ListProducts(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("pageSize", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
-		return
-	}
-
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page size"})
+	page, pageSize, ok := parsePage(c)
+	if !ok {
 		return
 	}
 
 	products, err := h.productUseCase.ListProducts(c.Request.Context(), page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list products"})
+		c.Error(err)
 		return
 	}
 
@@ -91,32 +75,26 @@ func (h *Handler) ListProducts(c *gin.Context) {
 }
 
 func (h *Handler) UpdateProduct(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID format"})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
 		return
 	}
 
 	var req updateProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	input := dto.UpdateProductInput{
-		Name:     req.Name,
-		Price:    req.Price,
-		Quantity: req.Quantity,
+		Name:        req.Name,
+		Price:       req.Price,
+		TaxCategory: req.TaxCategory,
+		Quantity:    req.Quantity,
 	}
 
 	product, err := h.productUseCase.UpdateProduct(c.Request.Context(), id, input)
 	if err != nil {
-		if errors.Is(err, usecase.ErrProductNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
+		c.Error(err)
 		return
 	}
 
@@ -124,20 +102,13 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 }
 
 func (h *Handler) DeleteProduct(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID format"})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
 		return
 	}
 
-	err = h.productUseCase.DeleteProduct(c.Request.Context(), id)
-	if err != nil {
-		if errors.Is(err, usecase.ErrProductNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
+	if err := h.productUseCase.DeleteProduct(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
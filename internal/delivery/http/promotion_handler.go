@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type createCouponRequest struct {
+	Code           string            `json:"code" binding:"required"`
+	Type           domain.CouponType `json:"type" binding:"required,oneof=percentage fixed"`
+	Percent        int               `json:"percent" binding:"gte=0,lte=100"`
+	Amount         domain.Money      `json:"amount"`
+	MinSubtotal    domain.Money      `json:"min_subtotal"`
+	ProductIDs     []int64           `json:"product_ids"`
+	MaxUses        int               `json:"max_uses" binding:"gte=0"`
+	MaxUsesPerUser int               `json:"max_uses_per_user" binding:"gte=0"`
+	StartsAt       *time.Time        `json:"starts_at"`
+	EndsAt         *time.Time        `json:"ends_at"`
+}
+
+func (h *Handler) CreateCoupon(c *gin.Context) {
+	var req createCouponRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	coupon, err := h.promotionUseCase.CreateCoupon(c.Request.Context(), dto.CreateCouponInput{
+		Code:           req.Code,
+		Type:           req.Type,
+		Percent:        req.Percent,
+		Amount:         req.Amount,
+		MinSubtotal:    req.MinSubtotal,
+		ProductIDs:     req.ProductIDs,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+func (h *Handler) GetCoupon(c *gin.Context) {
+	coupon, err := h.promotionUseCase.GetCoupon(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type createReturnRequest struct {
+	Reason string              `json:"reason"`
+	Items  []returnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type returnItemRequest struct {
+	ProductID int64 `json:"product_id" binding:"required"`
+	Quantity  int   `json:"quantity" binding:"required,gt=0"`
+}
+
+// CreateReturn requests the return of items of the order in the path.
+func (h *Handler) CreateReturn(c *gin.Context) {
+	orderID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req createReturnRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	items := make([]dto.CreateReturnItemInput, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = dto.CreateReturnItemInput{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	rma, err := h.returnUseCase.RequestReturn(c.Request.Context(), dto.CreateReturnInput{
+		OrderID: orderID,
+		Reason:  req.Reason,
+		Items:   items,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rma)
+}
+
+// ListReturns lists the returns of the order in the path.
+func (h *Handler) ListReturns(c *gin.Context) {
+	orderID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	returns, err := h.returnUseCase.ListReturns(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": returns})
+}
+
+func (h *Handler) GetReturn(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	rma, err := h.returnUseCase.GetReturn(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rma)
+}
+
+func (h *Handler) ApproveReturn(c *gin.Context) {
+	h.transitionReturn(c, h.returnUseCase.ApproveReturn)
+}
+
+func (h *Handler) RejectReturn(c *gin.Context) {
+	h.transitionReturn(c, h.returnUseCase.RejectReturn)
+}
+
+// ReceiveReturn records that the items of the return arrived, restocks and refunds them.
+func (h *Handler) ReceiveReturn(c *gin.Context) {
+	h.transitionReturn(c, h.returnUseCase.ReceiveReturn)
+}
+
+// transitionReturn moves the return in the path to another status with transition.
+func (h *Handler) transitionReturn(c *gin.Context, transition func(ctx context.Context, id int64) (*domain.Return, error)) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	rma, err := transition(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rma)
+}
@@ -2,23 +2,95 @@ package http
 
 import "github.com/gin-gonic/gin"
 
-func SetupRouter(h *Handler) *gin.Engine {
+// SetupRouter registers the API routes. Reading products is public, every other
+// endpoint requires a user token verified by tokens or an API key checked by apiKeys,
+// except for the payment webhook, which must be signed as checked by webhooks.
+// What the caller may do is decided by the access policy of the use cases.
+func SetupRouter(h *Handler, tokens TokenVerifier, apiKeys APIKeyAuthenticator, webhooks WebhookVerifier) *gin.Engine {
 	router := gin.Default()
+	router.Use(CorrelationID(), ErrorHandler())
+	router.NoRoute(NotFound)
+
+	authenticate := Authenticate(tokens, apiKeys)
 
 	api := router.Group("/api/v1")
 	{
 		products := api.Group("/products")
 		{
-			products.POST("/", h.CreateProduct)
+			products.POST("/", authenticate, h.CreateProduct)
 			products.GET("/", h.ListProducts)
 			products.GET("/:id", h.GetProductByID)
-			products.PUT("/:id", h.UpdateProduct)
-			products.DELETE("/:id", h.DeleteProduct)
+			products.PUT("/:id", authenticate, h.UpdateProduct)
+			products.DELETE("/:id", authenticate, h.DeleteProduct)
 		}
 
-		orders := api.Group("/orders")
+		orders := api.Group("/orders", authenticate)
 		{
 			orders.POST("/", h.CreateOrder)
+			orders.GET("/", h.ListOrders)
+			orders.GET("/:id", h.GetOrderByID)
+			orders.POST("/:id/pay", h.PayOrder)
+			orders.POST("/:id/ship", h.ShipOrder)
+			orders.POST("/:id/complete", h.CompleteOrder)
+			orders.POST("/:id/cancel", h.CancelOrder)
+			orders.POST("/:id/payments", h.CreatePayment)
+			orders.POST("/:id/returns", h.CreateReturn)
+			orders.GET("/:id/returns", h.ListReturns)
+			orders.POST("/:id/shipments", h.CreateShipment)
+			orders.GET("/:id/shipments", h.ListShipments)
+		}
+
+		returns := api.Group("/returns", authenticate)
+		{
+			returns.GET("/:id", h.GetReturn)
+			returns.POST("/:id/approve", h.ApproveReturn)
+			returns.POST("/:id/reject", h.RejectReturn)
+			returns.POST("/:id/receive", h.ReceiveReturn)
+		}
+
+		shipments := api.Group("/shipments", authenticate)
+		{
+			shipments.GET("/:id", h.GetShipment)
+			shipments.PUT("/:id/status", h.UpdateShipmentStatus)
+		}
+
+		api.POST("/payments/webhook", VerifyWebhook(webhooks), h.PaymentWebhook)
+
+		carts := api.Group("/carts", authenticate)
+		{
+			carts.POST("/", h.CreateCart)
+			carts.GET("/:id", h.GetCart)
+			carts.POST("/:id/items", h.AddCartItem)
+			carts.PUT("/:id/items/:product_id", h.UpdateCartItem)
+			carts.DELETE("/:id/items/:product_id", h.RemoveCartItem)
+			carts.POST("/:id/checkout", h.CheckoutCart)
+		}
+
+		promotions := api.Group("/promotions", authenticate)
+		{
+			promotions.POST("/coupons", h.CreateCoupon)
+			promotions.GET("/coupons/:code", h.GetCoupon)
+		}
+
+		users := api.Group("/users", authenticate)
+		{
+			users.POST("/", h.CreateUser)
+			users.GET("/", h.ListUsers)
+			users.GET("/:id", h.GetUser)
+			users.PUT("/:id", h.UpdateUser)
+			users.DELETE("/:id", h.DeleteUser)
+			users.POST("/:id/addresses", h.CreateAddress)
+			users.GET("/:id/addresses", h.ListAddresses)
+			users.GET("/:id/addresses/:address_id", h.GetAddress)
+			users.PUT("/:id/addresses/:address_id", h.UpdateAddress)
+			users.DELETE("/:id/addresses/:address_id", h.DeleteAddress)
+		}
+
+		apiKeys := api.Group("/api-keys", authenticate)
+		{
+			apiKeys.POST("/", h.CreateAPIKey)
+			apiKeys.GET("/", h.ListAPIKeys)
+			apiKeys.DELETE("/:id", h.RevokeAPIKey)
 		}
 	}
 
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type createShipmentRequest struct {
+	Carrier        string                `json:"carrier" binding:"required"`
+	TrackingNumber string                `json:"tracking_number" binding:"required"`
+	Items          []shipmentItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type shipmentItemRequest struct {
+	ProductID int64 `json:"product_id" binding:"required"`
+	Quantity  int   `json:"quantity" binding:"required,gt=0"`
+}
+
+type updateShipmentStatusRequest struct {
+	Status domain.ShipmentStatus `json:"status" binding:"required,oneof=in_transit delivered"`
+}
+
+// CreateShipment records that items of the order in the path were handed over to a carrier.
+func (h *Handler) CreateShipment(c *gin.Context) {
+	orderID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req createShipmentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	items := make([]dto.CreateShipmentItemInput, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = dto.CreateShipmentItemInput{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	shipment, err := h.shipmentUseCase.CreateShipment(c.Request.Context(), dto.CreateShipmentInput{
+		OrderID:        orderID,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Items:          items,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, shipment)
+}
+
+// ListShipments lists the shipments of the order in the path.
+func (h *Handler) ListShipments(c *gin.Context) {
+	orderID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	shipments, err := h.shipmentUseCase.ListShipments(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": shipments})
+}
+
+func (h *Handler) GetShipment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.GetShipment(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// UpdateShipmentStatus records the tracking status reported by the carrier.
+func (h *Handler) UpdateShipmentStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req updateShipmentStatusRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	shipment, err := h.shipmentUseCase.UpdateShipmentStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type userRequest struct {
+	Email string `json:"email" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+}
+
+type addressRequest struct {
+	Label string `json:"label"`
+	domain.PostalAddress
+}
+
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req userRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userUseCase.CreateUser(c.Request.Context(), dto.CreateUserInput{Email: req.Email, Name: req.Name})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *Handler) ListUsers(c *gin.Context) {
+	page, pageSize, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.userUseCase.ListUsers(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": users})
+}
+
+func (h *Handler) GetUser(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	user, err := h.userUseCase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) UpdateUser(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req userRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userUseCase.UpdateUser(c.Request.Context(), userID, dto.UpdateUserInput{Email: req.Email, Name: req.Name})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) DeleteUser(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userUseCase.DeleteUser(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) CreateAddress(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req addressRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	address, err := h.userUseCase.CreateAddress(c.Request.Context(), userID, dto.AddressInput{Label: req.Label, Address: req.PostalAddress})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+func (h *Handler) ListAddresses(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	addresses, err := h.userUseCase.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": addresses})
+}
+
+func (h *Handler) GetAddress(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	addressID, ok := parseIDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	address, err := h.userUseCase.GetAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+func (h *Handler) UpdateAddress(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	addressID, ok := parseIDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	var req addressRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	address, err := h.userUseCase.UpdateAddress(c.Request.Context(), userID, addressID, dto.AddressInput{Label: req.Label, Address: req.PostalAddress})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+func (h *Handler) DeleteAddress(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	addressID, ok := parseIDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	if err := h.userUseCase.DeleteAddress(c.Request.Context(), userID, addressID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
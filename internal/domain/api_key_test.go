@@ -0,0 +1,79 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	scopes := []domain.Scope{domain.ScopeReadAnyOrder}
+
+	t.Run("should mint a key of which only the secret hash is kept", func(t *testing.T) {
+		key, plain, err := domain.NewAPIKey(" warehouse ", scopes, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "warehouse", key.Name)
+		assert.True(t, strings.HasPrefix(plain, key.Prefix+"."))
+		assert.NotContains(t, key.SecretHash, strings.TrimPrefix(plain, key.Prefix+"."))
+
+		prefix, secret, err := domain.ParseAPIKey(plain)
+		require.NoError(t, err)
+		assert.Equal(t, key.Prefix, prefix)
+		assert.True(t, key.Matches(secret))
+		assert.False(t, key.Matches(secret+"x"))
+	})
+
+	t.Run("should mint a different key every time", func(t *testing.T) {
+		first, _, err := domain.NewAPIKey("a", scopes, nil)
+		require.NoError(t, err)
+		second, _, err := domain.NewAPIKey("a", scopes, nil)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first.Prefix, second.Prefix)
+		assert.NotEqual(t, first.SecretHash, second.SecretHash)
+	})
+
+	t.Run("should reject invalid definitions", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		_, _, err := domain.NewAPIKey("", scopes, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyDef)
+		_, _, err = domain.NewAPIKey("erp", nil, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyDef)
+		_, _, err = domain.NewAPIKey("erp", []domain.Scope{"orders:delete"}, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidScope)
+		_, _, err = domain.NewAPIKey("erp", scopes, &past)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyDef)
+	})
+}
+
+func TestParseAPIKey_Invalid(t *testing.T) {
+	for _, plain := range []string{"", "gos_abc", "gos_abc.", "other_abc.secret"} {
+		_, _, err := domain.ParseAPIKey(plain)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey, plain)
+	}
+}
+
+func TestAPIKey_Lifecycle(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	key := &domain.APIKey{ID: 7, Scopes: []domain.Scope{domain.ScopeManageProducts}, ExpiresAt: &expiresAt}
+
+	assert.True(t, key.IsActiveAt(now))
+	assert.False(t, key.IsActiveAt(expiresAt))
+
+	assert.True(t, key.Touch(now))
+	assert.False(t, key.Touch(now.Add(30*time.Second)), "uses within a minute are not recorded again")
+	assert.True(t, key.Touch(now.Add(2*time.Minute)))
+
+	assert.Equal(t, &domain.Principal{APIKeyID: 7, Scopes: key.Scopes}, key.Principal())
+
+	require.NoError(t, key.Revoke())
+	assert.False(t, key.IsActiveAt(now))
+	assert.ErrorIs(t, key.Revoke(), domain.ErrAPIKeyRevoked)
+}
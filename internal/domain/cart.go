@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"time"
+)
+
+var (
+	ErrCartCheckedOut   = NewError(KindConflict, "cart_checked_out", "cart is already checked out")
+	ErrCartItemNotFound = NewError(KindNotFound, "cart_item_not_found", "product is not in the cart")
+	ErrEmptyCart        = NewError(KindUnprocessable, "empty_cart", "cart is empty")
+	ErrInvalidQuantity  = NewError(KindInvalid, "invalid_quantity", "quantity must be positive")
+)
+
+// CartStatus defines the possible states of a cart.
+type CartStatus string
+
+const (
+	CartStatusOpen       CartStatus = "open"
+	CartStatusCheckedOut CartStatus = "checked_out"
+)
+
+// Cart is a server-side shopping cart that is turned into an order at checkout.
+type Cart struct {
+	ID        int64
+	UserID    int64
+	Currency  string
+	Items     []CartItem
+	Status    CartStatus
+	OrderID   *int64 // Set once the cart is checked out.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CartItem is a product and the quantity the customer wants of it.
+// Prices are not stored: they are looked up live until checkout.
+type CartItem struct {
+	ID        int64
+	CartID    int64
+	ProductID int64
+	Quantity  int
+}
+
+// NewCart is a constructor function to create a new, empty Cart.
+// An empty currency means DefaultCurrency.
+func NewCart(userID int64, currency string) *Cart {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	now := time.Now()
+	return &Cart{
+		UserID:    userID,
+		Currency:  currency,
+		Status:    CartStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Item returns the cart item of a product, or nil if the product is not in the cart.
+func (c *Cart) Item(productID int64) *CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			return &c.Items[i]
+		}
+	}
+	return nil
+}
+
+// AddItem adds a quantity of a product, on top of any quantity already in the cart.
+// It returns the resulting cart item.
+func (c *Cart) AddItem(productID int64, quantity int) (*CartItem, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	item := c.Item(productID)
+	if item == nil {
+		c.Items = append(c.Items, CartItem{CartID: c.ID, ProductID: productID})
+		item = &c.Items[len(c.Items)-1]
+	}
+	item.Quantity += quantity
+	c.UpdatedAt = time.Now()
+	return item, nil
+}
+
+// SetItemQuantity replaces the quantity of a product already in the cart.
+// It returns the updated cart item.
+func (c *Cart) SetItemQuantity(productID int64, quantity int) (*CartItem, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	item := c.Item(productID)
+	if item == nil {
+		return nil, ErrCartItemNotFound
+	}
+	item.Quantity = quantity
+	c.UpdatedAt = time.Now()
+	return item, nil
+}
+
+// RemoveItem removes a product from the cart.
+func (c *Cart) RemoveItem(productID int64) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			c.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return ErrCartItemNotFound
+}
+
+// CanCheckOut reports why the cart cannot be checked out, if it can't.
+func (c *Cart) CanCheckOut() error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if len(c.Items) == 0 {
+		return ErrEmptyCart
+	}
+	return nil
+}
+
+// CheckOut marks the cart as turned into the given order. A cart can be checked out only once.
+func (c *Cart) CheckOut(orderID int64) error {
+	if err := c.CanCheckOut(); err != nil {
+		return err
+	}
+
+	c.Status = CartStatusCheckedOut
+	c.OrderID = &orderID
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *Cart) checkOpen() error {
+	if c.Status != CartStatusOpen {
+		return ErrCartCheckedOut
+	}
+	return nil
+}
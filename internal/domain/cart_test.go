@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCart_Items(t *testing.T) {
+	t.Run("should accumulate quantities of the same product", func(t *testing.T) {
+		cart := domain.NewCart(123, "")
+
+		_, err := cart.AddItem(1, 2)
+		assert.NoError(t, err)
+		item, err := cart.AddItem(1, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, item.Quantity)
+		assert.Len(t, cart.Items, 1)
+		assert.Equal(t, domain.DefaultCurrency, cart.Currency)
+	})
+
+	t.Run("should update and remove items", func(t *testing.T) {
+		cart := domain.NewCart(123, "USD")
+		_, err := cart.AddItem(1, 2)
+		assert.NoError(t, err)
+		_, err = cart.AddItem(2, 1)
+		assert.NoError(t, err)
+
+		item, err := cart.SetItemQuantity(1, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, item.Quantity)
+
+		assert.NoError(t, cart.RemoveItem(1))
+		assert.Nil(t, cart.Item(1))
+		assert.Len(t, cart.Items, 1)
+	})
+
+	t.Run("should reject unknown products and non-positive quantities", func(t *testing.T) {
+		cart := domain.NewCart(123, "")
+
+		_, err := cart.AddItem(1, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+
+		_, err = cart.SetItemQuantity(1, 2)
+		assert.ErrorIs(t, err, domain.ErrCartItemNotFound)
+
+		assert.ErrorIs(t, cart.RemoveItem(1), domain.ErrCartItemNotFound)
+	})
+}
+
+func TestCart_CheckOut(t *testing.T) {
+	t.Run("should check out once", func(t *testing.T) {
+		cart := domain.NewCart(123, "")
+		_, err := cart.AddItem(1, 2)
+		assert.NoError(t, err)
+
+		assert.NoError(t, cart.CheckOut(42))
+		assert.Equal(t, domain.CartStatusCheckedOut, cart.Status)
+		assert.Equal(t, int64(42), *cart.OrderID)
+
+		assert.ErrorIs(t, cart.CheckOut(43), domain.ErrCartCheckedOut)
+		_, err = cart.AddItem(1, 1)
+		assert.ErrorIs(t, err, domain.ErrCartCheckedOut)
+	})
+
+	t.Run("should not check out an empty cart", func(t *testing.T) {
+		cart := domain.NewCart(123, "")
+
+		assert.ErrorIs(t, cart.CheckOut(42), domain.ErrEmptyCart)
+		assert.Equal(t, domain.CartStatusOpen, cart.Status)
+	})
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCouponNotActive         = NewError(KindUnprocessable, "coupon_not_active", "coupon is not active")
+	ErrCouponMinimumNotMet     = NewError(KindUnprocessable, "coupon_minimum_not_met", "order does not reach the coupon minimum")
+	ErrCouponNotApplicable     = NewError(KindUnprocessable, "coupon_not_applicable", "coupon does not apply to this order")
+	ErrCouponUsageLimitReached = NewError(KindUnprocessable, "coupon_usage_limit_reached", "coupon usage limit reached")
+	ErrInvalidCoupon           = NewError(KindInvalid, "invalid_coupon", "invalid coupon")
+)
+
+// CouponType defines how a coupon computes its discount.
+type CouponType string
+
+const (
+	CouponTypePercentage CouponType = "percentage"
+	CouponTypeFixed      CouponType = "fixed"
+)
+
+// Coupon is a discount code that can be applied to an order.
+type Coupon struct {
+	ID             int64
+	Code           string
+	Type           CouponType
+	Percent        int     // Percentage off for percentage coupons, from 1 to 100.
+	Amount         Money   // Amount off for fixed coupons, in the currency orders must use.
+	MinSubtotal    Money   // Minimum order subtotal; zero for no minimum.
+	ProductIDs     []int64 // Products the discount applies to; empty for the whole order.
+	MaxUses        int     // Total redemptions allowed; zero for unlimited.
+	MaxUsesPerUser int     // Redemptions allowed per user; zero for unlimited.
+	UsedCount      int
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CouponRedemption records the use of a coupon by an order.
+type CouponRedemption struct {
+	ID        int64
+	CouponID  int64
+	UserID    int64
+	OrderID   int64
+	Discount  Money
+	CreatedAt time.Time
+}
+
+// NormalizeCouponCode returns the canonical form of a coupon code, so codes are case-insensitive.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that the coupon definition is consistent.
+func (c *Coupon) Validate() error {
+	if c.Code == "" {
+		return NewFieldError(ErrInvalidCoupon, "code", "cannot be empty")
+	}
+	switch c.Type {
+	case CouponTypePercentage:
+		if c.Percent < 1 || c.Percent > 100 {
+			return NewFieldError(ErrInvalidCoupon, "percent", "must be between 1 and 100")
+		}
+	case CouponTypeFixed:
+		if !c.Amount.IsPositive() {
+			return NewFieldError(ErrInvalidCoupon, "amount", "must be positive")
+		}
+	default:
+		return NewFieldError(ErrInvalidCoupon, "type", fmt.Sprintf("%q is not a known type", c.Type))
+	}
+	if !c.Amount.IsZero() && !c.MinSubtotal.IsZero() && c.Amount.Currency != c.MinSubtotal.Currency {
+		return NewFieldError(ErrInvalidCoupon, "min_subtotal", "must be in the currency of the amount")
+	}
+	if c.MaxUses < 0 {
+		return NewFieldError(ErrInvalidCoupon, "max_uses", "cannot be negative")
+	}
+	if c.MaxUsesPerUser < 0 {
+		return NewFieldError(ErrInvalidCoupon, "max_uses_per_user", "cannot be negative")
+	}
+	if c.StartsAt != nil && c.EndsAt != nil && !c.EndsAt.After(*c.StartsAt) {
+		return NewFieldError(ErrInvalidCoupon, "ends_at", "must be after starts_at")
+	}
+	return nil
+}
+
+// IsActiveAt reports whether the coupon may be used at the given time.
+func (c *Coupon) IsActiveAt(now time.Time) bool {
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return false
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// CheckUsage reports whether the coupon may be redeemed once more by a user
+// who has already redeemed it userUses times.
+func (c *Coupon) CheckUsage(userUses int) error {
+	if c.MaxUses > 0 && c.UsedCount >= c.MaxUses {
+		return ErrCouponUsageLimitReached
+	}
+	if c.MaxUsesPerUser > 0 && userUses >= c.MaxUsesPerUser {
+		return ErrCouponUsageLimitReached
+	}
+	return nil
+}
+
+// Discount computes the discount the coupon grants on an order at the given time,
+// without applying it. Percentage discounts are rounded half up to the minor unit,
+// and no discount exceeds the price of the items it applies to.
+func (c *Coupon) Discount(order *Order, now time.Time) (Money, error) {
+	if !c.IsActiveAt(now) {
+		return Money{}, ErrCouponNotActive
+	}
+
+	currency := order.Subtotal.Currency
+	if !c.MinSubtotal.IsZero() {
+		cmp, err := order.Subtotal.Cmp(c.MinSubtotal)
+		if err != nil {
+			return Money{}, fmt.Errorf("%w: %v", ErrCouponNotApplicable, err)
+		}
+		if cmp < 0 {
+			return Money{}, fmt.Errorf("%w: subtotal must be at least %s", ErrCouponMinimumNotMet, c.MinSubtotal)
+		}
+	}
+
+	eligible := NewMoney(0, currency)
+	for _, item := range order.OrderItems {
+		if !c.appliesTo(item.Product.ID) {
+			continue
+		}
+		var err error
+		eligible, err = eligible.Add(item.PriceAtOrder.Mul(item.Quantity))
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	if eligible.IsZero() {
+		return Money{}, fmt.Errorf("%w: no eligible items", ErrCouponNotApplicable)
+	}
+
+	switch c.Type {
+	case CouponTypePercentage:
+		amount := (eligible.Amount*int64(c.Percent) + 50) / 100
+		return NewMoney(amount, currency), nil
+	case CouponTypeFixed:
+		cmp, err := c.Amount.Cmp(eligible)
+		if err != nil {
+			return Money{}, fmt.Errorf("%w: %v", ErrCouponNotApplicable, err)
+		}
+		if cmp > 0 {
+			return eligible, nil
+		}
+		return c.Amount, nil
+	default:
+		return Money{}, fmt.Errorf("%w: unknown type %q", ErrInvalidCoupon, c.Type)
+	}
+}
+
+func (c *Coupon) appliesTo(productID int64) bool {
+	if len(c.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ProductIDs {
+		if id == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// Redeem records one more use of the coupon by an order.
+func (c *Coupon) Redeem(order *Order) *CouponRedemption {
+	c.UsedCount++
+	c.UpdatedAt = time.Now()
+
+	return &CouponRedemption{
+		CouponID:  c.ID,
+		UserID:    order.UserID,
+		OrderID:   order.ID,
+		Discount:  order.Discount,
+		CreatedAt: c.UpdatedAt,
+	}
+}
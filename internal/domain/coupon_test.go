@@ -0,0 +1,130 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoupon_Discount(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// newOrder returns an order of 2 x 10000 (product 1) and 1 x 5005 (product 2).
+	newOrder := func(t *testing.T) *domain.Order {
+		order, err := domain.NewOrder(123, []domain.OrderItem{
+			{Product: domain.Product{ID: 1}, Quantity: 2, PriceAtOrder: idr("10000")},
+			{Product: domain.Product{ID: 2}, Quantity: 1, PriceAtOrder: idr("5005")},
+		})
+		require.NoError(t, err)
+		return order
+	}
+
+	t.Run("should take a rounded percentage of the subtotal", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "SAVE10", Type: domain.CouponTypePercentage, Percent: 10}
+
+		discount, err := coupon.Discount(newOrder(t), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("2500.50"), discount)
+	})
+
+	t.Run("should only discount the products the coupon is scoped to", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "HALF", Type: domain.CouponTypePercentage, Percent: 50, ProductIDs: []int64{2}}
+
+		discount, err := coupon.Discount(newOrder(t), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("2502.50"), discount)
+	})
+
+	t.Run("should cap a fixed discount at the price of the eligible items", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "FLAT", Type: domain.CouponTypeFixed, Amount: idr("10000"), ProductIDs: []int64{2}}
+
+		discount, err := coupon.Discount(newOrder(t), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("5005"), discount)
+	})
+
+	t.Run("should reject an order below the minimum subtotal", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "BIG", Type: domain.CouponTypeFixed, Amount: idr("1000"), MinSubtotal: idr("50000")}
+
+		_, err := coupon.Discount(newOrder(t), now)
+
+		assert.ErrorIs(t, err, domain.ErrCouponMinimumNotMet)
+	})
+
+	t.Run("should reject an order without eligible products", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "OTHER", Type: domain.CouponTypePercentage, Percent: 10, ProductIDs: []int64{99}}
+
+		_, err := coupon.Discount(newOrder(t), now)
+
+		assert.ErrorIs(t, err, domain.ErrCouponNotApplicable)
+	})
+
+	t.Run("should reject a fixed coupon in another currency", func(t *testing.T) {
+		coupon := &domain.Coupon{Code: "USD5", Type: domain.CouponTypeFixed, Amount: domain.NewMoney(500, "USD")}
+
+		_, err := coupon.Discount(newOrder(t), now)
+
+		assert.ErrorIs(t, err, domain.ErrCouponNotApplicable)
+	})
+
+	t.Run("should only be usable within its validity window", func(t *testing.T) {
+		startsAt := now.Add(-time.Hour)
+		endsAt := now.Add(time.Hour)
+		coupon := &domain.Coupon{Code: "SALE", Type: domain.CouponTypePercentage, Percent: 10, StartsAt: &startsAt, EndsAt: &endsAt}
+
+		_, err := coupon.Discount(newOrder(t), now)
+		assert.NoError(t, err)
+
+		_, err = coupon.Discount(newOrder(t), startsAt.Add(-time.Second))
+		assert.ErrorIs(t, err, domain.ErrCouponNotActive)
+
+		_, err = coupon.Discount(newOrder(t), endsAt)
+		assert.ErrorIs(t, err, domain.ErrCouponNotActive)
+	})
+}
+
+func TestCoupon_CheckUsage(t *testing.T) {
+	coupon := &domain.Coupon{MaxUses: 10, MaxUsesPerUser: 2, UsedCount: 9}
+
+	assert.NoError(t, coupon.CheckUsage(1))
+	assert.ErrorIs(t, coupon.CheckUsage(2), domain.ErrCouponUsageLimitReached)
+
+	coupon.UsedCount = 10
+	assert.ErrorIs(t, coupon.CheckUsage(0), domain.ErrCouponUsageLimitReached)
+
+	unlimited := &domain.Coupon{UsedCount: 1000}
+	assert.NoError(t, unlimited.CheckUsage(1000))
+}
+
+func TestCoupon_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		coupon domain.Coupon
+		valid  bool
+	}{
+		{"percentage", domain.Coupon{Code: "A", Type: domain.CouponTypePercentage, Percent: 15}, true},
+		{"fixed", domain.Coupon{Code: "A", Type: domain.CouponTypeFixed, Amount: idr("5000")}, true},
+		{"missing code", domain.Coupon{Type: domain.CouponTypePercentage, Percent: 15}, false},
+		{"percent above 100", domain.Coupon{Code: "A", Type: domain.CouponTypePercentage, Percent: 101}, false},
+		{"fixed without amount", domain.Coupon{Code: "A", Type: domain.CouponTypeFixed}, false},
+		{"unknown type", domain.Coupon{Code: "A", Type: "bogo"}, false},
+		{"mixed currencies", domain.Coupon{Code: "A", Type: domain.CouponTypeFixed, Amount: idr("5000"), MinSubtotal: domain.NewMoney(1000, "USD")}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.coupon.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidCoupon)
+			}
+		})
+	}
+}
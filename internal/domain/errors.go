@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies errors by what the caller did wrong, independently of the
+// transport. The HTTP API turns every kind into a status code.
+type ErrorKind string
+
+const (
+	KindInvalid         ErrorKind = "invalid"         // The input is malformed or fails validation.
+	KindNotFound        ErrorKind = "not_found"       // The resource the request is about does not exist.
+	KindConflict        ErrorKind = "conflict"        // The resource is in a state that does not allow the request.
+	KindUnprocessable   ErrorKind = "unprocessable"   // The input is well-formed but breaks a business rule.
+	KindUnauthenticated ErrorKind = "unauthenticated" // The caller did not prove who they are.
+	KindForbidden       ErrorKind = "forbidden"       // The caller may not do this.
+)
+
+// ErrInvalidInput is the generic validation error, used with a FieldError when no
+// more specific error applies.
+var ErrInvalidInput = NewError(KindInvalid, "invalid_input", "invalid input")
+
+// Error is an error with a stable, machine-readable code that clients can act on.
+// Sentinel errors are *Error values, so errors.Is keeps working on them after wrapping.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error // Wrapped cause, nil for sentinels.
+}
+
+// NewError returns a coded error, typically to be declared as a sentinel.
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Unprocessable reclassifies err as KindUnprocessable, keeping its code and message.
+// It is for entities that the input of a request refers to, such as the coupon of an
+// order: when one is missing the input is wrong, not the URL of the request.
+// Errors without a code, such as database failures, are returned unchanged.
+func Unprocessable(err error) error {
+	var coded *Error
+	if !errors.As(err, &coded) {
+		return err
+	}
+	return &Error{Kind: KindUnprocessable, Code: coded.Code, Message: err.Error(), Err: err}
+}
+
+// FieldError reports which field of the input made a validation error occur.
+type FieldError struct {
+	Err     error  // The error the field caused, e.g. ErrInvalidAddress.
+	Field   string // Name of the field as the client sent it, e.g. "postal_code".
+	Message string // What is wrong with the field, e.g. "cannot be empty".
+}
+
+// NewFieldError returns an error wrapping err that blames field.
+func NewFieldError(err error, field, message string) *FieldError {
+	return &FieldError{Err: err, Field: field, Message: message}
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %s %s", e.Err, e.Field, e.Message)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// rateDecimals is the precision of exchange rates. It matches the decimal(20, 8)
+// columns used for rates in the database.
+const (
+	rateScale    = 100000000
+	rateDecimals = 8
+)
+
+var ErrInvalidRate = NewError(KindInvalid, "invalid_exchange_rate", "invalid exchange rate")
+
+// Rate is an exact exchange rate with eight fractional digits,
+// so 16250.5 is Rate(1625050000000).
+type Rate int64
+
+// IdentityRate converts an amount into the same value.
+const IdentityRate Rate = rateScale
+
+// ParseRate parses a decimal string such as "16250.5" with at most eight fractional digits.
+func ParseRate(s string) (Rate, error) {
+	scaled, err := parseDecimal(s, rateDecimals)
+	if err != nil || scaled <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return Rate(scaled), nil
+}
+
+// String formats the rate as a decimal string with eight fractional digits.
+func (r Rate) String() string {
+	return formatDecimal(int64(r), rateDecimals)
+}
+
+// MarshalJSON encodes the rate as a decimal string.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or number.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	literal := string(data)
+	if strings.HasPrefix(literal, `"`) {
+		if err := json.Unmarshal(data, &literal); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseRate(literal)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the rate as a decimal string.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for decimal columns.
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidRate, src)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// ExchangeRate is the number of units of To that one unit of From buys.
+type ExchangeRate struct {
+	From      string
+	To        string
+	Rate      Rate
+	UpdatedAt time.Time
+}
+
+// Convert returns the amount expressed in the given currency at the given rate,
+// rounded half away from zero to the nearest minor unit.
+func (m Money) Convert(currency string, rate Rate) Money {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(rate)))
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(rateScale), new(big.Int))
+
+	// Round half away from zero.
+	if new(big.Int).Abs(remainder).Cmp(big.NewInt(rateScale/2)) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+
+	return NewMoney(quotient.Int64(), currency)
+}
@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Run("should parse up to eight decimals", func(t *testing.T) {
+		rate, err := domain.ParseRate("0.00006154")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Rate(6154), rate)
+		assert.Equal(t, "0.00006154", rate.String())
+	})
+
+	for _, input := range []string{"", "0", "-1", "1.000000001", "abc"} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			_, err := domain.ParseRate(input)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidRate)
+		})
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   domain.Money
+		rate     string
+		currency string
+		expected domain.Money
+	}{
+		{"identity", idr("25.50"), "1", "IDR", idr("25.50")},
+		{"into a larger unit", idr("162500"), "0.00006154", "USD", domain.NewMoney(1000, "USD")},
+		{"into a smaller unit", domain.NewMoney(1999, "USD"), "16250.5", "IDR", idr("324847.50")},
+		{"rounds half away from zero", domain.NewMoney(1, "USD"), "0.5", "EUR", domain.NewMoney(1, "EUR")},
+		{"rounds negative amounts symmetrically", domain.NewMoney(-1, "USD"), "0.5", "EUR", domain.NewMoney(-1, "EUR")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := domain.ParseRate(tc.rate)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expected, tc.amount.Convert(tc.currency, rate))
+		})
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	var rate domain.Rate
+
+	assert.NoError(t, json.Unmarshal([]byte(`16250.5`), &rate))
+	body, err := json.Marshal(rate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"16250.50000000"`, string(body))
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// IdempotencyKey records the outcome of a request sent with an Idempotency-Key header,
+// so that retries of the same request return the original response instead of
+// executing it again.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	Response    []byte
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewIdempotencyKey is a constructor function to create a new, not yet completed IdempotencyKey.
+func NewIdempotencyKey(key, requestHash string) *IdempotencyKey {
+	return &IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Complete stores the serialized response of the request.
+func (k *IdempotencyKey) Complete(response []byte) {
+	now := time.Now()
+	k.Response = response
+	k.CompletedAt = &now
+}
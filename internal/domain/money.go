@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts that don't state one.
+const DefaultCurrency = "IDR"
+
+// moneyScale is the number of minor units per major unit. It matches the
+// decimal(10, 2) columns used for every amount in the database.
+const (
+	moneyScale    = 100
+	moneyDecimals = 2
+)
+
+var (
+	ErrInvalidMoney     = NewError(KindInvalid, "invalid_money", "invalid money amount")
+	ErrCurrencyMismatch = NewError(KindUnprocessable, "currency_mismatch", "currency mismatch")
+)
+
+// Money is an exact monetary amount in a single currency.
+// Amount is expressed in minor units (hundredths), so 25.50 IDR is Money{Amount: 2550, Currency: "IDR"}.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney creates an amount from minor units. An empty currency means DefaultCurrency.
+func NewMoney(minorUnits int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: minorUnits, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "25.50" with at most two fractional digits.
+// An empty currency means DefaultCurrency.
+func ParseMoney(s, currency string) (Money, error) {
+	minorUnits, err := parseDecimal(s, moneyDecimals)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	return NewMoney(minorUnits, currency), nil
+}
+
+// parseDecimal parses a decimal string with at most the given number of
+// fractional digits into an integer scaled by 10^decimals.
+func parseDecimal(s string, decimals int) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || len(frac) > decimals || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("malformed decimal %q", s)
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	scaled, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		scaled = -scaled
+	}
+	return scaled, nil
+}
+
+// formatDecimal formats an integer scaled by 10^decimals as a decimal string.
+func formatDecimal(scaled int64, decimals int) string {
+	sign := ""
+	if scaled < 0 {
+		sign = "-"
+		scaled = -scaled
+	}
+	digits := fmt.Sprintf("%0*d", decimals+1, scaled)
+	return sign + digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns the sum of both amounts, which must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of both amounts, which must be in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// Prorate returns the share part/whole of the amount, rounded toward zero to the
+// minor unit. whole must be positive.
+func (m Money) Prorate(part, whole int64) Money {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(part))
+	return Money{Amount: product.Quo(product, big.NewInt(whole)).Int64(), Currency: m.Currency}
+}
+
+// Cmp compares both amounts, which must be in the same currency.
+// It returns -1 if m is less than other, 0 if they are equal and +1 if m is greater.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Decimal formats the amount as a decimal string with two fractional digits, e.g. "25.50".
+func (m Money) Decimal() string {
+	return formatDecimal(m.Amount, moneyDecimals)
+}
+
+// String formats the amount with its currency, e.g. "IDR 25.50".
+func (m Money) String() string {
+	return m.Currency + " " + m.Decimal()
+}
+
+// moneyJSON is the JSON representation of Money. The amount is a string so
+// clients never have to parse it as a float.
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as {"amount": "25.50", "currency": "IDR"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, err := json.Marshal(m.Decimal())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(moneyJSON{Amount: amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts the object produced by MarshalJSON as well as a bare
+// decimal string or number, which are taken to be in DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	currency := ""
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		var obj moneyJSON
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		data, currency = bytes.TrimSpace(obj.Amount), obj.Currency
+	}
+
+	// Read numbers from their literal text rather than through float64.
+	literal := string(data)
+	if strings.HasPrefix(literal, `"`) {
+		if err := json.Unmarshal(data, &literal); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseMoney(literal, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// Scan implements sql.Scanner for decimal columns. The currency is kept if
+// already set and defaults to DefaultCurrency otherwise.
+func (m *Money) Scan(src interface{}) error {
+	var minorUnits int64
+	var err error
+
+	switch v := src.(type) {
+	case []byte:
+		minorUnits, err = parseDecimal(string(v), moneyDecimals)
+	case string:
+		minorUnits, err = parseDecimal(v, moneyDecimals)
+	case int64:
+		minorUnits = v * moneyScale
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidMoney, src)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+	}
+
+	m.Amount = minorUnits
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	return nil
+}
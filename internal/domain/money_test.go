@@ -0,0 +1,149 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idr parses a decimal amount in the default currency, failing loudly on bad test data.
+func idr(amount string) domain.Money {
+	m, err := domain.ParseMoney(amount, domain.DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"25", 2500},
+		{"25.5", 2550},
+		{"25.05", 2505},
+		{"0.01", 1},
+		{"-0.50", -50},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			m, err := domain.ParseMoney(tc.input, "USD")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, m.Amount)
+			assert.Equal(t, "USD", m.Currency)
+		})
+	}
+
+	for _, input := range []string{"", "abc", "1.234", ".5", "1,5", "1e3"} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			_, err := domain.ParseMoney(input, "USD")
+
+			assert.ErrorIs(t, err, domain.ErrInvalidMoney)
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Run("should add, subtract and multiply exactly", func(t *testing.T) {
+		sum, err := idr("0.10").Add(idr("0.20"))
+		assert.NoError(t, err)
+		assert.Equal(t, idr("0.30"), sum)
+
+		diff, err := idr("1").Sub(idr("0.99"))
+		assert.NoError(t, err)
+		assert.Equal(t, idr("0.01"), diff)
+
+		assert.Equal(t, idr("51"), idr("25.5").Mul(2))
+	})
+
+	t.Run("should prorate rounding toward zero", func(t *testing.T) {
+		assert.Equal(t, idr("3.33"), idr("10").Prorate(1, 3))
+		assert.Equal(t, idr("6.66"), idr("10").Prorate(2, 3))
+		assert.Equal(t, idr("10"), idr("10").Prorate(3, 3))
+	})
+
+	t.Run("should compare amounts", func(t *testing.T) {
+		cmp, err := idr("10").Cmp(idr("9.99"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, cmp)
+
+		cmp, err = idr("10").Cmp(idr("10.00"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, cmp)
+	})
+
+	t.Run("should refuse to mix currencies", func(t *testing.T) {
+		usd := domain.NewMoney(100, "USD")
+
+		_, err := idr("1").Add(usd)
+		assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+		_, err = idr("1").Cmp(usd)
+		assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+	})
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Run("should marshal the amount as a string", func(t *testing.T) {
+		body, err := json.Marshal(idr("25.5"))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount": "25.50", "currency": "IDR"}`, string(body))
+	})
+
+	t.Run("should unmarshal objects, strings and numbers", func(t *testing.T) {
+		testCases := map[string]domain.Money{
+			`{"amount": "25.50", "currency": "USD"}`: domain.NewMoney(2550, "USD"),
+			`{"amount": 25.5}`:                       idr("25.5"),
+			`"25.50"`:                                idr("25.5"),
+			`25.5`:                                   idr("25.5"),
+		}
+
+		for input, expected := range testCases {
+			var m domain.Money
+			require.NoError(t, json.Unmarshal([]byte(input), &m), input)
+			assert.Equal(t, expected, m, input)
+		}
+	})
+
+	t.Run("should reject amounts with more than two decimals", func(t *testing.T) {
+		var m domain.Money
+
+		err := json.Unmarshal([]byte(`0.001`), &m)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMoney)
+	})
+}
+
+func TestMoney_Database(t *testing.T) {
+	t.Run("should store the amount as a decimal string", func(t *testing.T) {
+		value, err := idr("1005000").Value()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1005000.00", value)
+	})
+
+	t.Run("should scan decimal columns", func(t *testing.T) {
+		var m domain.Money
+
+		err := m.Scan([]byte("25.50"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("25.5"), m)
+	})
+
+	t.Run("should keep the currency already set", func(t *testing.T) {
+		m := domain.Money{Currency: "USD"}
+
+		err := m.Scan([]byte("25.50"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(2550, "USD"), m)
+	})
+}
@@ -1,11 +1,15 @@
 package domain
 
 import (
-	"errors"
+	"fmt"
 	"time"
 )
 
-var ErrEmptyOrder = errors.New("order must have at least one item")
+var (
+	ErrEmptyOrder              = NewError(KindInvalid, "empty_order", "order must have at least one item")
+	ErrInvalidStatusTransition = NewError(KindConflict, "invalid_status_transition", "invalid order status transition")
+	ErrInvalidDiscount         = NewError(KindUnprocessable, "invalid_discount", "discount must be between zero and the order subtotal")
+)
 
 type OrderStatus string
 
@@ -17,15 +21,41 @@ const (
 	StatusCancelled OrderStatus = "cancelled"
 )
 
+// statusTransitions lists, for every status, the statuses an order may move to next.
+// Statuses without an entry (completed, cancelled) are terminal.
+var statusTransitions = map[OrderStatus][]OrderStatus{
+	StatusPending: {StatusPaid, StatusCancelled},
+	StatusPaid:    {StatusShipped, StatusCancelled},
+	StatusShipped: {StatusCompleted},
+}
+
+// CanTransitionTo reports whether an order in status s may move to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Order represents the core business entity for a customer's order.
 type Order struct {
 	ID          int64
 	UserID      int64
 	OrderItems  []OrderItem
-	TotalAmount float64
-	Status      OrderStatus
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Subtotal    Money  // Sum of the item prices.
+	Discount    Money  // Deducted from Subtotal by the coupon.
+	CouponCode  string // Empty if no coupon was applied.
+	Tax         Money  // Sum of the item taxes.
+	TaxRegion   string // Region whose tax rules were applied.
+	TotalAmount Money  // Subtotal minus Discount plus Tax.
+	// ShippingAddress is a copy of the address taken when the order was placed,
+	// nil if none was given. Editing the saved address does not change it.
+	ShippingAddress *PostalAddress
+	Status          OrderStatus
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // OrderItem represents a single line item within an order.
@@ -34,7 +64,24 @@ type OrderItem struct {
 	OrderID      int64
 	Product      Product
 	Quantity     int
-	PriceAtOrder float64
+	PriceAtOrder Money   // Unit price in the order currency.
+	BasePrice    Money   // Unit price in the product currency at order time.
+	ExchangeRate Rate    // Rate used to convert BasePrice into PriceAtOrder.
+	TaxRate      TaxRate // Rate at which the item is taxed, zero if exempt.
+	Tax          Money   // Tax on the whole line, in the order currency.
+}
+
+// LineTotal returns the price of the whole line before tax.
+func (i OrderItem) LineTotal() Money {
+	return i.PriceAtOrder.Mul(i.Quantity)
+}
+
+// ApplyTaxRate sets the tax rate of the item and computes its tax on the line total,
+// rounded half away from zero to the minor unit.
+func (i *OrderItem) ApplyTaxRate(rate TaxRate) {
+	line := i.LineTotal()
+	i.TaxRate = rate
+	i.Tax = rate.Of(line)
 }
 
 // NewOrder is a constructor function to create a new Order.
@@ -56,29 +103,147 @@ func NewOrder(userID int64, items []OrderItem) (*Order, error) {
 	}
 
 	// Calculate the total amount upon creation.
-	order.CalculateTotalAmount()
+	if err := order.CalculateTotalAmount(); err != nil {
+		return nil, err
+	}
 
 	return order, nil
 }
 
-// CalculateTotalAmount sums up the price of all items in the order.
-func (o *Order) CalculateTotalAmount() {
-	var total float64
+// CalculateTotalAmount sums up the price and the tax of all items in the order into
+// the subtotal and tax, and computes the total as subtotal minus discount plus tax.
+// Tax is charged on item prices before the discount. All items must be priced in
+// the same currency.
+func (o *Order) CalculateTotalAmount() error {
+	currency := DefaultCurrency
+	if len(o.OrderItems) > 0 {
+		currency = o.OrderItems[0].PriceAtOrder.Currency
+	}
+	subtotal := NewMoney(0, currency)
+	tax := NewMoney(0, currency)
+
 	for _, item := range o.OrderItems {
-		total += item.PriceAtOrder * float64(item.Quantity)
+		var err error
+		subtotal, err = subtotal.Add(item.LineTotal())
+		if err != nil {
+			return err
+		}
+		if !item.Tax.IsZero() {
+			tax, err = tax.Add(item.Tax)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	discount := o.Discount
+	if discount.IsZero() {
+		discount = NewMoney(0, currency)
 	}
+	discounted, err := subtotal.Sub(discount)
+	if err != nil {
+		return err
+	}
+	if discount.Amount < 0 || discounted.Amount < 0 {
+		return ErrInvalidDiscount
+	}
+	total, err := discounted.Add(tax)
+	if err != nil {
+		return err
+	}
+
+	o.Subtotal = subtotal
+	o.Discount = discount
+	o.Tax = tax
 	o.TotalAmount = total
+	return nil
+}
+
+// PaidPerItem returns what was paid for every item, in the order of OrderItems: its
+// line total minus its share of the discount, plus its tax. The discount is shared in
+// proportion to the line totals, rounded down, and the minor units left over go to the
+// first items, so the amounts add up to TotalAmount exactly.
+func (o *Order) PaidPerItem() ([]Money, error) {
+	discounted := !o.Discount.IsZero() && o.Subtotal.IsPositive()
+	discounts := make([]Money, len(o.OrderItems))
+	var left int64
+	if discounted {
+		left = o.Discount.Amount
+	}
+	for i, item := range o.OrderItems {
+		discounts[i] = NewMoney(0, item.PriceAtOrder.Currency)
+		if discounted {
+			discounts[i] = o.Discount.Prorate(item.LineTotal().Amount, o.Subtotal.Amount)
+			left -= discounts[i].Amount
+		}
+	}
+	// Fewer minor units are left over than there are items.
+	for i := 0; left > 0; i++ {
+		discounts[i].Amount++
+		left--
+	}
+
+	paid := make([]Money, len(o.OrderItems))
+	for i, item := range o.OrderItems {
+		var err error
+		paid[i], err = item.LineTotal().Sub(discounts[i])
+		if err != nil {
+			return nil, err
+		}
+		if !item.Tax.IsZero() {
+			paid[i], err = paid[i].Add(item.Tax)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return paid, nil
+}
+
+// ApplyDiscount deducts a coupon discount from the order subtotal.
+func (o *Order) ApplyDiscount(couponCode string, discount Money) error {
+	previousCode, previousDiscount := o.CouponCode, o.Discount
+
+	o.CouponCode, o.Discount = couponCode, discount
+	if err := o.CalculateTotalAmount(); err != nil {
+		o.CouponCode, o.Discount = previousCode, previousDiscount
+		return err
+	}
+	return nil
+}
+
+// ApplyTax sets the tax rate of every item, in order, and recalculates the totals.
+// rates must hold one rate per item.
+func (o *Order) ApplyTax(region string, rates []TaxRate) error {
+	if len(rates) != len(o.OrderItems) {
+		return fmt.Errorf("got %d tax rates for %d order items", len(rates), len(o.OrderItems))
+	}
+
+	for i := range o.OrderItems {
+		o.OrderItems[i].ApplyTaxRate(rates[i])
+	}
+	o.TaxRegion = region
+	return o.CalculateTotalAmount()
 }
 
 // AddItem adds a new OrderItem to the order and recalculates the total amount.
-func (o *Order) AddItem(item OrderItem) {
+func (o *Order) AddItem(item OrderItem) error {
 	o.OrderItems = append(o.OrderItems, item)
-	o.CalculateTotalAmount() // Recalculate total after adding an item.
+	if err := o.CalculateTotalAmount(); err != nil { // Recalculate total after adding an item.
+		o.OrderItems = o.OrderItems[:len(o.OrderItems)-1]
+		return err
+	}
 	o.UpdatedAt = time.Now()
+	return nil
 }
 
-// ChangeStatus updates the status of the order.
-func (o *Order) ChangeStatus(newStatus OrderStatus) {
+// ChangeStatus moves the order to a new status, enforcing the allowed transitions.
+// It returns an error wrapping ErrInvalidStatusTransition for illegal moves.
+func (o *Order) ChangeStatus(newStatus OrderStatus) error {
+	if !o.Status.CanTransitionTo(newStatus) {
+		return fmt.Errorf("%w: cannot move order from %s to %s", ErrInvalidStatusTransition, o.Status, newStatus)
+	}
 	o.Status = newStatus
 	o.UpdatedAt = time.Now()
+	return nil
 }
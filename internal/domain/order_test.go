@@ -9,16 +9,16 @@ import (
 )
 
 func TestNewOrder(t *testing.T) {
-	product1 := domain.Product{ID: 1, Price: 10000}
-	product2 := domain.Product{ID: 2, Price: 5000}
+	product1 := domain.Product{ID: 1, Price: idr("10000")}
+	product2 := domain.Product{ID: 2, Price: idr("5000")}
 
 	t.Run("should create a new order successfully with valid items", func(t *testing.T) {
 		items := []domain.OrderItem{
-			{Product: product1, Quantity: 2, PriceAtOrder: 10000}, // 20000
-			{Product: product2, Quantity: 3, PriceAtOrder: 5000},  // 15000
+			{Product: product1, Quantity: 2, PriceAtOrder: idr("10000")}, // 20000
+			{Product: product2, Quantity: 3, PriceAtOrder: idr("5000")},  // 15000
 		}
 		userID := int64(123)
-		expectedTotal := float64(35000)
+		expectedTotal := idr("35000")
 
 		// Act
 		order, err := domain.NewOrder(userID, items)
@@ -50,40 +50,125 @@ func TestNewOrder(t *testing.T) {
 func TestOrder_CalculateTotalAmount(t *testing.T) {
 	order := &domain.Order{
 		OrderItems: []domain.OrderItem{
-			{PriceAtOrder: 25.5, Quantity: 2}, // 51.0
-			{PriceAtOrder: 10.0, Quantity: 5}, // 50.0
+			{PriceAtOrder: idr("25.5"), Quantity: 2}, // 51.00
+			{PriceAtOrder: idr("0.1"), Quantity: 3},  // 0.30, which float64 can't represent exactly
 		},
 	}
-	expectedTotal := 101.0
+	expectedTotal := idr("51.30")
 
 	// Act
-	order.CalculateTotalAmount()
+	err := order.CalculateTotalAmount()
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Equal(t, expectedTotal, order.TotalAmount)
 }
 
+func TestOrder_ApplyDiscount(t *testing.T) {
+	order, err := domain.NewOrder(123, []domain.OrderItem{{PriceAtOrder: idr("10000"), Quantity: 2}})
+	assert.NoError(t, err)
+
+	t.Run("should deduct the discount from the subtotal", func(t *testing.T) {
+		err := order.ApplyDiscount("SAVE10", idr("2000"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("20000"), order.Subtotal)
+		assert.Equal(t, idr("2000"), order.Discount)
+		assert.Equal(t, idr("18000"), order.TotalAmount)
+		assert.Equal(t, "SAVE10", order.CouponCode)
+	})
+
+	t.Run("should keep the previous discount when the new one exceeds the subtotal", func(t *testing.T) {
+		err := order.ApplyDiscount("TOOBIG", idr("20000.01"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDiscount)
+		assert.Equal(t, idr("2000"), order.Discount)
+		assert.Equal(t, idr("18000"), order.TotalAmount)
+		assert.Equal(t, "SAVE10", order.CouponCode)
+	})
+}
+
+func TestOrder_ApplyTax(t *testing.T) {
+	order, err := domain.NewOrder(123, []domain.OrderItem{
+		{PriceAtOrder: idr("10000"), Quantity: 3}, // 30000, taxed at 11%
+		{PriceAtOrder: idr("5000"), Quantity: 1},  // 5000, exempt
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, order.ApplyDiscount("SAVE", idr("1000")))
+
+	standard, err := domain.ParseTaxRate("0.11")
+	assert.NoError(t, err)
+
+	err = order.ApplyTax("ID", []domain.TaxRate{standard, 0})
+
+	assert.NoError(t, err)
+	assert.Equal(t, idr("3300"), order.OrderItems[0].Tax)
+	assert.Equal(t, idr("0"), order.OrderItems[1].Tax)
+	assert.Equal(t, idr("35000"), order.Subtotal)
+	assert.Equal(t, idr("3300"), order.Tax)
+	assert.Equal(t, idr("37300"), order.TotalAmount) // 35000 - 1000 + 3300
+	assert.Equal(t, "ID", order.TaxRegion)
+
+	assert.Error(t, order.ApplyTax("ID", []domain.TaxRate{standard}))
+}
+
+func TestOrder_PaidPerItem(t *testing.T) {
+	order, err := domain.NewOrder(123, []domain.OrderItem{
+		{PriceAtOrder: idr("10"), Quantity: 1},
+		{PriceAtOrder: idr("10"), Quantity: 1},
+		{PriceAtOrder: idr("10"), Quantity: 1},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, order.ApplyDiscount("SAVE", idr("1")))
+
+	paid, err := order.PaidPerItem()
+
+	// The discount of 1.00 is shared as 0.34, 0.33 and 0.33.
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Money{idr("9.66"), idr("9.67"), idr("9.67")}, paid)
+}
+
+func TestTaxRate(t *testing.T) {
+	t.Run("should accept zero but not negative rates", func(t *testing.T) {
+		zero, err := domain.ParseTaxRate("0")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TaxRate(0), zero)
+
+		_, err = domain.ParseTaxRate("-0.1")
+		assert.ErrorIs(t, err, domain.ErrInvalidTaxRate)
+	})
+
+	t.Run("should round the tax half away from zero", func(t *testing.T) {
+		rate, err := domain.ParseTaxRate("0.11")
+		assert.NoError(t, err)
+
+		assert.Equal(t, idr("0.56"), rate.Of(idr("5.05"))) // 0.5555
+		assert.Equal(t, idr("0.06"), rate.Of(idr("0.50"))) // 0.055
+	})
+}
+
 func TestOrder_AddItem(t *testing.T) {
 	// Create an initial order
 	order := &domain.Order{
 		OrderItems: []domain.OrderItem{
-			{Product: domain.Product{ID: 1}, PriceAtOrder: 100, Quantity: 1}, // Total = 100
+			{Product: domain.Product{ID: 1}, PriceAtOrder: idr("100"), Quantity: 1}, // Total = 100
 		},
-		TotalAmount: 100,
+		TotalAmount: idr("100"),
 	}
 
 	// Define the new item to add
 	newItem := domain.OrderItem{
 		Product:      domain.Product{ID: 2},
-		PriceAtOrder: 50,
+		PriceAtOrder: idr("50"),
 		Quantity:     2, // Value = 100
 	}
-	expectedTotal := 200.0
+	expectedTotal := idr("200")
 
 	// Act
-	order.AddItem(newItem)
+	err := order.AddItem(newItem)
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Len(t, order.OrderItems, 2)                // Check if item count is now 2
 	assert.Equal(t, expectedTotal, order.TotalAmount) // Check if total amount was recalculated
 }
@@ -100,9 +185,48 @@ func TestOrder_ChangeStatus(t *testing.T) {
 	// Act
 	// We wait for a moment to ensure the timestamp will be different
 	time.Sleep(1 * time.Millisecond)
-	order.ChangeStatus(domain.StatusPaid)
+	err := order.ChangeStatus(domain.StatusPaid)
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Equal(t, domain.StatusPaid, order.Status)      // Check if status is updated
 	assert.NotEqual(t, initialUpdatedAt, order.UpdatedAt) // Check if UpdatedAt was modified
 }
+
+func TestOrder_ChangeStatus_Transitions(t *testing.T) {
+	tests := []struct {
+		from    domain.OrderStatus
+		to      domain.OrderStatus
+		allowed bool
+	}{
+		{domain.StatusPending, domain.StatusPaid, true},
+		{domain.StatusPending, domain.StatusCancelled, true},
+		{domain.StatusPaid, domain.StatusShipped, true},
+		{domain.StatusPaid, domain.StatusCancelled, true},
+		{domain.StatusShipped, domain.StatusCompleted, true},
+		{domain.StatusPending, domain.StatusCompleted, false},
+		{domain.StatusPending, domain.StatusShipped, false},
+		{domain.StatusShipped, domain.StatusCancelled, false},
+		{domain.StatusCancelled, domain.StatusPaid, false},
+		{domain.StatusCompleted, domain.StatusPending, false},
+		{domain.StatusPaid, domain.StatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			order := &domain.Order{Status: tt.from}
+
+			// Act
+			err := order.ChangeStatus(tt.to)
+
+			// Assert
+			if tt.allowed {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.to, order.Status)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+				assert.Equal(t, tt.from, order.Status) // The status must not change
+			}
+		})
+	}
+}
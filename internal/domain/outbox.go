@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// OutboxMessage is an event waiting to be published to the message broker.
+// It is stored in the same transaction as the change that produced it, so
+// events are never lost when the broker is unavailable.
+type OutboxMessage struct {
+	ID            int64
+	QueueName     string
+	Payload       []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+// NewOutboxMessage is a constructor function to create a new OutboxMessage
+// that is ready to be published immediately.
+func NewOutboxMessage(queueName string, payload []byte) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		QueueName:     queueName,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// MarkSent records that the message has been published successfully.
+func (m *OutboxMessage) MarkSent() {
+	now := time.Now()
+	m.SentAt = &now
+	m.LastError = ""
+}
+
+// MarkFailed records a failed publish attempt and schedules the next one.
+func (m *OutboxMessage) MarkFailed(err error, nextAttemptAt time.Time) {
+	m.Attempts++
+	m.LastError = err.Error()
+	m.NextAttemptAt = nextAttemptAt
+}
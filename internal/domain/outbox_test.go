@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutboxMessage(t *testing.T) {
+	// Act
+	msg := domain.NewOutboxMessage("orders.created", []byte(`{"order_id":1}`))
+
+	// Assert
+	assert.Equal(t, "orders.created", msg.QueueName)
+	assert.Zero(t, msg.Attempts)
+	assert.Nil(t, msg.SentAt)                            // Not sent yet
+	assert.False(t, msg.NextAttemptAt.After(time.Now())) // Due immediately
+}
+
+func TestOutboxMessage_MarkFailedAndSent(t *testing.T) {
+	msg := domain.NewOutboxMessage("orders.created", []byte(`{}`))
+	retryAt := time.Now().Add(time.Minute)
+
+	// Act
+	msg.MarkFailed(errors.New("connection refused"), retryAt)
+
+	// Assert
+	assert.Equal(t, 1, msg.Attempts)
+	assert.Equal(t, "connection refused", msg.LastError)
+	assert.Equal(t, retryAt, msg.NextAttemptAt)
+
+	// Act
+	msg.MarkSent()
+
+	// Assert
+	assert.NotNil(t, msg.SentAt)
+	assert.Empty(t, msg.LastError)
+	assert.Equal(t, 1, msg.Attempts) // Attempts are kept for diagnostics
+}
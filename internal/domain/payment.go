@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrOrderNotPayable          = NewError(KindConflict, "order_not_payable", "only pending orders can be paid")
+	ErrInvalidPaymentTransition = NewError(KindConflict, "invalid_payment_transition", "invalid payment status transition")
+	ErrUnsupportedPaymentEvent  = NewError(KindInvalid, "unsupported_payment_event", "unsupported payment event")
+	ErrRefundExceedsPayment     = NewError(KindUnprocessable, "refund_exceeds_payment", "refund exceeds the amount left on the payment")
+)
+
+type PaymentStatus string
+
+const (
+	// PaymentPending payments were charged at the provider and wait for the customer
+	// to authorize them.
+	PaymentPending PaymentStatus = "pending"
+	// PaymentCaptured payments were authorized and their amount was taken.
+	PaymentCaptured PaymentStatus = "captured"
+	// PaymentFailed payments were declined.
+	PaymentFailed PaymentStatus = "failed"
+	// PaymentVoided payments were authorized for an order that could no longer be
+	// paid, so their authorization was released instead of captured.
+	PaymentVoided PaymentStatus = "voided"
+)
+
+// PaymentEvent is what a payment provider reports about a payment in a webhook.
+type PaymentEvent string
+
+const (
+	PaymentEventAuthorized PaymentEvent = "payment.authorized"
+	PaymentEventFailed     PaymentEvent = "payment.failed"
+)
+
+// IsValid reports whether the event is one the provider may send.
+func (e PaymentEvent) IsValid() bool {
+	return e == PaymentEventAuthorized || e == PaymentEventFailed
+}
+
+// Payment is a charge of the total amount of an order at a payment provider.
+// An order may have several payments, e.g. when the first attempt was declined,
+// but at most one of them is captured.
+type Payment struct {
+	ID                int64
+	OrderID           int64
+	Provider          string // Name of the payment provider.
+	ProviderPaymentID string // ID of the charge at the provider, unique per provider.
+	Amount            Money
+	Refunded          Money // Part of Amount that was paid back.
+	Status            PaymentStatus
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewPayment returns a pending payment of an order, charged at the provider as providerPaymentID.
+func NewPayment(orderID int64, provider, providerPaymentID string, amount Money) *Payment {
+	now := time.Now()
+	return &Payment{
+		OrderID:           orderID,
+		Provider:          provider,
+		ProviderPaymentID: providerPaymentID,
+		Amount:            amount,
+		Refunded:          NewMoney(0, amount.Currency),
+		Status:            PaymentPending,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// Capture records that the amount of a pending payment was taken.
+func (p *Payment) Capture() error {
+	return p.changeStatus(PaymentCaptured)
+}
+
+// Fail records that a pending payment was declined.
+func (p *Payment) Fail() error {
+	return p.changeStatus(PaymentFailed)
+}
+
+// Void records that the authorization of a pending payment was released.
+func (p *Payment) Void() error {
+	return p.changeStatus(PaymentVoided)
+}
+
+// Refund records that amount of a captured payment was paid back. All refunds of a
+// payment together may not exceed its amount.
+func (p *Payment) Refund(amount Money) error {
+	if p.Status != PaymentCaptured {
+		return fmt.Errorf("%w: cannot refund a %s payment", ErrInvalidPaymentTransition, p.Status)
+	}
+	refunded, err := p.Refunded.Add(amount)
+	if err != nil {
+		return err
+	}
+	if cmp, err := refunded.Cmp(p.Amount); err != nil || cmp > 0 {
+		return ErrRefundExceedsPayment
+	}
+	p.Refunded = refunded
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Refundable returns the part of the payment that was not paid back yet.
+func (p *Payment) Refundable() (Money, error) {
+	return p.Amount.Sub(p.Refunded)
+}
+
+func (p *Payment) changeStatus(status PaymentStatus) error {
+	if p.Status != PaymentPending {
+		return ErrInvalidPaymentTransition
+	}
+	p.Status = status
+	p.UpdatedAt = time.Now()
+	return nil
+}
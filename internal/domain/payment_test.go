@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayment_Transitions(t *testing.T) {
+	amount := domain.NewMoney(2500000, domain.DefaultCurrency)
+
+	t.Run("should start pending", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+
+		assert.Equal(t, domain.PaymentPending, payment.Status)
+		assert.Equal(t, int64(7), payment.OrderID)
+		assert.Equal(t, amount, payment.Amount)
+	})
+
+	t.Run("should capture a pending payment", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+
+		assert.NoError(t, payment.Capture())
+		assert.Equal(t, domain.PaymentCaptured, payment.Status)
+	})
+
+	t.Run("should fail a pending payment", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+
+		assert.NoError(t, payment.Fail())
+		assert.Equal(t, domain.PaymentFailed, payment.Status)
+	})
+
+	t.Run("should void a pending payment", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+
+		assert.NoError(t, payment.Void())
+		assert.Equal(t, domain.PaymentVoided, payment.Status)
+	})
+
+	t.Run("should not change a payment that is no longer pending", func(t *testing.T) {
+		captured := domain.NewPayment(7, "fake", "ch_1", amount)
+		assert.NoError(t, captured.Capture())
+		failed := domain.NewPayment(7, "fake", "ch_2", amount)
+		assert.NoError(t, failed.Fail())
+
+		assert.ErrorIs(t, captured.Fail(), domain.ErrInvalidPaymentTransition)
+		assert.ErrorIs(t, captured.Capture(), domain.ErrInvalidPaymentTransition)
+		assert.ErrorIs(t, failed.Capture(), domain.ErrInvalidPaymentTransition)
+		assert.ErrorIs(t, captured.Void(), domain.ErrInvalidPaymentTransition)
+		assert.Equal(t, domain.PaymentCaptured, captured.Status)
+		assert.Equal(t, domain.PaymentFailed, failed.Status)
+	})
+}
+
+func TestPayment_Refund(t *testing.T) {
+	amount := domain.NewMoney(2500000, domain.DefaultCurrency)
+
+	t.Run("should refund parts of a captured payment up to its amount", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+		assert.NoError(t, payment.Capture())
+
+		assert.NoError(t, payment.Refund(domain.NewMoney(1500000, domain.DefaultCurrency)))
+		assert.NoError(t, payment.Refund(domain.NewMoney(1000000, domain.DefaultCurrency)))
+		assert.Equal(t, amount, payment.Refunded)
+
+		assert.ErrorIs(t, payment.Refund(domain.NewMoney(1, domain.DefaultCurrency)), domain.ErrRefundExceedsPayment)
+		assert.Equal(t, amount, payment.Refunded)
+	})
+
+	t.Run("should only refund captured payments", func(t *testing.T) {
+		payment := domain.NewPayment(7, "fake", "ch_1", amount)
+
+		assert.ErrorIs(t, payment.Refund(amount), domain.ErrInvalidPaymentTransition)
+		assert.True(t, payment.Refunded.IsZero())
+	})
+}
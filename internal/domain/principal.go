@@ -0,0 +1,55 @@
+package domain
+
+import "context"
+
+// Role grants a user a fixed set of scopes.
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleStaff    Role = "staff"
+	RoleAdmin    Role = "admin"
+)
+
+// Principal is the authenticated caller of an operation: either a user,
+// authenticated with a token, or a machine client, authenticated with an API key.
+type Principal struct {
+	UserID   int64 // Zero for API keys.
+	APIKeyID int64 // Zero for users.
+	Roles    []Role
+	Scopes   []Scope // Granted to API keys; users get theirs from their roles.
+}
+
+// HasRole reports whether the principal was granted the role.
+func (p *Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the principal was granted the scope directly.
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey is the key used to store the principal in the context.
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in the context, or nil if the caller is anonymous.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
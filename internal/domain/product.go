@@ -2,18 +2,49 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
-var ErrInsufficientStock = errors.New("insufficient product stock")
+var (
+	ErrInsufficientStock = NewError(KindConflict, "insufficient_stock", "insufficient product stock")
+	ErrInvalidProduct    = NewError(KindInvalid, "invalid_product", "invalid product")
+)
+
+// StockShortage describes a requested item whose product has too few units in stock.
+type StockShortage struct {
+	ProductID int64
+	Requested int
+	Available int
+}
+
+// InsufficientStockError lists every item of a request that the stock cannot cover.
+// It wraps ErrInsufficientStock.
+type InsufficientStockError struct {
+	Shortages []StockShortage
+}
+
+func (e *InsufficientStockError) Error() string {
+	parts := make([]string, len(e.Shortages))
+	for i, s := range e.Shortages {
+		parts[i] = fmt.Sprintf("product %d (requested %d, available %d)", s.ProductID, s.Requested, s.Available)
+	}
+	return fmt.Sprintf("%v: %s", ErrInsufficientStock, strings.Join(parts, ", "))
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
 
 type Product struct {
-	ID        int64
-	Name      string
-	Price     float64
-	Quantity  int
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	Name        string
+	Price       Money
+	TaxCategory TaxCategory
+	Quantity    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // IsStockAvailable checks if the current stock is sufficient for the requested quantity.
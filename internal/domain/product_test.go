@@ -106,3 +106,13 @@ func TestProduct_IncreaseStock(t *testing.T) {
 		assert.Equal(t, "amount to increase must be positive", err.Error())
 	})
 }
+
+func TestInsufficientStockError(t *testing.T) {
+	err := &domain.InsufficientStockError{Shortages: []domain.StockShortage{
+		{ProductID: 1, Requested: 5, Available: 2},
+		{ProductID: 4, Requested: 1, Available: 0},
+	}}
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Equal(t, "insufficient product stock: product 1 (requested 5, available 2), product 4 (requested 1, available 0)", err.Error())
+}
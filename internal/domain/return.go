@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrOrderNotReturnable      = NewError(KindConflict, "order_not_returnable", "only paid, shipped or completed orders can be returned")
+	ErrInvalidReturn           = NewError(KindInvalid, "invalid_return", "invalid return")
+	ErrInvalidReturnTransition = NewError(KindConflict, "invalid_return_transition", "invalid return status transition")
+)
+
+type ReturnStatus string
+
+const (
+	ReturnRequested ReturnStatus = "requested"
+	ReturnApproved  ReturnStatus = "approved"
+	ReturnRejected  ReturnStatus = "rejected"
+	// ReturnReceived returns were sent back by the customer, restocked and refunded.
+	ReturnReceived ReturnStatus = "received"
+)
+
+// returnTransitions lists, for every status, the statuses a return may move to next.
+var returnTransitions = map[ReturnStatus][]ReturnStatus{
+	ReturnRequested: {ReturnApproved, ReturnRejected},
+	ReturnApproved:  {ReturnReceived},
+}
+
+// Return is a request of a customer to send back items of an order (an RMA).
+// Staff approve or reject it, and once the items are received they are restocked
+// and their price is refunded.
+type Return struct {
+	ID           int64
+	OrderID      int64
+	UserID       int64
+	Reason       string
+	Items        []ReturnItem
+	Status       ReturnStatus
+	RefundAmount Money  // What was paid for the items, see NewReturn.
+	RefundID     string // ID of the refund at the payment provider, set once the refund went through.
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ReturnItem is a quantity of an ordered product to send back.
+type ReturnItem struct {
+	ID           int64
+	ReturnID     int64
+	ProductID    int64
+	Quantity     int
+	PriceAtOrder Money // Unit price paid for the product, in the order currency.
+}
+
+// NewReturn requests the return of items of an order. Lines of the same product are
+// merged, and no product may be returned more often than it was ordered, counting
+// the quantities in returned, as computed by ReturnedQuantities. Only ProductID and
+// Quantity of items are used. The refund is the share of the returned units in what
+// was paid for their order items, as computed by Order.PaidPerItem, so it includes
+// their part of the discount and tax; it is rounded down, so the refunds of an order
+// never add up to more than its total.
+func NewReturn(order *Order, items []ReturnItem, reason string, returned map[int64]int) (*Return, error) {
+	if order.Status != StatusPaid && order.Status != StatusShipped && order.Status != StatusCompleted {
+		return nil, ErrOrderNotReturnable
+	}
+	if len(items) == 0 {
+		return nil, NewFieldError(ErrInvalidReturn, "items", "must contain at least one item")
+	}
+
+	paid, err := order.PaidPerItem()
+	if err != nil {
+		return nil, err
+	}
+	ordered := make(map[int64]int, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		ordered[item.Product.ID] = i
+	}
+
+	rma := &Return{
+		OrderID:      order.ID,
+		UserID:       order.UserID,
+		Reason:       reason,
+		Status:       ReturnRequested,
+		RefundAmount: NewMoney(0, order.TotalAmount.Currency),
+	}
+	positions := make(map[int64]int)
+	for i, item := range items {
+		index, ok := ordered[item.ProductID]
+		if !ok {
+			return nil, NewFieldError(ErrInvalidReturn, fmt.Sprintf("items[%d].product_id", i), "is not part of the order")
+		}
+		if item.Quantity <= 0 {
+			return nil, NewFieldError(ErrInvalidQuantity, fmt.Sprintf("items[%d].quantity", i), "must be positive")
+		}
+
+		orderItem := order.OrderItems[index]
+		pos, ok := positions[item.ProductID]
+		if !ok {
+			pos = len(rma.Items)
+			positions[item.ProductID] = pos
+			rma.Items = append(rma.Items, ReturnItem{ProductID: item.ProductID, PriceAtOrder: orderItem.PriceAtOrder})
+		}
+		rma.Items[pos].Quantity += item.Quantity
+
+		if remaining := orderItem.Quantity - returned[item.ProductID]; rma.Items[pos].Quantity > remaining {
+			return nil, NewFieldError(ErrInvalidReturn, fmt.Sprintf("items[%d].quantity", i), fmt.Sprintf("exceeds the %d unit(s) that can still be returned", remaining))
+		}
+	}
+
+	for _, item := range rma.Items {
+		index := ordered[item.ProductID]
+		share := paid[index].Prorate(int64(item.Quantity), int64(order.OrderItems[index].Quantity))
+		rma.RefundAmount, err = rma.RefundAmount.Add(share)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	rma.CreatedAt = now
+	rma.UpdatedAt = now
+	return rma, nil
+}
+
+// ReturnedQuantities sums the quantities of every product in returns that were not rejected.
+func ReturnedQuantities(returns []Return) map[int64]int {
+	returned := make(map[int64]int)
+	for _, rma := range returns {
+		if rma.Status == ReturnRejected {
+			continue
+		}
+		for _, item := range rma.Items {
+			returned[item.ProductID] += item.Quantity
+		}
+	}
+	return returned
+}
+
+// Approve accepts a requested return, so the customer may send the items back.
+func (r *Return) Approve() error {
+	return r.changeStatus(ReturnApproved)
+}
+
+// Reject declines a requested return.
+func (r *Return) Reject() error {
+	return r.changeStatus(ReturnRejected)
+}
+
+// CanReceive reports an error if the items of the return may not be received yet.
+func (r *Return) CanReceive() error {
+	if !r.Status.canTransitionTo(ReturnReceived) {
+		return fmt.Errorf("%w: cannot move return from %s to %s", ErrInvalidReturnTransition, r.Status, ReturnReceived)
+	}
+	return nil
+}
+
+// Receive records that the items of an approved return arrived and that amount is
+// refunded. The refund is sent to the payment provider afterwards, see RecordRefund.
+func (r *Return) Receive(amount Money) error {
+	if err := r.changeStatus(ReturnReceived); err != nil {
+		return err
+	}
+	r.RefundAmount = amount
+	return nil
+}
+
+// RefundPending reports whether the return was received but its refund did not go
+// through at the payment provider yet.
+func (r *Return) RefundPending() bool {
+	return r.Status == ReturnReceived && r.RefundID == ""
+}
+
+// RecordRefund records the ID of the refund of a received return at the payment provider.
+func (r *Return) RecordRefund(refundID string) error {
+	if !r.RefundPending() {
+		return fmt.Errorf("%w: return %d has no pending refund", ErrInvalidReturnTransition, r.ID)
+	}
+	r.RefundID = refundID
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *Return) changeStatus(status ReturnStatus) error {
+	if !r.Status.canTransitionTo(status) {
+		return fmt.Errorf("%w: cannot move return from %s to %s", ErrInvalidReturnTransition, r.Status, status)
+	}
+	r.Status = status
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s ReturnStatus) canTransitionTo(next ReturnStatus) bool {
+	for _, allowed := range returnTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReturn(t *testing.T) {
+	paidOrder := func() *domain.Order {
+		return &domain.Order{
+			ID:          10,
+			UserID:      123,
+			Status:      domain.StatusPaid,
+			TotalAmount: domain.NewMoney(2000000, domain.DefaultCurrency),
+			OrderItems: []domain.OrderItem{
+				{Product: domain.Product{ID: 1}, Quantity: 2, PriceAtOrder: domain.NewMoney(750000, domain.DefaultCurrency)},
+				{Product: domain.Product{ID: 2}, Quantity: 1, PriceAtOrder: domain.NewMoney(500000, domain.DefaultCurrency)},
+			},
+		}
+	}
+
+	t.Run("should merge lines and refund the price paid", func(t *testing.T) {
+		items := []domain.ReturnItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}}
+
+		rma, err := domain.NewReturn(paidOrder(), items, "damaged", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnRequested, rma.Status)
+		assert.Equal(t, int64(123), rma.UserID)
+		assert.Equal(t, []domain.ReturnItem{
+			{ProductID: 1, Quantity: 2, PriceAtOrder: domain.NewMoney(750000, domain.DefaultCurrency)},
+			{ProductID: 2, Quantity: 1, PriceAtOrder: domain.NewMoney(500000, domain.DefaultCurrency)},
+		}, rma.Items)
+		assert.Equal(t, domain.NewMoney(2000000, domain.DefaultCurrency), rma.RefundAmount)
+	})
+
+	t.Run("should refund the share of the discount and tax of a discounted order", func(t *testing.T) {
+		order := paidOrder()
+		standard, err := domain.ParseTaxRate("0.10")
+		require.NoError(t, err)
+		require.NoError(t, order.ApplyTax("ID", []domain.TaxRate{standard, 0}))
+		require.NoError(t, order.ApplyDiscount("SAVE10", domain.NewMoney(200000, domain.DefaultCurrency)))
+		// 2000000 - 200000 + 150000 tax on the first line.
+		require.Equal(t, domain.NewMoney(1950000, domain.DefaultCurrency), order.TotalAmount)
+
+		one, err := domain.NewReturn(order, []domain.ReturnItem{{ProductID: 1, Quantity: 1}}, "", nil)
+		require.NoError(t, err)
+		all, err := domain.NewReturn(order, []domain.ReturnItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}, "", nil)
+		require.NoError(t, err)
+
+		// 750000 - 75000 discount + 75000 tax.
+		assert.Equal(t, domain.NewMoney(750000, domain.DefaultCurrency), one.RefundAmount)
+		assert.Equal(t, order.TotalAmount, all.RefundAmount)
+	})
+
+	t.Run("should not return more than was ordered and not yet returned", func(t *testing.T) {
+		_, err := domain.NewReturn(paidOrder(), []domain.ReturnItem{{ProductID: 1, Quantity: 2}}, "", map[int64]int{1: 1})
+
+		var fieldErr *domain.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.ErrorIs(t, err, domain.ErrInvalidReturn)
+		assert.Equal(t, "items[0].quantity", fieldErr.Field)
+		assert.Equal(t, "exceeds the 1 unit(s) that can still be returned", fieldErr.Message)
+	})
+
+	t.Run("should reject products that are not part of the order", func(t *testing.T) {
+		_, err := domain.NewReturn(paidOrder(), []domain.ReturnItem{{ProductID: 9, Quantity: 1}}, "", nil)
+
+		var fieldErr *domain.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "items[0].product_id", fieldErr.Field)
+	})
+
+	t.Run("should only return paid, shipped or completed orders", func(t *testing.T) {
+		order := paidOrder()
+		order.Status = domain.StatusPending
+
+		_, err := domain.NewReturn(order, []domain.ReturnItem{{ProductID: 1, Quantity: 1}}, "", nil)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotReturnable)
+	})
+}
+
+func TestReturnedQuantities(t *testing.T) {
+	returns := []domain.Return{
+		{Status: domain.ReturnReceived, Items: []domain.ReturnItem{{ProductID: 1, Quantity: 1}}},
+		{Status: domain.ReturnRejected, Items: []domain.ReturnItem{{ProductID: 1, Quantity: 5}}},
+		{Status: domain.ReturnRequested, Items: []domain.ReturnItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 3}}},
+	}
+
+	assert.Equal(t, map[int64]int{1: 2, 2: 3}, domain.ReturnedQuantities(returns))
+}
+
+func TestReturn_Transitions(t *testing.T) {
+	t.Run("should approve and then receive a return", func(t *testing.T) {
+		rma := &domain.Return{Status: domain.ReturnRequested}
+
+		assert.ErrorIs(t, rma.CanReceive(), domain.ErrInvalidReturnTransition)
+		assert.NoError(t, rma.Approve())
+		assert.NoError(t, rma.CanReceive())
+		assert.NoError(t, rma.Receive(rma.RefundAmount))
+		assert.Equal(t, domain.ReturnReceived, rma.Status)
+		assert.True(t, rma.RefundPending())
+
+		assert.NoError(t, rma.RecordRefund("re_1"))
+		assert.Equal(t, "re_1", rma.RefundID)
+		assert.False(t, rma.RefundPending())
+		assert.ErrorIs(t, rma.RecordRefund("re_2"), domain.ErrInvalidReturnTransition)
+	})
+
+	t.Run("should not change a rejected return", func(t *testing.T) {
+		rma := &domain.Return{Status: domain.ReturnRequested}
+		assert.NoError(t, rma.Reject())
+
+		assert.ErrorIs(t, rma.Approve(), domain.ErrInvalidReturnTransition)
+		assert.ErrorIs(t, rma.Receive(rma.RefundAmount), domain.ErrInvalidReturnTransition)
+		assert.Equal(t, domain.ReturnRejected, rma.Status)
+		assert.Empty(t, rma.RefundID)
+	})
+}
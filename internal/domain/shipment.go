@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOrderNotShippable         = NewError(KindConflict, "order_not_shippable", "only paid orders can be shipped")
+	ErrInvalidShipment           = NewError(KindInvalid, "invalid_shipment", "invalid shipment")
+	ErrInvalidShipmentTransition = NewError(KindConflict, "invalid_shipment_transition", "invalid shipment status transition")
+)
+
+type ShipmentStatus string
+
+const (
+	// ShipmentShipped shipments were handed over to the carrier.
+	ShipmentShipped   ShipmentStatus = "shipped"
+	ShipmentInTransit ShipmentStatus = "in_transit"
+	ShipmentDelivered ShipmentStatus = "delivered"
+)
+
+// shipmentTransitions lists, for every status, the statuses a shipment may move to next.
+// Carriers do not always report a shipment in transit before it is delivered.
+var shipmentTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentShipped:   {ShipmentInTransit, ShipmentDelivered},
+	ShipmentInTransit: {ShipmentDelivered},
+}
+
+// Shipment is a parcel with items of an order sent with a carrier. An order may be
+// fulfilled partially by several shipments; it is shipped once all of its items are
+// in shipments.
+type Shipment struct {
+	ID             int64
+	OrderID        int64
+	Carrier        string
+	TrackingNumber string
+	Items          []ShipmentItem
+	Status         ShipmentStatus
+	ShippedAt      time.Time
+	DeliveredAt    *time.Time // Nil until the shipment is delivered.
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ShipmentItem is a quantity of an ordered product in a shipment.
+type ShipmentItem struct {
+	ID         int64
+	ShipmentID int64
+	ProductID  int64
+	Quantity   int
+}
+
+// NewShipment records that items of a paid order were handed over to a carrier.
+// Lines of the same product are merged, and no product may be shipped more often
+// than it was ordered, counting the quantities in shipped, as computed by
+// ShippedQuantities.
+func NewShipment(order *Order, carrier, trackingNumber string, items []ShipmentItem, shipped map[int64]int) (*Shipment, error) {
+	if order.Status != StatusPaid {
+		return nil, ErrOrderNotShippable
+	}
+	carrier = strings.TrimSpace(carrier)
+	if carrier == "" {
+		return nil, NewFieldError(ErrInvalidShipment, "carrier", "is required")
+	}
+	trackingNumber = strings.TrimSpace(trackingNumber)
+	if trackingNumber == "" {
+		return nil, NewFieldError(ErrInvalidShipment, "tracking_number", "is required")
+	}
+	if len(items) == 0 {
+		return nil, NewFieldError(ErrInvalidShipment, "items", "must contain at least one item")
+	}
+
+	ordered := make(map[int64]int, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		ordered[item.Product.ID] = item.Quantity
+	}
+
+	now := time.Now()
+	shipment := &Shipment{
+		OrderID:        order.ID,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		Status:         ShipmentShipped,
+		ShippedAt:      now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	positions := make(map[int64]int)
+	for i, item := range items {
+		quantity, ok := ordered[item.ProductID]
+		if !ok {
+			return nil, NewFieldError(ErrInvalidShipment, fmt.Sprintf("items[%d].product_id", i), "is not part of the order")
+		}
+		if item.Quantity <= 0 {
+			return nil, NewFieldError(ErrInvalidQuantity, fmt.Sprintf("items[%d].quantity", i), "must be positive")
+		}
+
+		pos, ok := positions[item.ProductID]
+		if !ok {
+			pos = len(shipment.Items)
+			positions[item.ProductID] = pos
+			shipment.Items = append(shipment.Items, ShipmentItem{ProductID: item.ProductID})
+		}
+		shipment.Items[pos].Quantity += item.Quantity
+
+		if remaining := quantity - shipped[item.ProductID]; shipment.Items[pos].Quantity > remaining {
+			return nil, NewFieldError(ErrInvalidShipment, fmt.Sprintf("items[%d].quantity", i), fmt.Sprintf("exceeds the %d unit(s) that are still to be shipped", remaining))
+		}
+	}
+
+	return shipment, nil
+}
+
+// ShippedQuantities sums the quantities of every product in shipments.
+func ShippedQuantities(shipments []Shipment) map[int64]int {
+	shipped := make(map[int64]int)
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			shipped[item.ProductID] += item.Quantity
+		}
+	}
+	return shipped
+}
+
+// FullyShipped reports whether every item of the order is in shipments, given the
+// quantities shipped as computed by ShippedQuantities.
+func FullyShipped(order *Order, shipped map[int64]int) bool {
+	for _, item := range order.OrderItems {
+		if shipped[item.Product.ID] < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// AllDelivered reports whether there are shipments and all of them were delivered.
+func AllDelivered(shipments []Shipment) bool {
+	if len(shipments) == 0 {
+		return false
+	}
+	for _, shipment := range shipments {
+		if shipment.Status != ShipmentDelivered {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateStatus records the tracking status reported by the carrier, enforcing the
+// allowed transitions. Delivered shipments record when they were delivered.
+func (s *Shipment) UpdateStatus(status ShipmentStatus) error {
+	if !s.Status.canTransitionTo(status) {
+		return fmt.Errorf("%w: cannot move shipment from %s to %s", ErrInvalidShipmentTransition, s.Status, status)
+	}
+
+	now := time.Now()
+	s.Status = status
+	s.UpdatedAt = now
+	if status == ShipmentDelivered {
+		s.DeliveredAt = &now
+	}
+	return nil
+}
+
+func (s ShipmentStatus) canTransitionTo(next ShipmentStatus) bool {
+	for _, allowed := range shipmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
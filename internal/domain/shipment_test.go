@@ -0,0 +1,117 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShipment(t *testing.T) {
+	paidOrder := func() *domain.Order {
+		return &domain.Order{
+			ID:     10,
+			Status: domain.StatusPaid,
+			OrderItems: []domain.OrderItem{
+				{Product: domain.Product{ID: 1}, Quantity: 2},
+				{Product: domain.Product{ID: 2}, Quantity: 1},
+			},
+		}
+	}
+
+	t.Run("should merge lines of the same product", func(t *testing.T) {
+		items := []domain.ShipmentItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}}
+
+		shipment, err := domain.NewShipment(paidOrder(), " JNE ", "JNE123", items, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ShipmentShipped, shipment.Status)
+		assert.Equal(t, "JNE", shipment.Carrier)
+		assert.Equal(t, []domain.ShipmentItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}, shipment.Items)
+		assert.False(t, shipment.ShippedAt.IsZero())
+		assert.Nil(t, shipment.DeliveredAt)
+	})
+
+	t.Run("should not ship more than is still to be shipped", func(t *testing.T) {
+		_, err := domain.NewShipment(paidOrder(), "JNE", "JNE123", []domain.ShipmentItem{{ProductID: 1, Quantity: 2}}, map[int64]int{1: 1})
+
+		var fieldErr *domain.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+		assert.Equal(t, "items[0].quantity", fieldErr.Field)
+		assert.Equal(t, "exceeds the 1 unit(s) that are still to be shipped", fieldErr.Message)
+	})
+
+	t.Run("should reject products that are not part of the order", func(t *testing.T) {
+		_, err := domain.NewShipment(paidOrder(), "JNE", "JNE123", []domain.ShipmentItem{{ProductID: 9, Quantity: 1}}, nil)
+
+		var fieldErr *domain.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "items[0].product_id", fieldErr.Field)
+	})
+
+	t.Run("should require a carrier and a tracking number", func(t *testing.T) {
+		items := []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}
+
+		_, err := domain.NewShipment(paidOrder(), "", "JNE123", items, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+
+		_, err = domain.NewShipment(paidOrder(), "JNE", " ", items, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+	})
+
+	t.Run("should only ship paid orders", func(t *testing.T) {
+		order := paidOrder()
+		order.Status = domain.StatusPending
+
+		_, err := domain.NewShipment(order, "JNE", "JNE123", []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}, nil)
+
+		assert.ErrorIs(t, err, domain.ErrOrderNotShippable)
+	})
+}
+
+func TestFullyShipped(t *testing.T) {
+	order := &domain.Order{OrderItems: []domain.OrderItem{
+		{Product: domain.Product{ID: 1}, Quantity: 2},
+		{Product: domain.Product{ID: 2}, Quantity: 1},
+	}}
+	shipments := []domain.Shipment{
+		{Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}}},
+		{Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}},
+	}
+
+	assert.False(t, domain.FullyShipped(order, domain.ShippedQuantities(shipments[:1])))
+	assert.True(t, domain.FullyShipped(order, domain.ShippedQuantities(shipments)))
+}
+
+func TestAllDelivered(t *testing.T) {
+	assert.False(t, domain.AllDelivered(nil))
+	assert.False(t, domain.AllDelivered([]domain.Shipment{{Status: domain.ShipmentDelivered}, {Status: domain.ShipmentInTransit}}))
+	assert.True(t, domain.AllDelivered([]domain.Shipment{{Status: domain.ShipmentDelivered}, {Status: domain.ShipmentDelivered}}))
+}
+
+func TestShipment_UpdateStatus(t *testing.T) {
+	t.Run("should move a shipment in transit and then deliver it", func(t *testing.T) {
+		shipment := &domain.Shipment{Status: domain.ShipmentShipped}
+
+		assert.NoError(t, shipment.UpdateStatus(domain.ShipmentInTransit))
+		assert.Nil(t, shipment.DeliveredAt)
+		assert.NoError(t, shipment.UpdateStatus(domain.ShipmentDelivered))
+		assert.Equal(t, domain.ShipmentDelivered, shipment.Status)
+		assert.NotNil(t, shipment.DeliveredAt)
+	})
+
+	t.Run("should deliver a shipment that was never reported in transit", func(t *testing.T) {
+		shipment := &domain.Shipment{Status: domain.ShipmentShipped}
+
+		assert.NoError(t, shipment.UpdateStatus(domain.ShipmentDelivered))
+	})
+
+	t.Run("should not change a delivered shipment", func(t *testing.T) {
+		shipment := &domain.Shipment{Status: domain.ShipmentDelivered}
+
+		assert.ErrorIs(t, shipment.UpdateStatus(domain.ShipmentInTransit), domain.ErrInvalidShipmentTransition)
+		assert.ErrorIs(t, shipment.UpdateStatus(domain.ShipmentDelivered), domain.ErrInvalidShipmentTransition)
+	})
+}
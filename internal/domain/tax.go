@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidTaxRate  = NewError(KindInvalid, "invalid_tax_rate", "invalid tax rate")
+	ErrTaxRuleNotFound = NewError(KindUnprocessable, "tax_rule_not_found", "no tax rule for product category and region")
+)
+
+// DefaultTaxRegion is the region orders are taxed in when none is given.
+const DefaultTaxRegion = "ID"
+
+// TaxCategory groups products that are taxed at the same rate in a region.
+type TaxCategory string
+
+const (
+	TaxCategoryStandard TaxCategory = "standard"
+	TaxCategoryExempt   TaxCategory = "exempt"
+)
+
+// OrDefault returns the category, or TaxCategoryStandard if it is empty.
+func (c TaxCategory) OrDefault() TaxCategory {
+	if c == "" {
+		return TaxCategoryStandard
+	}
+	return c
+}
+
+// TaxRate is the share of an amount charged as tax, with eight decimal places
+// like Rate, so 0.11 is 11%. Unlike an exchange rate it may be zero.
+type TaxRate int64
+
+// ParseTaxRate parses a non-negative decimal string such as "0.11".
+func ParseTaxRate(s string) (TaxRate, error) {
+	scaled, err := parseDecimal(s, rateDecimals)
+	if err != nil || scaled < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTaxRate, s)
+	}
+	return TaxRate(scaled), nil
+}
+
+// String formats the rate as a decimal string with eight fractional digits.
+func (r TaxRate) String() string {
+	return formatDecimal(int64(r), rateDecimals)
+}
+
+// Of returns the tax on an amount, rounded half away from zero to the nearest minor unit.
+func (r TaxRate) Of(m Money) Money {
+	if r == 0 {
+		return NewMoney(0, m.Currency)
+	}
+	return m.Convert(m.Currency, Rate(r))
+}
+
+// MarshalJSON encodes the rate as a decimal string.
+func (r TaxRate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or number.
+func (r *TaxRate) UnmarshalJSON(data []byte) error {
+	literal := string(data)
+	if strings.HasPrefix(literal, `"`) {
+		if err := json.Unmarshal(data, &literal); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseTaxRate(literal)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the rate as a decimal string.
+func (r TaxRate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for decimal columns.
+func (r *TaxRate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidTaxRate, src)
+	}
+
+	parsed, err := ParseTaxRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidUser    = NewError(KindInvalid, "invalid_user", "invalid user")
+	ErrInvalidAddress = NewError(KindInvalid, "invalid_address", "invalid address")
+)
+
+// User is a customer account. Orders, carts and addresses belong to a user.
+type User struct {
+	ID        int64
+	Email     string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewUser is a constructor function to create a new, validated User.
+func NewUser(email, name string) (*User, error) {
+	now := time.Now()
+	user := &User{CreatedAt: now, UpdatedAt: now}
+	if err := user.Update(email, name); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Update replaces the details of the user after validating them.
+// Emails are stored in lower case, so they are unique regardless of case.
+func (u *User) Update(email, name string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewFieldError(ErrInvalidUser, "name", "cannot be empty")
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return NewFieldError(ErrInvalidUser, "email", fmt.Sprintf("%q is not a valid email address", email))
+	}
+
+	u.Email = email
+	u.Name = name
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// PostalAddress is where a parcel is delivered to.
+type PostalAddress struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city"`
+	Province      string `json:"province,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"` // ISO 3166-1 alpha-2 code, e.g. "ID".
+}
+
+// Validate checks that the address has every line a courier needs.
+func (a PostalAddress) Validate() error {
+	required := []struct{ name, value string }{
+		{"recipient_name", a.RecipientName},
+		{"phone", a.Phone},
+		{"line1", a.Line1},
+		{"city", a.City},
+		{"postal_code", a.PostalCode},
+	}
+	for _, field := range required {
+		if strings.TrimSpace(field.value) == "" {
+			return NewFieldError(ErrInvalidAddress, field.name, "cannot be empty")
+		}
+	}
+	if len(a.Country) != 2 || strings.ToUpper(a.Country) != a.Country {
+		return NewFieldError(ErrInvalidAddress, "country", "must be an upper-case ISO 3166-1 alpha-2 code")
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the address as JSON.
+func (a PostalAddress) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner for addresses stored as JSON.
+func (a *PostalAddress) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into PostalAddress", src)
+	}
+}
+
+// Address is a postal address saved by a user for later orders.
+type Address struct {
+	ID     int64
+	UserID int64
+	Label  string // Optional name given by the user, e.g. "Home".
+	PostalAddress
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewAddress is a constructor function to create a new, validated Address.
+func NewAddress(userID int64, label string, postal PostalAddress) (*Address, error) {
+	now := time.Now()
+	address := &Address{UserID: userID, CreatedAt: now}
+	if err := address.Update(label, postal); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Update replaces the label and postal address after validating them.
+// Orders keep their own copy, so this does not change where past orders were shipped.
+func (a *Address) Update(label string, postal PostalAddress) error {
+	if err := postal.Validate(); err != nil {
+		return err
+	}
+	a.Label = strings.TrimSpace(label)
+	a.PostalAddress = postal
+	a.UpdatedAt = time.Now()
+	return nil
+}
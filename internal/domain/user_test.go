@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validPostalAddress() domain.PostalAddress {
+	return domain.PostalAddress{
+		RecipientName: "Budi Santoso",
+		Phone:         "+6281234567890",
+		Line1:         "Jl. Sudirman No. 1",
+		City:          "Jakarta",
+		PostalCode:    "10220",
+		Country:       "ID",
+	}
+}
+
+func TestNewUser(t *testing.T) {
+	t.Run("should normalize the email address", func(t *testing.T) {
+		user, err := domain.NewUser(" Budi@Example.COM ", " Budi ")
+
+		require.NoError(t, err)
+		assert.Equal(t, "budi@example.com", user.Email)
+		assert.Equal(t, "Budi", user.Name)
+	})
+
+	t.Run("should reject invalid details", func(t *testing.T) {
+		for _, email := range []string{"", "budi", "Budi <budi@example.com>"} {
+			_, err := domain.NewUser(email, "Budi")
+			assert.ErrorIs(t, err, domain.ErrInvalidUser, email)
+		}
+
+		_, err := domain.NewUser("budi@example.com", " ")
+		assert.ErrorIs(t, err, domain.ErrInvalidUser)
+	})
+}
+
+func TestPostalAddress_Validate(t *testing.T) {
+	t.Run("should accept a complete address", func(t *testing.T) {
+		assert.NoError(t, validPostalAddress().Validate())
+	})
+
+	t.Run("should reject missing lines and bad country codes", func(t *testing.T) {
+		tests := map[string]func(a *domain.PostalAddress){
+			"recipient":   func(a *domain.PostalAddress) { a.RecipientName = "" },
+			"phone":       func(a *domain.PostalAddress) { a.Phone = " " },
+			"line1":       func(a *domain.PostalAddress) { a.Line1 = "" },
+			"city":        func(a *domain.PostalAddress) { a.City = "" },
+			"postal code": func(a *domain.PostalAddress) { a.PostalCode = "" },
+			"country":     func(a *domain.PostalAddress) { a.Country = "IDN" },
+			"lower case":  func(a *domain.PostalAddress) { a.Country = "id" },
+		}
+		for name, modify := range tests {
+			address := validPostalAddress()
+			modify(&address)
+			assert.ErrorIs(t, address.Validate(), domain.ErrInvalidAddress, name)
+		}
+	})
+
+	t.Run("should round trip through its database representation", func(t *testing.T) {
+		address := validPostalAddress()
+		value, err := address.Value()
+		require.NoError(t, err)
+
+		var scanned domain.PostalAddress
+		require.NoError(t, scanned.Scan(value))
+		assert.Equal(t, address, scanned)
+	})
+}
+
+func TestAddress_Update(t *testing.T) {
+	address, err := domain.NewAddress(1, "Home", validPostalAddress())
+	require.NoError(t, err)
+
+	invalid := validPostalAddress()
+	invalid.City = ""
+	assert.ErrorIs(t, address.Update("Office", invalid), domain.ErrInvalidAddress)
+	assert.Equal(t, "Home", address.Label)
+
+	moved := validPostalAddress()
+	moved.City = "Bandung"
+	require.NoError(t, address.Update(" Office ", moved))
+	assert.Equal(t, "Office", address.Label)
+	assert.Equal(t, "Bandung", address.City)
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+type CreateAPIKeyInput struct {
+	Name      string
+	Scopes    []domain.Scope
+	ExpiresAt *time.Time // Nil for a key that never expires.
+}
+
+// CreatedAPIKey is a newly minted API key. Key is the only copy of the plain key.
+type CreatedAPIKey struct {
+	APIKey *domain.APIKey
+	Key    string
+}
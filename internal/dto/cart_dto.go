@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
+type CreateCartInput struct {
+	UserID   int64
+	Currency string // Defaults to domain.DefaultCurrency.
+}
+
+// CheckoutInput carries the order details that a cart does not hold. They are
+// applied to the order as in CreateOrderInput.
+type CheckoutInput struct {
+	CouponCode string // Optional.
+	Region     string // Tax region, defaults to domain.DefaultTaxRegion.
+
+	// At most one of these may be set.
+	ShippingAddressID int64
+	ShippingAddress   *domain.PostalAddress
+}
+
+// CartView is a cart priced with the current product prices and stock.
+type CartView struct {
+	ID       int64
+	UserID   int64
+	Currency string
+	Status   domain.CartStatus
+	OrderID  *int64
+	Items    []CartItemView
+	Subtotal domain.Money
+}
+
+// CartItemView is a cart item priced in the cart currency.
+// Products that no longer exist are listed as unavailable without a price.
+type CartItemView struct {
+	ProductID         int64
+	Name              string
+	Quantity          int
+	UnitPrice         domain.Money
+	LineTotal         domain.Money
+	AvailableQuantity int
+	Available         bool // The product exists and has enough stock for Quantity.
+}
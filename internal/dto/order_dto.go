@@ -1,11 +1,21 @@
 package dto
 
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
 type CreateOrderItemInput struct {
 	ProductID int64
 	Quantity  int
 }
 
 type CreateOrderInput struct {
-	UserID int64
-	Items  []CreateOrderItemInput
+	UserID     int64
+	Currency   string // Defaults to domain.DefaultCurrency.
+	CouponCode string // Optional.
+	Region     string // Tax region, defaults to domain.DefaultTaxRegion.
+	Items      []CreateOrderItemInput
+
+	// The order is shipped to the saved address ShippingAddressID of the user,
+	// or to ShippingAddress. At most one of them may be set.
+	ShippingAddressID int64
+	ShippingAddress   *domain.PostalAddress
 }
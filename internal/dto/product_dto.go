@@ -1,13 +1,17 @@
 package dto
 
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
 type CreateProductInput struct {
-	Name     string
-	Price    float64
-	Quantity int
+	Name        string
+	Price       domain.Money
+	TaxCategory domain.TaxCategory // Defaults to domain.TaxCategoryStandard.
+	Quantity    int
 }
 
 type UpdateProductInput struct {
-	Name     string
-	Price    float64
-	Quantity int
+	Name        string
+	Price       domain.Money
+	TaxCategory domain.TaxCategory // Keeps the current category when empty.
+	Quantity    int
 }
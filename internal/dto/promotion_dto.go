@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+type CreateCouponInput struct {
+	Code           string
+	Type           domain.CouponType
+	Percent        int
+	Amount         domain.Money
+	MinSubtotal    domain.Money
+	ProductIDs     []int64
+	MaxUses        int
+	MaxUsesPerUser int
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+}
@@ -0,0 +1,12 @@
+package dto
+
+type CreateReturnItemInput struct {
+	ProductID int64
+	Quantity  int
+}
+
+type CreateReturnInput struct {
+	OrderID int64
+	Reason  string // Optional.
+	Items   []CreateReturnItemInput
+}
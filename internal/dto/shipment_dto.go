@@ -0,0 +1,13 @@
+package dto
+
+type CreateShipmentItemInput struct {
+	ProductID int64
+	Quantity  int
+}
+
+type CreateShipmentInput struct {
+	OrderID        int64
+	Carrier        string
+	TrackingNumber string
+	Items          []CreateShipmentItemInput
+}
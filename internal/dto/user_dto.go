@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
+type CreateUserInput struct {
+	Email string
+	Name  string
+}
+
+type UpdateUserInput struct {
+	Email string
+	Name  string
+}
+
+type AddressInput struct {
+	Label   string // Optional.
+	Address domain.PostalAddress
+}
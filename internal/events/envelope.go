@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrUnknownEventType   = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+)
+
+// Type identifies an event. It doubles as the name of the queue the event is published to.
+type Type string
+
+const (
+	TypeOrderCreated   Type = "orders.created"
+	TypeOrderPaid      Type = "orders.paid"
+	TypeOrderShipped   Type = "orders.shipped"
+	TypeOrderCompleted Type = "orders.completed"
+	TypeOrderCancelled Type = "orders.cancelled"
+
+	TypeReturnRequested Type = "returns.requested"
+	TypeReturnApproved  Type = "returns.approved"
+	TypeReturnRejected  Type = "returns.rejected"
+	TypeReturnReceived  Type = "returns.received"
+
+	TypeShipmentCreated   Type = "shipments.created"
+	TypeShipmentInTransit Type = "shipments.in_transit"
+	TypeShipmentDelivered Type = "shipments.delivered"
+)
+
+// versions holds the payload schema version of every known event type.
+// Bump the version when a payload changes incompatibly, so old consumers reject it explicitly.
+var versions = map[Type]int{
+	TypeOrderCreated:   2, // v2: amounts are money objects instead of floats
+	TypeOrderPaid:      1,
+	TypeOrderShipped:   1,
+	TypeOrderCompleted: 1,
+	TypeOrderCancelled: 1,
+
+	TypeReturnRequested: 1,
+	TypeReturnApproved:  1,
+	TypeReturnRejected:  1,
+	TypeReturnReceived:  1,
+
+	TypeShipmentCreated:   1,
+	TypeShipmentInTransit: 1,
+	TypeShipmentDelivered: 1,
+}
+
+// Envelope is the common wrapper of every published message.
+type Envelope struct {
+	EventID       string          `json:"event_id"`
+	Type          Type            `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// New wraps a payload into an envelope of the current version for the event type.
+// The correlation ID is taken from the context when present.
+func New(ctx context.Context, eventType Type, payload interface{}) (*Envelope, error) {
+	version, ok := versions[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	eventID, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		EventID:       eventID,
+		Type:          eventType,
+		Version:       version,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Payload:       raw,
+	}, nil
+}
+
+// Decode parses a message body into an envelope and rejects event types
+// and versions this build does not understand.
+func Decode(body []byte) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	version, ok := versions[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, env.Type)
+	}
+	if env.Version != version {
+		return nil, fmt.Errorf("%w: %s v%d (supported: v%d)", ErrUnsupportedVersion, env.Type, env.Version, version)
+	}
+
+	return &env, nil
+}
+
+// DecodePayload unmarshals the payload into v, which should be the typed payload of the event.
+func (e *Envelope) DecodePayload(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s payload: %w", e.Type, err)
+	}
+	return nil
+}
+
+// NewID returns a random (version 4) UUID, used for event and correlation IDs.
+func NewID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// correlationIDKey is the key used to store the correlation ID in the context.
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID that events created from it will have.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context, if any.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,91 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAndDecode(t *testing.T) {
+	ctx := events.WithCorrelationID(context.Background(), "req-123")
+	order := &domain.Order{
+		ID:          7,
+		UserID:      123,
+		Status:      domain.StatusPending,
+		TotalAmount: idr("25000"),
+		OrderItems: []domain.OrderItem{
+			{Product: domain.Product{ID: 1, Name: "Product A"}, Quantity: 2, PriceAtOrder: idr("10000")},
+			{Product: domain.Product{ID: 2, Name: "Product B"}, Quantity: 1, PriceAtOrder: idr("5000")},
+		},
+	}
+
+	// Act
+	env, err := events.New(ctx, events.TypeOrderCreated, events.NewOrderCreated(order))
+	assert.NoError(t, err)
+	body, err := json.Marshal(env)
+	assert.NoError(t, err)
+	decoded, err := events.Decode(body)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, decoded.EventID)
+	assert.Equal(t, events.TypeOrderCreated, decoded.Type)
+	assert.Equal(t, 2, decoded.Version)
+	assert.Equal(t, "req-123", decoded.CorrelationID)
+	assert.NotZero(t, decoded.OccurredAt)
+
+	var payload events.OrderCreated
+	assert.NoError(t, decoded.DecodePayload(&payload))
+	assert.Equal(t, int64(7), payload.OrderID)
+	assert.Equal(t, idr("25000"), payload.TotalAmount)
+	assert.Equal(t, domain.StatusPending, payload.Status)
+	assert.Len(t, payload.Items, 2)
+	assert.Equal(t, "Product A", payload.Items[0].ProductName)
+}
+
+func TestNew_UnknownType(t *testing.T) {
+	env, err := events.New(context.Background(), events.Type("orders.teleported"), struct{}{})
+
+	assert.ErrorIs(t, err, events.ErrUnknownEventType)
+	assert.Nil(t, env)
+}
+
+func TestDecode_RejectsUnknownVersionsAndTypes(t *testing.T) {
+	t.Run("should reject an unsupported version", func(t *testing.T) {
+		body := []byte(`{"event_id":"1","type":"orders.created","version":1,"payload":{}}`)
+
+		env, err := events.Decode(body)
+
+		assert.ErrorIs(t, err, events.ErrUnsupportedVersion)
+		assert.Nil(t, env)
+	})
+
+	t.Run("should reject an unknown event type", func(t *testing.T) {
+		body := []byte(`{"event_id":"1","type":"orders.teleported","version":1,"payload":{}}`)
+
+		env, err := events.Decode(body)
+
+		assert.ErrorIs(t, err, events.ErrUnknownEventType)
+		assert.Nil(t, env)
+	})
+
+	t.Run("should reject a legacy message without envelope", func(t *testing.T) {
+		env, err := events.Decode([]byte(`{"order_id":1,"user_id":123}`))
+
+		assert.ErrorIs(t, err, events.ErrUnknownEventType)
+		assert.Nil(t, env)
+	})
+}
+
+// idr parses a decimal amount in the default currency, failing loudly on bad test data.
+func idr(amount string) domain.Money {
+	m, err := domain.ParseMoney(amount, domain.DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
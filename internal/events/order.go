@@ -0,0 +1,67 @@
+package events
+
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
+// OrderItem is a line item as carried in order events.
+type OrderItem struct {
+	ProductID    int64        `json:"product_id"`
+	ProductName  string       `json:"product_name"`
+	Quantity     int          `json:"quantity"`
+	PriceAtOrder domain.Money `json:"price_at_order"`
+	Tax          domain.Money `json:"tax"`
+}
+
+// OrderCreated is the payload of TypeOrderCreated.
+type OrderCreated struct {
+	OrderID     int64              `json:"order_id"`
+	UserID      int64              `json:"user_id"`
+	Items       []OrderItem        `json:"items"`
+	Subtotal    domain.Money       `json:"subtotal"`
+	Discount    domain.Money       `json:"discount"`
+	CouponCode  string             `json:"coupon_code,omitempty"`
+	Tax         domain.Money       `json:"tax"`
+	TotalAmount domain.Money       `json:"total_amount"`
+	Status      domain.OrderStatus `json:"status"`
+}
+
+// OrderStatusChanged is the payload of the paid, shipped, completed and cancelled order events.
+type OrderStatusChanged struct {
+	OrderID int64              `json:"order_id"`
+	UserID  int64              `json:"user_id"`
+	Status  domain.OrderStatus `json:"status"`
+}
+
+// NewOrderCreated builds the OrderCreated payload of an order.
+func NewOrderCreated(order *domain.Order) OrderCreated {
+	items := make([]OrderItem, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		items[i] = OrderItem{
+			ProductID:    item.Product.ID,
+			ProductName:  item.Product.Name,
+			Quantity:     item.Quantity,
+			PriceAtOrder: item.PriceAtOrder,
+			Tax:          item.Tax,
+		}
+	}
+
+	return OrderCreated{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		Items:       items,
+		Subtotal:    order.Subtotal,
+		Discount:    order.Discount,
+		CouponCode:  order.CouponCode,
+		Tax:         order.Tax,
+		TotalAmount: order.TotalAmount,
+		Status:      order.Status,
+	}
+}
+
+// NewOrderStatusChanged builds the OrderStatusChanged payload of an order.
+func NewOrderStatusChanged(order *domain.Order) OrderStatusChanged {
+	return OrderStatusChanged{
+		OrderID: order.ID,
+		UserID:  order.UserID,
+		Status:  order.Status,
+	}
+}
@@ -0,0 +1,63 @@
+package events
+
+import "github.com/elokanugrah/go-order-system/internal/domain"
+
+// ReturnItem is a returned line as carried in return events.
+type ReturnItem struct {
+	ProductID    int64        `json:"product_id"`
+	Quantity     int          `json:"quantity"`
+	PriceAtOrder domain.Money `json:"price_at_order"`
+}
+
+// ReturnRequested is the payload of TypeReturnRequested.
+type ReturnRequested struct {
+	ReturnID     int64               `json:"return_id"`
+	OrderID      int64               `json:"order_id"`
+	UserID       int64               `json:"user_id"`
+	Reason       string              `json:"reason,omitempty"`
+	Items        []ReturnItem        `json:"items"`
+	RefundAmount domain.Money        `json:"refund_amount"`
+	Status       domain.ReturnStatus `json:"status"`
+}
+
+// ReturnStatusChanged is the payload of the approved, rejected and received return events.
+type ReturnStatusChanged struct {
+	ReturnID     int64               `json:"return_id"`
+	OrderID      int64               `json:"order_id"`
+	UserID       int64               `json:"user_id"`
+	Status       domain.ReturnStatus `json:"status"`
+	RefundAmount domain.Money        `json:"refund_amount"`
+}
+
+// NewReturnRequested builds the ReturnRequested payload of a return.
+func NewReturnRequested(rma *domain.Return) ReturnRequested {
+	items := make([]ReturnItem, len(rma.Items))
+	for i, item := range rma.Items {
+		items[i] = ReturnItem{
+			ProductID:    item.ProductID,
+			Quantity:     item.Quantity,
+			PriceAtOrder: item.PriceAtOrder,
+		}
+	}
+
+	return ReturnRequested{
+		ReturnID:     rma.ID,
+		OrderID:      rma.OrderID,
+		UserID:       rma.UserID,
+		Reason:       rma.Reason,
+		Items:        items,
+		RefundAmount: rma.RefundAmount,
+		Status:       rma.Status,
+	}
+}
+
+// NewReturnStatusChanged builds the ReturnStatusChanged payload of a return.
+func NewReturnStatusChanged(rma *domain.Return) ReturnStatusChanged {
+	return ReturnStatusChanged{
+		ReturnID:     rma.ID,
+		OrderID:      rma.OrderID,
+		UserID:       rma.UserID,
+		Status:       rma.Status,
+		RefundAmount: rma.RefundAmount,
+	}
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+// ShipmentItem is a shipped line as carried in shipment events.
+type ShipmentItem struct {
+	ProductID int64 `json:"product_id"`
+	Quantity  int   `json:"quantity"`
+}
+
+// ShipmentCreated is the payload of TypeShipmentCreated.
+type ShipmentCreated struct {
+	ShipmentID     int64                 `json:"shipment_id"`
+	OrderID        int64                 `json:"order_id"`
+	Carrier        string                `json:"carrier"`
+	TrackingNumber string                `json:"tracking_number"`
+	Items          []ShipmentItem        `json:"items"`
+	Status         domain.ShipmentStatus `json:"status"`
+	ShippedAt      time.Time             `json:"shipped_at"`
+}
+
+// ShipmentStatusChanged is the payload of the in-transit and delivered shipment events.
+// DeliveredAt is set once the shipment was delivered.
+type ShipmentStatusChanged struct {
+	ShipmentID     int64                 `json:"shipment_id"`
+	OrderID        int64                 `json:"order_id"`
+	Carrier        string                `json:"carrier"`
+	TrackingNumber string                `json:"tracking_number"`
+	Status         domain.ShipmentStatus `json:"status"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// NewShipmentCreated builds the ShipmentCreated payload of a shipment.
+func NewShipmentCreated(shipment *domain.Shipment) ShipmentCreated {
+	items := make([]ShipmentItem, len(shipment.Items))
+	for i, item := range shipment.Items {
+		items[i] = ShipmentItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return ShipmentCreated{
+		ShipmentID:     shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Items:          items,
+		Status:         shipment.Status,
+		ShippedAt:      shipment.ShippedAt,
+	}
+}
+
+// NewShipmentStatusChanged builds the ShipmentStatusChanged payload of a shipment.
+func NewShipmentStatusChanged(shipment *domain.Shipment) ShipmentStatusChanged {
+	return ShipmentStatusChanged{
+		ShipmentID:     shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		DeliveredAt:    shipment.DeliveredAt,
+	}
+}
@@ -21,17 +21,9 @@ func (b *RabbitMQBroker) Publish(ctx context.Context, queueName string, message
 	}
 	defer ch.Close()
 
-	// Declare a queue to ensure it exists.
-	_, err = ch.QueueDeclare(
-		queueName,
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare a queue: %w", err)
+	// Declare the queue and its retry and dead-letter queues to ensure they exist.
+	if err := DeclareQueue(ch, queueName); err != nil {
+		return err
 	}
 
 	// Publish the message to the queue.
@@ -0,0 +1,119 @@
+package messagebroker
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// DeadLetterExchange receives messages that were rejected without requeue.
+	DeadLetterExchange = "dead-letter"
+
+	// RetryCountHeader carries how many times a message has been retried.
+	RetryCountHeader = "x-retry-count"
+)
+
+// QueueDeclarer is the subset of *amqp.Channel needed to declare queues.
+type QueueDeclarer interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// DeadLetterQueueName returns the name of the queue holding poison messages of a queue.
+func DeadLetterQueueName(queueName string) string {
+	return queueName + ".dlq"
+}
+
+// RetryQueueName returns the name of the queue used to delay retries of a queue.
+func RetryQueueName(queueName string) string {
+	return queueName + ".retry"
+}
+
+// DeclareQueue declares a durable work queue together with its dead-letter and retry queues.
+// Messages rejected without requeue are routed to "<queue>.dlq", and messages published to
+// "<queue>.retry" with an expiration are moved back to the work queue once they expire.
+// Publishers and consumers must both use it so that the queue arguments always match.
+func DeclareQueue(ch QueueDeclarer, queueName string) error {
+	err := ch.ExchangeDeclare(
+		DeadLetterExchange,
+		"direct", // kind
+		true,     // durable
+		false,    // auto-deleted
+		false,    // internal
+		false,    // no-wait
+		nil,      // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange: %w", err)
+	}
+
+	dlqName := DeadLetterQueueName(queueName)
+	if _, err := ch.QueueDeclare(dlqName, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+	if err := ch.QueueBind(dlqName, dlqName, DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue: %w", err)
+	}
+
+	// Expired retry messages are dead-lettered through the default exchange back to the work queue.
+	_, err = ch.QueueDeclare(RetryQueueName(queueName), true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queueName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare retry queue: %w", err)
+	}
+
+	_, err = ch.QueueDeclare(
+		queueName,
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    DeadLetterExchange,
+			"x-dead-letter-routing-key": dlqName,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare a queue: %w", err)
+	}
+
+	return nil
+}
+
+// RetryCount reads the retry count header of a delivery, defaulting to zero.
+func RetryCount(headers amqp.Table) int {
+	switch v := headers[RetryCountHeader].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// RetryPublishing builds the message that schedules another attempt of a delivery after delay.
+// It is meant to be published to the retry queue of the delivery's queue.
+func RetryPublishing(d amqp.Delivery, delay time.Duration) amqp.Publishing {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[RetryCountHeader] = int32(RetryCount(d.Headers) + 1)
+
+	return amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		Body:         d.Body,
+		DeliveryMode: amqp.Persistent,
+		Expiration:   strconv.FormatInt(delay.Milliseconds(), 10),
+	}
+}
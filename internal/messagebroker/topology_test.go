@@ -0,0 +1,36 @@
+package messagebroker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryCount(t *testing.T) {
+	assert.Equal(t, 0, messagebroker.RetryCount(nil))
+	assert.Equal(t, 0, messagebroker.RetryCount(amqp.Table{messagebroker.RetryCountHeader: "3"}))
+	assert.Equal(t, 3, messagebroker.RetryCount(amqp.Table{messagebroker.RetryCountHeader: int32(3)}))
+	assert.Equal(t, 4, messagebroker.RetryCount(amqp.Table{messagebroker.RetryCountHeader: int64(4)}))
+}
+
+func TestRetryPublishing(t *testing.T) {
+	d := amqp.Delivery{
+		Headers:     amqp.Table{messagebroker.RetryCountHeader: int32(1), "trace-id": "abc"},
+		ContentType: "application/json",
+		Body:        []byte(`{"order_id":1}`),
+	}
+
+	// Act
+	p := messagebroker.RetryPublishing(d, 10*time.Second)
+
+	// Assert
+	assert.Equal(t, 2, messagebroker.RetryCount(p.Headers)) // Incremented
+	assert.Equal(t, "abc", p.Headers["trace-id"])           // Other headers are kept
+	assert.Equal(t, "10000", p.Expiration)                  // Delay in milliseconds
+	assert.Equal(t, d.Body, p.Body)
+	assert.Equal(t, amqp.Persistent, p.DeliveryMode)
+	assert.Equal(t, 1, messagebroker.RetryCount(d.Headers)) // Original delivery is untouched
+}
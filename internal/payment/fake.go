@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure FakeGateway implements the usecase.PaymentGateway interface.
+var _ usecase.PaymentGateway = (*FakeGateway)(nil)
+
+var (
+	ErrChargeNotFound    = errors.New("charge not found")
+	ErrChargeNotCaptured = errors.New("charge has not been captured")
+	ErrChargeCaptured    = errors.New("charge has been captured")
+	ErrChargeVoided      = errors.New("charge has been voided")
+	ErrAmountExceeded    = errors.New("amount exceeds the charge")
+	ErrMissingKey        = errors.New("idempotency key is missing")
+	ErrKeyReused         = errors.New("idempotency key was used for another request")
+)
+
+// FakeGateway is an in-process payment provider for local use and tests. It accepts
+// every charge and keeps them in memory; the webhooks that a real provider would send
+// when a customer authorizes a charge have to be sent by hand, signed with Sign.
+// Like a real provider, it answers a repeated request with the result of the first
+// request with the same idempotency key.
+type FakeGateway struct {
+	mu      sync.Mutex
+	charges map[string]*fakeCharge
+	results map[string]fakeResult // By idempotency key.
+}
+
+type fakeCharge struct {
+	amount   domain.Money
+	captured bool
+	voided   bool
+	refunded domain.Money
+}
+
+// fakeResult is the outcome of a successful request.
+type fakeResult struct {
+	request  string // Describes the request, to detect keys that are reused for another one.
+	refundID string
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{charges: make(map[string]*fakeCharge), results: make(map[string]fakeResult)}
+}
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+// CreateCharge records a charge of amount and returns its ID, e.g. "ch_3f9c...".
+func (g *FakeGateway) CreateCharge(ctx context.Context, orderID int64, amount domain.Money) (string, error) {
+	id, err := newID("ch_")
+	if err != nil {
+		return "", err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.charges[id] = &fakeCharge{amount: amount, refunded: domain.NewMoney(0, amount.Currency)}
+	return id, nil
+}
+
+// Capture marks a charge as captured, provided amount does not exceed it.
+func (g *FakeGateway) Capture(ctx context.Context, providerPaymentID string, amount domain.Money, idempotencyKey string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	request := fmt.Sprintf("capture %s %d %s", providerPaymentID, amount.Amount, amount.Currency)
+	if _, ok, err := g.replay(idempotencyKey, request); ok || err != nil {
+		return err
+	}
+
+	charge, ok := g.charges[providerPaymentID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChargeNotFound, providerPaymentID)
+	}
+	if charge.voided {
+		return fmt.Errorf("%w: %s", ErrChargeVoided, providerPaymentID)
+	}
+	if cmp, err := amount.Cmp(charge.amount); err != nil || cmp > 0 {
+		return fmt.Errorf("%w: %s", ErrAmountExceeded, providerPaymentID)
+	}
+	charge.captured = true
+	g.results[idempotencyKey] = fakeResult{request: request}
+	return nil
+}
+
+// Void releases a charge that was not captured. Voiding a voided charge again has no effect.
+func (g *FakeGateway) Void(ctx context.Context, providerPaymentID string, idempotencyKey string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	request := "void " + providerPaymentID
+	if _, ok, err := g.replay(idempotencyKey, request); ok || err != nil {
+		return err
+	}
+
+	charge, ok := g.charges[providerPaymentID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChargeNotFound, providerPaymentID)
+	}
+	if charge.captured {
+		return fmt.Errorf("%w: %s", ErrChargeCaptured, providerPaymentID)
+	}
+	charge.voided = true
+	g.results[idempotencyKey] = fakeResult{request: request}
+	return nil
+}
+
+// Refund pays back amount of a captured charge and returns the ID of the refund,
+// e.g. "re_7a1d...". All refunds of a charge together may not exceed it.
+func (g *FakeGateway) Refund(ctx context.Context, providerPaymentID string, amount domain.Money, idempotencyKey string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	request := fmt.Sprintf("refund %s %d %s", providerPaymentID, amount.Amount, amount.Currency)
+	if result, ok, err := g.replay(idempotencyKey, request); ok || err != nil {
+		return result.refundID, err
+	}
+
+	charge, ok := g.charges[providerPaymentID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrChargeNotFound, providerPaymentID)
+	}
+	if !charge.captured {
+		return "", fmt.Errorf("%w: %s", ErrChargeNotCaptured, providerPaymentID)
+	}
+	refunded, err := charge.refunded.Add(amount)
+	if err != nil {
+		return "", err
+	}
+	if cmp, _ := refunded.Cmp(charge.amount); cmp > 0 {
+		return "", fmt.Errorf("%w: %s", ErrAmountExceeded, providerPaymentID)
+	}
+
+	id, err := newID("re_")
+	if err != nil {
+		return "", err
+	}
+	charge.refunded = refunded
+	g.results[idempotencyKey] = fakeResult{request: request, refundID: id}
+	return id, nil
+}
+
+// replay returns the result of the request with key, if it succeeded before.
+// It must be called with g.mu held.
+func (g *FakeGateway) replay(key, request string) (fakeResult, bool, error) {
+	if key == "" {
+		return fakeResult{}, false, ErrMissingKey
+	}
+	result, ok := g.results[key]
+	if ok && result.request != request {
+		return fakeResult{}, false, fmt.Errorf("%w: %s", ErrKeyReused, key)
+	}
+	return result, ok, nil
+}
+
+func newID(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,104 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+	amount := domain.NewMoney(5000000, domain.DefaultCurrency)
+
+	t.Run("should capture and refund a charge", func(t *testing.T) {
+		gateway := NewFakeGateway()
+
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+		require.NoError(t, gateway.Capture(ctx, id, amount, "capture-1"))
+
+		refundID, err := gateway.Refund(ctx, id, domain.NewMoney(2000000, domain.DefaultCurrency), "refund-1")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, refundID)
+
+		_, err = gateway.Refund(ctx, id, domain.NewMoney(3000001, domain.DefaultCurrency), "refund-2")
+		assert.ErrorIs(t, err, ErrAmountExceeded)
+	})
+
+	t.Run("should only refund captured charges", func(t *testing.T) {
+		gateway := NewFakeGateway()
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+
+		_, err = gateway.Refund(ctx, id, amount, "refund-1")
+
+		assert.ErrorIs(t, err, ErrChargeNotCaptured)
+	})
+
+	t.Run("should not capture more than the charge", func(t *testing.T) {
+		gateway := NewFakeGateway()
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+
+		err = gateway.Capture(ctx, id, domain.NewMoney(5000001, domain.DefaultCurrency), "capture-1")
+
+		assert.ErrorIs(t, err, ErrAmountExceeded)
+	})
+
+	t.Run("should void a charge that was not captured", func(t *testing.T) {
+		gateway := NewFakeGateway()
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+		captured, err := gateway.CreateCharge(ctx, 2, amount)
+		require.NoError(t, err)
+		require.NoError(t, gateway.Capture(ctx, captured, amount, "capture-2"))
+
+		assert.NoError(t, gateway.Void(ctx, id, "void-1"))
+		assert.ErrorIs(t, gateway.Capture(ctx, id, amount, "capture-1"), ErrChargeVoided)
+		assert.ErrorIs(t, gateway.Void(ctx, captured, "void-2"), ErrChargeCaptured)
+	})
+
+	t.Run("should reject unknown charges", func(t *testing.T) {
+		gateway := NewFakeGateway()
+
+		assert.ErrorIs(t, gateway.Capture(ctx, "ch_unknown", amount, "capture-1"), ErrChargeNotFound)
+		assert.ErrorIs(t, gateway.Void(ctx, "ch_unknown", "void-1"), ErrChargeNotFound)
+		_, err := gateway.Refund(ctx, "ch_unknown", amount, "refund-1")
+		assert.ErrorIs(t, err, ErrChargeNotFound)
+	})
+
+	t.Run("should refund once per idempotency key", func(t *testing.T) {
+		gateway := NewFakeGateway()
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+		require.NoError(t, gateway.Capture(ctx, id, amount, "capture-1"))
+		require.NoError(t, gateway.Capture(ctx, id, amount, "capture-1"))
+
+		first, err := gateway.Refund(ctx, id, domain.NewMoney(3000000, domain.DefaultCurrency), "refund-1")
+		require.NoError(t, err)
+		again, err := gateway.Refund(ctx, id, domain.NewMoney(3000000, domain.DefaultCurrency), "refund-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, first, again)
+		// Only the first refund counts against the charge.
+		_, err = gateway.Refund(ctx, id, domain.NewMoney(2000000, domain.DefaultCurrency), "refund-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject reused or missing idempotency keys", func(t *testing.T) {
+		gateway := NewFakeGateway()
+		id, err := gateway.CreateCharge(ctx, 1, amount)
+		require.NoError(t, err)
+		require.NoError(t, gateway.Capture(ctx, id, amount, "capture-1"))
+		_, err = gateway.Refund(ctx, id, domain.NewMoney(1000000, domain.DefaultCurrency), "refund-1")
+		require.NoError(t, err)
+
+		_, err = gateway.Refund(ctx, id, domain.NewMoney(2000000, domain.DefaultCurrency), "refund-1")
+		assert.ErrorIs(t, err, ErrKeyReused)
+		_, err = gateway.Refund(ctx, id, domain.NewMoney(1000000, domain.DefaultCurrency), "")
+		assert.ErrorIs(t, err, ErrMissingKey)
+	})
+}
@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Environments in which the fake provider may be used.
+var fakeEnvironments = map[string]bool{"development": true, "test": true}
+
+// NewGateway returns the gateway of the payment provider named provider, for the
+// environment env the service runs in. The fake provider accepts every charge, so it
+// is refused outside development and test.
+func NewGateway(provider, env string) (usecase.PaymentGateway, error) {
+	switch provider {
+	case "fake":
+		if !fakeEnvironments[env] {
+			return nil, fmt.Errorf("the fake payment provider may only be used in development or test, not in %q", env)
+		}
+		return NewFakeGateway(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", provider)
+	}
+}
@@ -0,0 +1,30 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGateway(t *testing.T) {
+	t.Run("should use the fake provider in development and test", func(t *testing.T) {
+		for _, env := range []string{"development", "test"} {
+			gateway, err := NewGateway("fake", env)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "fake", gateway.Name())
+		}
+	})
+
+	t.Run("should refuse the fake provider in other environments", func(t *testing.T) {
+		_, err := NewGateway("fake", "production")
+
+		assert.ErrorContains(t, err, "only be used in development or test")
+	})
+
+	t.Run("should refuse unknown providers", func(t *testing.T) {
+		_, err := NewGateway("acme", "production")
+
+		assert.ErrorContains(t, err, `unknown payment provider "acme"`)
+	})
+}
@@ -0,0 +1,85 @@
+// Package payment provides the payment provider behind usecase.PaymentGateway and
+// verifies the webhooks it sends.
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+var ErrInvalidSignature = domain.NewError(domain.KindUnauthenticated, "invalid_webhook_signature", "invalid webhook signature")
+
+const (
+	// SignatureHeader carries the signature of a webhook request.
+	SignatureHeader = "Payment-Signature"
+
+	// signatureTolerance is how old a signed webhook may be, to limit replays.
+	signatureTolerance = 5 * time.Minute
+)
+
+// WebhookVerifier checks the signatures of webhooks. A signature has the form
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">", keyed with a secret
+// shared with the provider.
+type WebhookVerifier struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewWebhookVerifier returns a verifier for webhooks signed with secret.
+func NewWebhookVerifier(secret []byte) (*WebhookVerifier, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("webhook secret must be at least 32 bytes")
+	}
+	return &WebhookVerifier{secret: secret, now: time.Now}, nil
+}
+
+// Verify checks that payload was signed with the secret within the tolerated time.
+func (v *WebhookVerifier) Verify(payload []byte, signature string) error {
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed timestamp", ErrInvalidSignature)
+	}
+	age := v.now().Sub(time.Unix(unix, 0))
+	if age > signatureTolerance || age < -signatureTolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidSignature)
+	}
+
+	got, err := hex.DecodeString(mac)
+	if err != nil || !hmac.Equal(got, sign(v.secret, timestamp, payload)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Sign returns the signature of payload sent at t, as the provider computes it.
+func Sign(secret, payload []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(sign(secret, timestamp, payload)))
+}
+
+func sign(secret []byte, timestamp string, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp + "."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
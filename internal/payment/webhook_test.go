@@ -0,0 +1,56 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookVerifier(t *testing.T) {
+	secret := []byte(strings.Repeat("s", 32))
+	payload := []byte(`{"type":"payment.authorized","payment_id":"ch_1"}`)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	verifier, err := NewWebhookVerifier(secret)
+	require.NoError(t, err)
+	verifier.now = func() time.Time { return now }
+
+	t.Run("should accept a valid signature", func(t *testing.T) {
+		assert.NoError(t, verifier.Verify(payload, Sign(secret, payload, now.Add(-time.Minute))))
+	})
+
+	t.Run("should reject a signature of another body", func(t *testing.T) {
+		signature := Sign(secret, payload, now)
+
+		err := verifier.Verify([]byte(`{"type":"payment.authorized","payment_id":"ch_2"}`), signature)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("should reject a signature made with another secret", func(t *testing.T) {
+		signature := Sign([]byte(strings.Repeat("x", 32)), payload, now)
+
+		assert.ErrorIs(t, verifier.Verify(payload, signature), ErrInvalidSignature)
+	})
+
+	t.Run("should reject an old signature", func(t *testing.T) {
+		signature := Sign(secret, payload, now.Add(-10*time.Minute))
+
+		assert.ErrorIs(t, verifier.Verify(payload, signature), ErrInvalidSignature)
+	})
+
+	t.Run("should reject malformed signatures", func(t *testing.T) {
+		for _, signature := range []string{"", "v1=abc", "t=abc,v1=abc", "t=1714564800,v1=not-hex"} {
+			assert.ErrorIs(t, verifier.Verify(payload, signature), ErrInvalidSignature, signature)
+		}
+	})
+
+	t.Run("should refuse short secrets", func(t *testing.T) {
+		_, err := NewWebhookVerifier([]byte("short"))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,66 @@
+// Package file provides repositories backed by local files, for local development and tests.
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure RateProvider implements the usecase.RateProvider interface.
+var _ usecase.RateProvider = (*RateProvider)(nil)
+
+// RateProvider serves exchange rates loaded once from a JSON file such as:
+//
+//	[{"from": "USD", "to": "IDR", "rate": "16250.5"}]
+type RateProvider struct {
+	rates map[[2]string]domain.ExchangeRate
+}
+
+type rateEntry struct {
+	From string      `json:"from"`
+	To   string      `json:"to"`
+	Rate domain.Rate `json:"rate"`
+}
+
+// NewRateProvider reads the rates from the file at path.
+func NewRateProvider(path string) (*RateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rates file: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rates file: %w", err)
+	}
+
+	var entries []rateEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing rates file %s: %w", path, err)
+	}
+
+	rates := make(map[[2]string]domain.ExchangeRate, len(entries))
+	for _, e := range entries {
+		rates[[2]string{e.From, e.To}] = domain.ExchangeRate{
+			From:      e.From,
+			To:        e.To,
+			Rate:      e.Rate,
+			UpdatedAt: info.ModTime(),
+		}
+	}
+	return &RateProvider{rates: rates}, nil
+}
+
+// FindRate returns the rate of a currency pair, or nil if the file doesn't list it.
+func (p *RateProvider) FindRate(_ context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rate, ok := p.rates[[2]string{from, to}]
+	if !ok {
+		return nil, nil
+	}
+	return &rate, nil
+}
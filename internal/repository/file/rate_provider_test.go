@@ -0,0 +1,49 @@
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"from": "USD", "to": "IDR", "rate": "16250.5"},
+		{"from": "IDR", "to": "USD", "rate": 0.00006154}
+	]`), 0o600))
+
+	provider, err := file.NewRateProvider(path)
+	require.NoError(t, err)
+
+	t.Run("should return a listed rate", func(t *testing.T) {
+		rate, err := provider.FindRate(context.Background(), "USD", "IDR")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", rate.From)
+		assert.Equal(t, "IDR", rate.To)
+		assert.Equal(t, domain.Rate(1625050000000), rate.Rate)
+	})
+
+	t.Run("should return nil for an unknown pair", func(t *testing.T) {
+		rate, err := provider.FindRate(context.Background(), "EUR", "IDR")
+
+		assert.NoError(t, err)
+		assert.Nil(t, rate)
+	})
+
+	t.Run("should reject a malformed file", func(t *testing.T) {
+		badPath := filepath.Join(t.TempDir(), "bad.json")
+		require.NoError(t, os.WriteFile(badPath, []byte(`[{"from": "USD", "to": "IDR", "rate": "-1"}]`), 0o600))
+
+		_, err := file.NewRateProvider(badPath)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRate)
+	})
+}
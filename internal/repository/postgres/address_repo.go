@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresAddressRepository implements the usecase.AddressRepository interface.
+var _ usecase.AddressRepository = (*PostgresAddressRepository)(nil)
+
+type PostgresAddressRepository struct {
+	db *sql.DB
+}
+
+func NewAddressRepository(db *sql.DB) *PostgresAddressRepository {
+	return &PostgresAddressRepository{db: db}
+}
+
+const addressColumns = `id, user_id, label, recipient_name, phone, line1, line2, city, province, postal_code, country, created_at, updated_at`
+
+// Save inserts a new address.
+func (r *PostgresAddressRepository) Save(ctx context.Context, address *domain.Address) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO addresses (user_id, label, recipient_name, phone, line1, line2, city, province, postal_code, country, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			   RETURNING id`
+
+	a := address.PostalAddress
+	err := q.QueryRowContext(ctx, query,
+		address.UserID, address.Label, a.RecipientName, a.Phone, a.Line1, a.Line2, a.City, a.Province, a.PostalCode, a.Country,
+		address.CreatedAt, address.UpdatedAt,
+	).Scan(&address.ID)
+	if err != nil {
+		return fmt.Errorf("error saving address: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an address by its ID.
+func (r *PostgresAddressRepository) FindByID(ctx context.Context, id int64) (*domain.Address, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + addressColumns + ` FROM addresses WHERE id = $1`
+
+	address, err := scanAddress(q.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning address: %w", err)
+	}
+
+	return address, nil
+}
+
+// FindByUserID retrieves all addresses of a user, oldest first.
+func (r *PostgresAddressRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.Address, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + addressColumns + ` FROM addresses WHERE user_id = $1 ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []domain.Address
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning address row: %w", err)
+		}
+		addresses = append(addresses, *address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating address rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+func scanAddress(row rowScanner) (*domain.Address, error) {
+	var address domain.Address
+	a := &address.PostalAddress
+	err := row.Scan(
+		&address.ID, &address.UserID, &address.Label,
+		&a.RecipientName, &a.Phone, &a.Line1, &a.Line2, &a.City, &a.Province, &a.PostalCode, &a.Country,
+		&address.CreatedAt, &address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// Update persists the label and postal address of an existing address.
+func (r *PostgresAddressRepository) Update(ctx context.Context, address *domain.Address) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE addresses SET label = $1, recipient_name = $2, phone = $3, line1 = $4, line2 = $5,
+			   city = $6, province = $7, postal_code = $8, country = $9, updated_at = $10
+			   WHERE id = $11`
+
+	a := address.PostalAddress
+	result, err := q.ExecContext(ctx, query,
+		address.Label, a.RecipientName, a.Phone, a.Line1, a.Line2, a.City, a.Province, a.PostalCode, a.Country,
+		time.Now(), address.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating address: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("address not found for update")
+	}
+
+	return nil
+}
+
+// Delete removes an address. Orders shipped to it keep their own copy.
+func (r *PostgresAddressRepository) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `DELETE FROM addresses WHERE id = $1`
+
+	result, err := q.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting address: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("address not found for delete")
+	}
+
+	return nil
+}
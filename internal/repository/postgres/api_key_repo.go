@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/lib/pq"
+)
+
+// Ensure PostgresAPIKeyRepository implements the usecase.APIKeyRepository interface.
+var _ usecase.APIKeyRepository = (*PostgresAPIKeyRepository)(nil)
+
+type PostgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at`
+
+// Save inserts a new API key.
+func (r *PostgresAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO api_keys (name, prefix, secret_hash, scopes, expires_at, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7)
+			   RETURNING id`
+
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	err := q.QueryRowContext(ctx, query,
+		key.Name,
+		key.Prefix,
+		key.SecretHash,
+		pq.Array(scopes),
+		key.ExpiresAt,
+		key.CreatedAt,
+		key.UpdatedAt,
+	).Scan(&key.ID)
+	if err != nil {
+		return fmt.Errorf("error saving API key: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an API key by its ID.
+func (r *PostgresAPIKeyRepository) FindByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+	return scanAPIKeyOrNil(q.QueryRowContext(ctx, query, id))
+}
+
+// FindByPrefix retrieves an API key by the public prefix of the plain key.
+func (r *PostgresAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+
+	return scanAPIKeyOrNil(q.QueryRowContext(ctx, query, prefix))
+}
+
+// FindAll retrieves every API key, newest first.
+func (r *PostgresAPIKeyRepository) FindAll(ctx context.Context) ([]domain.APIKey, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id DESC`
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating API key rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// TouchLastUsed persists when an API key was last used. It leaves the revocation
+// alone, so recording a use can never undo a concurrent revoke.
+func (r *PostgresAPIKeyRepository) TouchLastUsed(ctx context.Context, key *domain.APIKey) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE api_keys SET last_used_at = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := q.ExecContext(ctx, query, key.LastUsedAt, time.Now(), key.ID)
+	if err != nil {
+		return fmt.Errorf("error updating API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("API key not found for update")
+	}
+
+	return nil
+}
+
+// Revoke persists the revocation of an API key. It returns domain.ErrAPIKeyRevoked
+// if the key was already revoked, so of concurrent revokes only the first one wins.
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, key *domain.APIKey) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE api_keys SET revoked_at = $1, updated_at = $2 WHERE id = $3 AND revoked_at IS NULL`
+
+	result, err := q.ExecContext(ctx, query, key.RevokedAt, key.UpdatedAt, key.ID)
+	if err != nil {
+		return fmt.Errorf("error revoking API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		// API keys are never deleted, so the key was revoked in the meantime.
+		return domain.ErrAPIKeyRevoked
+	}
+
+	return nil
+}
+
+// scanAPIKeyOrNil scans a single API key row, returning nil, nil if there is none.
+func scanAPIKeyOrNil(row *sql.Row) (*domain.APIKey, error) {
+	key, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+	}
+	return key, err
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var scopes pq.StringArray
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.SecretHash, &scopes, &expiresAt, &lastUsedAt, &revokedAt, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error scanning API key: %w", err)
+	}
+
+	k.Scopes = make([]domain.Scope, len(scopes))
+	for i, s := range scopes {
+		k.Scopes[i] = domain.Scope(s)
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+
+	return &k, nil
+}
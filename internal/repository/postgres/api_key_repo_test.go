@@ -0,0 +1,137 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyRepositorySuite struct {
+	suite.Suite
+
+	db   *sql.DB
+	repo *postgres.PostgresAPIKeyRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *APIKeyRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewAPIKeyRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *APIKeyRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *APIKeyRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE api_keys RESTART IDENTITY")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestAPIKeyRepository(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositorySuite))
+}
+
+// TestSaveFindTouchAndRevoke tests the full lifecycle of an API key.
+func (s *APIKeyRepositorySuite) TestSaveFindTouchAndRevoke() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+	key, plain, err := domain.NewAPIKey("warehouse", []domain.Scope{domain.ScopeReadAnyOrder, domain.ScopeTransitionOrders}, &expiresAt)
+	assert.NoError(err)
+
+	// Act
+	err = s.repo.Save(ctx, key)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(key.ID)
+
+	prefix, secret, err := domain.ParseAPIKey(plain)
+	assert.NoError(err)
+	found, err := s.repo.FindByPrefix(ctx, prefix)
+	assert.NoError(err)
+	assert.NotNil(found)
+	assert.True(found.Matches(secret))
+	assert.Equal([]domain.Scope{domain.ScopeReadAnyOrder, domain.ScopeTransitionOrders}, found.Scopes)
+	assert.True(expiresAt.Equal(*found.ExpiresAt))
+	assert.Nil(found.LastUsedAt)
+	assert.Nil(found.RevokedAt)
+
+	// Record a use and revoke it.
+	assert.True(found.Touch(time.Now()))
+	assert.NoError(s.repo.TouchLastUsed(ctx, found))
+	assert.NoError(found.Revoke())
+	assert.NoError(s.repo.Revoke(ctx, found))
+
+	updated, err := s.repo.FindByID(ctx, key.ID)
+	assert.NoError(err)
+	assert.NotNil(updated.LastUsedAt)
+	assert.NotNil(updated.RevokedAt)
+
+	all, err := s.repo.FindAll(ctx)
+	assert.NoError(err)
+	assert.Len(all, 1)
+}
+
+// TestTouchAfterRevoke tests that recording a use read before a revoke
+// does not undo the revoke, and that a key cannot be revoked twice.
+func (s *APIKeyRepositorySuite) TestTouchAfterRevoke() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	key, _, err := domain.NewAPIKey("warehouse", []domain.Scope{domain.ScopeReadAnyOrder}, nil)
+	assert.NoError(err)
+	assert.NoError(s.repo.Save(ctx, key))
+
+	// Both requests read the key before either writes.
+	authenticated, err := s.repo.FindByID(ctx, key.ID)
+	assert.NoError(err)
+	revoking, err := s.repo.FindByID(ctx, key.ID)
+	assert.NoError(err)
+	staleRevoke, err := s.repo.FindByID(ctx, key.ID)
+	assert.NoError(err)
+
+	assert.NoError(revoking.Revoke())
+	assert.NoError(s.repo.Revoke(ctx, revoking))
+	assert.True(authenticated.Touch(time.Now()))
+	assert.NoError(s.repo.TouchLastUsed(ctx, authenticated))
+
+	updated, err := s.repo.FindByID(ctx, key.ID)
+	assert.NoError(err)
+	assert.NotNil(updated.LastUsedAt)
+	assert.NotNil(updated.RevokedAt)
+
+	assert.NoError(staleRevoke.Revoke())
+	assert.ErrorIs(s.repo.Revoke(ctx, staleRevoke), domain.ErrAPIKeyRevoked)
+}
+
+func (s *APIKeyRepositorySuite) TestFind_NotFound() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	byPrefix, err := s.repo.FindByPrefix(ctx, "gos_missing")
+	assert.NoError(err)
+	assert.Nil(byPrefix)
+
+	byID, err := s.repo.FindByID(ctx, 999)
+	assert.NoError(err)
+	assert.Nil(byID)
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresCartRepository implements the usecase.CartRepository interface.
+var _ usecase.CartRepository = (*PostgresCartRepository)(nil)
+
+type PostgresCartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *PostgresCartRepository {
+	return &PostgresCartRepository{db: db}
+}
+
+// Save inserts a new cart. Its items are saved separately with SaveItem.
+func (r *PostgresCartRepository) Save(ctx context.Context, cart *domain.Cart) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO carts (user_id, currency, status, created_at, updated_at) 
+			   VALUES ($1, $2, $3, $4, $5) 
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		cart.UserID,
+		cart.Currency,
+		cart.Status,
+		cart.CreatedAt,
+		cart.UpdatedAt,
+	).Scan(&cart.ID)
+	if err != nil {
+		return fmt.Errorf("error saving cart: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a cart together with its items.
+func (r *PostgresCartRepository) FindByID(ctx context.Context, id int64) (*domain.Cart, error) {
+	query := `SELECT id, user_id, currency, status, order_id, created_at, updated_at FROM carts WHERE id = $1`
+
+	return r.find(ctx, query, id)
+}
+
+// FindByIDForUpdate retrieves a cart and locks its row until the surrounding
+// transaction ends, so the same cart cannot be checked out twice concurrently.
+// It must be called within a transaction to have any effect.
+func (r *PostgresCartRepository) FindByIDForUpdate(ctx context.Context, id int64) (*domain.Cart, error) {
+	query := `SELECT id, user_id, currency, status, order_id, created_at, updated_at FROM carts WHERE id = $1 FOR UPDATE`
+
+	return r.find(ctx, query, id)
+}
+
+// find runs a cart query that takes the cart ID as its only argument and loads the cart items.
+func (r *PostgresCartRepository) find(ctx context.Context, query string, id int64) (*domain.Cart, error) {
+	q := getQuerier(ctx, r.db)
+
+	var c domain.Cart
+	var orderID sql.NullInt64
+	err := q.QueryRowContext(ctx, query, id).Scan(
+		&c.ID, &c.UserID, &c.Currency, &c.Status, &orderID, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning cart: %w", err)
+	}
+	if orderID.Valid {
+		c.OrderID = &orderID.Int64
+	}
+
+	itemQuery := `SELECT id, cart_id, product_id, quantity FROM cart_items WHERE cart_id = $1 ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, itemQuery, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying cart items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.CartItem
+		if err := rows.Scan(&item.ID, &item.CartID, &item.ProductID, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("error scanning cart item row: %w", err)
+		}
+		c.Items = append(c.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Update persists the status and order of a cart.
+func (r *PostgresCartRepository) Update(ctx context.Context, cart *domain.Cart) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE carts SET status = $1, order_id = $2, updated_at = $3 WHERE id = $4`
+
+	result, err := q.ExecContext(ctx, query, cart.Status, cart.OrderID, time.Now(), cart.ID)
+	if err != nil {
+		return fmt.Errorf("error updating cart: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("cart not found for update")
+	}
+
+	return nil
+}
+
+// SaveItem inserts a cart item or replaces the quantity of the same product in the cart.
+func (r *PostgresCartRepository) SaveItem(ctx context.Context, item *domain.CartItem) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO cart_items (cart_id, product_id, quantity) 
+			   VALUES ($1, $2, $3) 
+			   ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity 
+			   RETURNING id`
+
+	if err := q.QueryRowContext(ctx, query, item.CartID, item.ProductID, item.Quantity).Scan(&item.ID); err != nil {
+		return fmt.Errorf("error saving cart item: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteItem removes a product from a cart.
+func (r *PostgresCartRepository) DeleteItem(ctx context.Context, cartID, productID int64) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2`
+
+	result, err := q.ExecContext(ctx, query, cartID, productID)
+	if err != nil {
+		return fmt.Errorf("error deleting cart item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("cart item not found for delete")
+	}
+
+	return nil
+}
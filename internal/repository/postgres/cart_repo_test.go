@@ -0,0 +1,119 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type CartRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresCartRepository
+	productRepo *postgres.PostgresProductRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *CartRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewCartRepository(s.db)
+	s.productRepo = postgres.NewProductRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *CartRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *CartRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE cart_items, carts, products, orders, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestCartRepository(t *testing.T) {
+	suite.Run(t, new(CartRepositorySuite))
+}
+
+// TestCartLifecycle tests saving a cart, changing its items and checking it out.
+func (s *CartRepositorySuite) TestCartLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Gula Aren", Price: idr("30000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	cart := domain.NewCart(123, "")
+	assert.NoError(s.repo.Save(ctx, cart))
+	assert.NotZero(cart.ID)
+
+	// Adding the same product twice updates the existing row.
+	item, err := cart.AddItem(product.ID, 2)
+	assert.NoError(err)
+	assert.NoError(s.repo.SaveItem(ctx, item))
+	item, err = cart.AddItem(product.ID, 1)
+	assert.NoError(err)
+	assert.NoError(s.repo.SaveItem(ctx, item))
+
+	found, err := s.repo.FindByID(ctx, cart.ID)
+	assert.NoError(err)
+	assert.Equal(domain.CartStatusOpen, found.Status)
+	assert.Len(found.Items, 1)
+	assert.Equal(3, found.Items[0].Quantity)
+
+	// Removing the product empties the cart.
+	assert.NoError(s.repo.DeleteItem(ctx, cart.ID, product.ID))
+	found, err = s.repo.FindByID(ctx, cart.ID)
+	assert.NoError(err)
+	assert.Empty(found.Items)
+	assert.Error(s.repo.DeleteItem(ctx, cart.ID, product.ID))
+
+	// A missing cart is reported as nil without an error.
+	missing, err := s.repo.FindByID(ctx, 99999)
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// TestUpdate tests that the checkout of a cart is persisted.
+func (s *CartRepositorySuite) TestUpdate() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+
+	var orderID int64
+	err := s.db.QueryRowContext(ctx,
+		"INSERT INTO orders (user_id, total_amount, status) VALUES (123, 0, 'pending') RETURNING id").Scan(&orderID)
+	assert.NoError(err)
+
+	cart := domain.NewCart(123, "USD")
+	assert.NoError(s.repo.Save(ctx, cart))
+	cart.Status = domain.CartStatusCheckedOut
+	cart.OrderID = &orderID
+
+	// Act
+	err = s.repo.Update(ctx, cart)
+
+	// Assert
+	assert.NoError(err)
+	found, err := s.repo.FindByID(ctx, cart.ID)
+	assert.NoError(err)
+	assert.Equal(domain.CartStatusCheckedOut, found.Status)
+	assert.Equal(orderID, *found.OrderID)
+	assert.Equal("USD", found.Currency)
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/lib/pq"
+)
+
+// Ensure PostgresCouponRepository implements the usecase.CouponRepository interface.
+var _ usecase.CouponRepository = (*PostgresCouponRepository)(nil)
+
+type PostgresCouponRepository struct {
+	db *sql.DB
+}
+
+func NewCouponRepository(db *sql.DB) *PostgresCouponRepository {
+	return &PostgresCouponRepository{db: db}
+}
+
+const couponColumns = `id, code, type, percent, amount, min_subtotal, currency, product_ids,
+			   max_uses, max_uses_per_user, used_count, starts_at, ends_at, created_at, updated_at`
+
+// Save inserts a new coupon. Its amount and minimum subtotal share one currency column.
+func (r *PostgresCouponRepository) Save(ctx context.Context, coupon *domain.Coupon) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO coupons (code, type, percent, amount, min_subtotal, currency, product_ids,
+			   max_uses, max_uses_per_user, used_count, starts_at, ends_at, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			   RETURNING id`
+
+	productIDs := coupon.ProductIDs
+	if productIDs == nil {
+		productIDs = []int64{}
+	}
+
+	err := q.QueryRowContext(ctx, query,
+		coupon.Code,
+		coupon.Type,
+		coupon.Percent,
+		coupon.Amount,
+		coupon.MinSubtotal,
+		couponCurrency(coupon),
+		pq.Array(productIDs),
+		coupon.MaxUses,
+		coupon.MaxUsesPerUser,
+		coupon.UsedCount,
+		coupon.StartsAt,
+		coupon.EndsAt,
+		coupon.CreatedAt,
+		coupon.UpdatedAt,
+	).Scan(&coupon.ID)
+	if err != nil {
+		return fmt.Errorf("error saving coupon: %w", err)
+	}
+
+	return nil
+}
+
+// FindByCode retrieves a coupon by its code.
+func (r *PostgresCouponRepository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE code = $1`
+
+	return r.find(ctx, query, code)
+}
+
+// FindByCodeForUpdate retrieves a coupon and locks its row until the surrounding
+// transaction ends, so concurrent orders cannot redeem it beyond its usage limits.
+// It must be called within a transaction to have any effect.
+func (r *PostgresCouponRepository) FindByCodeForUpdate(ctx context.Context, code string) (*domain.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE code = $1 FOR UPDATE`
+
+	return r.find(ctx, query, code)
+}
+
+// find runs a coupon query that takes the coupon code as its only argument.
+func (r *PostgresCouponRepository) find(ctx context.Context, query string, code string) (*domain.Coupon, error) {
+	q := getQuerier(ctx, r.db)
+
+	var c domain.Coupon
+	var currency string
+	var productIDs pq.Int64Array
+	var startsAt, endsAt sql.NullTime
+	err := q.QueryRowContext(ctx, query, code).Scan(
+		&c.ID, &c.Code, &c.Type, &c.Percent, &c.Amount, &c.MinSubtotal, &currency, &productIDs,
+		&c.MaxUses, &c.MaxUsesPerUser, &c.UsedCount, &startsAt, &endsAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning coupon: %w", err)
+	}
+
+	c.Amount.Currency = currency
+	c.MinSubtotal.Currency = currency
+	if len(productIDs) > 0 {
+		c.ProductIDs = productIDs
+	}
+	if startsAt.Valid {
+		c.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		c.EndsAt = &endsAt.Time
+	}
+
+	return &c, nil
+}
+
+// CountRedemptionsByUser returns how many times a user has redeemed a coupon.
+func (r *PostgresCouponRepository) CountRedemptionsByUser(ctx context.Context, couponID, userID int64) (int, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2`
+
+	var count int
+	if err := q.QueryRowContext(ctx, query, couponID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting coupon redemptions: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update persists the usage counter of a coupon.
+func (r *PostgresCouponRepository) Update(ctx context.Context, coupon *domain.Coupon) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE coupons SET used_count = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := q.ExecContext(ctx, query, coupon.UsedCount, time.Now(), coupon.ID)
+	if err != nil {
+		return fmt.Errorf("error updating coupon: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("coupon not found for update")
+	}
+
+	return nil
+}
+
+// SaveRedemption records that an order redeemed a coupon.
+func (r *PostgresCouponRepository) SaveRedemption(ctx context.Context, redemption *domain.CouponRedemption) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, discount_amount, created_at)
+			   VALUES ($1, $2, $3, $4, $5)
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		redemption.CouponID,
+		redemption.UserID,
+		redemption.OrderID,
+		redemption.Discount,
+		redemption.CreatedAt,
+	).Scan(&redemption.ID)
+	if err != nil {
+		return fmt.Errorf("error saving coupon redemption: %w", err)
+	}
+
+	return nil
+}
+
+// couponCurrency returns the currency of the coupon amounts, which are validated to share one.
+func couponCurrency(c *domain.Coupon) string {
+	if !c.Amount.IsZero() {
+		return c.Amount.Currency
+	}
+	if !c.MinSubtotal.IsZero() {
+		return c.MinSubtotal.Currency
+	}
+	return domain.DefaultCurrency
+}
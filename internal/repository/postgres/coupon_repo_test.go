@@ -0,0 +1,167 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+type CouponRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresCouponRepository
+	productRepo *postgres.PostgresProductRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *CouponRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewCouponRepository(s.db)
+	s.productRepo = postgres.NewProductRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *CouponRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *CouponRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE coupon_redemptions, coupons, order_items, orders, products, outbox, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestCouponRepository(t *testing.T) {
+	suite.Run(t, new(CouponRepositorySuite))
+}
+
+// TestSaveAndFind tests that a coupon round-trips through the database.
+func (s *CouponRepositorySuite) TestSaveAndFind() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	endsAt := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+	coupon := &domain.Coupon{
+		Code:           "FLAT5",
+		Type:           domain.CouponTypeFixed,
+		Amount:         domain.NewMoney(500, "USD"),
+		MinSubtotal:    domain.NewMoney(2000, "USD"),
+		ProductIDs:     []int64{1, 2},
+		MaxUses:        100,
+		MaxUsesPerUser: 1,
+		EndsAt:         &endsAt,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	assert.NoError(s.repo.Save(ctx, coupon))
+	assert.NotZero(coupon.ID)
+
+	found, err := s.repo.FindByCode(ctx, "FLAT5")
+	assert.NoError(err)
+	assert.Equal(domain.NewMoney(500, "USD"), found.Amount)
+	assert.Equal(domain.NewMoney(2000, "USD"), found.MinSubtotal)
+	assert.Equal([]int64{1, 2}, found.ProductIDs)
+	assert.Nil(found.StartsAt)
+	assert.True(endsAt.Equal(*found.EndsAt))
+
+	notFound, err := s.repo.FindByCode(ctx, "NOPE")
+	assert.NoError(err)
+	assert.Nil(notFound)
+}
+
+// TestCreateOrder_ConcurrentRedemptions fires many concurrent orders with the same coupon
+// and checks that it is never redeemed beyond its global usage limit.
+func (s *CouponRepositorySuite) TestCreateOrder_ConcurrentRedemptions() {
+	assert := s.Suite.Assert()
+	ctx := asAdmin()
+
+	const maxUses = 3
+	const buyers = 10
+
+	assert.NoError(createUsers(s.db, userIDs(buyers)...))
+
+	product := &domain.Product{Name: "Kopi Luwak", Price: idr("100000"), Quantity: 100}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	promotionUseCase := usecase.NewPromotionUseCase(s.repo)
+	_, err := promotionUseCase.CreateCoupon(ctx, dto.CreateCouponInput{
+		Code:    "launch",
+		Type:    domain.CouponTypePercentage,
+		Percent: 20,
+		MaxUses: maxUses,
+	})
+	assert.NoError(err)
+
+	orderUseCase := usecase.NewOrderUseCase(postgres.NewOrderRepository(s.db), s.productRepo, postgres.NewTransactionManager(s.db), postgres.NewOutboxRepository(s.db), postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), promotionUseCase, defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		rejected   int
+		unexpected []error
+	)
+
+	// Act
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			_, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+				UserID:     userID,
+				CouponCode: "LAUNCH",
+				Items:      []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 1}},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, domain.ErrCouponUsageLimitReached):
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}(int64(i + 1))
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Empty(unexpected)
+	assert.Equal(maxUses, succeeded)
+	assert.Equal(buyers-maxUses, rejected)
+
+	coupon, err := s.repo.FindByCode(ctx, "LAUNCH")
+	assert.NoError(err)
+	assert.Equal(maxUses, coupon.UsedCount)
+
+	var discounted int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders WHERE coupon_code = 'LAUNCH' AND discount_amount = 20000").Scan(&discounted)
+	assert.NoError(err)
+	assert.Equal(maxUses, discounted)
+
+	// The rejected orders must not have taken any stock.
+	found, err := s.productRepo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(100-maxUses, found.Quantity)
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresExchangeRateRepository implements the usecase.RateProvider interface.
+var _ usecase.RateProvider = (*PostgresExchangeRateRepository)(nil)
+
+// PostgresExchangeRateRepository serves exchange rates from the exchange_rates table.
+type PostgresExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *PostgresExchangeRateRepository {
+	return &PostgresExchangeRateRepository{db: db}
+}
+
+// Save inserts an exchange rate or replaces the existing rate of the same currency pair.
+func (r *PostgresExchangeRateRepository) Save(ctx context.Context, rate *domain.ExchangeRate) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO exchange_rates (from_currency, to_currency, rate, updated_at) 
+			   VALUES ($1, $2, $3, $4) 
+			   ON CONFLICT (from_currency, to_currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at`
+
+	rate.UpdatedAt = time.Now()
+	if _, err := q.ExecContext(ctx, query, rate.From, rate.To, rate.Rate, rate.UpdatedAt); err != nil {
+		return fmt.Errorf("error saving exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+// FindRate retrieves the current rate of a currency pair.
+func (r *PostgresExchangeRateRepository) FindRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT from_currency, to_currency, rate, updated_at FROM exchange_rates WHERE from_currency = $1 AND to_currency = $2`
+	var rate domain.ExchangeRate
+
+	err := q.QueryRowContext(ctx, query, from, to).Scan(&rate.From, &rate.To, &rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning exchange rate: %w", err)
+	}
+
+	return &rate, nil
+}
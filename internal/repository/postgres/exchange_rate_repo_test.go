@@ -0,0 +1,79 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExchangeRateRepositorySuite struct {
+	suite.Suite
+
+	db   *sql.DB
+	repo *postgres.PostgresExchangeRateRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *ExchangeRateRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewExchangeRateRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *ExchangeRateRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *ExchangeRateRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE exchange_rates")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestExchangeRateRepository(t *testing.T) {
+	suite.Run(t, new(ExchangeRateRepositorySuite))
+}
+
+// TestSaveAndFindRate tests that a saved rate is found and can be replaced.
+func (s *ExchangeRateRepositorySuite) TestSaveAndFindRate() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	rate, err := domain.ParseRate("16250.5")
+	assert.NoError(err)
+	assert.NoError(s.repo.Save(ctx, &domain.ExchangeRate{From: "USD", To: "IDR", Rate: rate}))
+
+	// Act
+	found, err := s.repo.FindRate(ctx, "USD", "IDR")
+
+	// Assert
+	assert.NoError(err)
+	assert.NotNil(found)
+	assert.Equal(rate, found.Rate)
+
+	// Saving the same pair again replaces the rate.
+	newRate, err := domain.ParseRate("16300")
+	assert.NoError(err)
+	assert.NoError(s.repo.Save(ctx, &domain.ExchangeRate{From: "USD", To: "IDR", Rate: newRate}))
+	found, err = s.repo.FindRate(ctx, "USD", "IDR")
+	assert.NoError(err)
+	assert.Equal(newRate, found.Rate)
+
+	// Rates are directional.
+	found, err = s.repo.FindRate(ctx, "IDR", "USD")
+	assert.NoError(err)
+	assert.Nil(found)
+}
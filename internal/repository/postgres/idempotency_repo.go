@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresIdempotencyRepository implements the usecase.IdempotencyRepository interface.
+var _ usecase.IdempotencyRepository = (*PostgresIdempotencyRepository)(nil)
+
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+// Create inserts a new idempotency key and reports whether it was inserted.
+// If another transaction inserted the same key and has not finished yet, the
+// insert waits for it: it succeeds if that transaction rolls back and reports
+// false if it commits.
+func (r *PostgresIdempotencyRepository) Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO idempotency_keys (key, request_hash, created_at) 
+			   VALUES ($1, $2, $3) 
+			   ON CONFLICT (key) DO NOTHING`
+
+	result, err := q.ExecContext(ctx, query, key.Key, key.RequestHash, key.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("error saving idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// FindByKey retrieves an idempotency key.
+func (r *PostgresIdempotencyRepository) FindByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT key, request_hash, response, created_at, completed_at FROM idempotency_keys WHERE key = $1`
+	var k domain.IdempotencyKey
+	var completedAt sql.NullTime
+
+	err := q.QueryRowContext(ctx, query, key).Scan(&k.Key, &k.RequestHash, &k.Response, &k.CreatedAt, &completedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning idempotency key: %w", err)
+	}
+	if completedAt.Valid {
+		k.CompletedAt = &completedAt.Time
+	}
+
+	return &k, nil
+}
+
+// Update stores the response of an idempotency key.
+func (r *PostgresIdempotencyRepository) Update(ctx context.Context, key *domain.IdempotencyKey) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE idempotency_keys SET response = $1, completed_at = $2 WHERE key = $3`
+
+	result, err := q.ExecContext(ctx, query, key.Response, key.CompletedAt, key.Key)
+	if err != nil {
+		return fmt.Errorf("error updating idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("idempotency key not found for update")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyRepositorySuite struct {
+	suite.Suite
+
+	db   *sql.DB
+	repo *postgres.PostgresIdempotencyRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *IdempotencyRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewIdempotencyRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *IdempotencyRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *IdempotencyRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE idempotency_keys")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestIdempotencyRepository(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositorySuite))
+}
+
+// TestCreateFindAndUpdate tests the full lifecycle of an idempotency key.
+func (s *IdempotencyRepositorySuite) TestCreateFindAndUpdate() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	key := domain.NewIdempotencyKey("key-1", "hash-1")
+
+	// Act
+	created, err := s.repo.Create(ctx, key)
+
+	// Assert
+	assert.NoError(err)
+	assert.True(created)
+
+	// A second insert of the same key is reported, not failed.
+	created, err = s.repo.Create(ctx, domain.NewIdempotencyKey("key-1", "hash-2"))
+	assert.NoError(err)
+	assert.False(created)
+
+	key.Complete([]byte(`{"ID": 1}`))
+	assert.NoError(s.repo.Update(ctx, key))
+
+	found, err := s.repo.FindByKey(ctx, "key-1")
+	assert.NoError(err)
+	assert.NotNil(found)
+	assert.Equal("hash-1", found.RequestHash)
+	assert.JSONEq(`{"ID": 1}`, string(found.Response))
+	assert.NotNil(found.CompletedAt)
+}
+
+// TestFindByKey_NotFound tests that an unknown key returns nil without error.
+func (s *IdempotencyRepositorySuite) TestFindByKey_NotFound() {
+	assert := s.Suite.Assert()
+
+	found, err := s.repo.FindByKey(context.Background(), "missing")
+
+	assert.NoError(err)
+	assert.Nil(found)
+}
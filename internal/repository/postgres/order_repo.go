@@ -10,19 +10,12 @@ import (
 
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/lib/pq"
 )
 
 // Ensure PostgresOrderRepository implements the usecase.OrderRepository interface.
 var _ usecase.OrderRepository = (*PostgresOrderRepository)(nil)
 
-// querier is an interface that is satisfied by both *sql.DB and *sql.Tx.
-// This allows repository methods to work with or without a transaction.
-type querier interface {
-	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
-	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
-	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
-}
-
 type PostgresOrderRepository struct {
 	db *sql.DB
 }
@@ -31,32 +24,28 @@ func NewOrderRepository(db *sql.DB) *PostgresOrderRepository {
 	return &PostgresOrderRepository{db: db}
 }
 
-// getQuerier extracts a transaction from the context if it exists,
-// otherwise it returns the base database connection.
-func (r *PostgresOrderRepository) getQuerier(ctx context.Context) querier {
-	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
-	if ok {
-		return tx
-	}
-
-	return r.db
-}
-
 // Save inserts a new order and its items into the database.
 func (r *PostgresOrderRepository) Save(ctx context.Context, order *domain.Order) error {
 	// Get the correct querier (either the transaction or the base DB connection).
-	q := r.getQuerier(ctx)
+	q := getQuerier(ctx, r.db)
 
 	// Insert the main order record into the 'orders' table.
 	// Use RETURNING to get the generated order ID back immediately.
-	orderQuery := `INSERT INTO orders (user_id, total_amount, status, created_at, updated_at) 
-                   VALUES ($1, $2, $3, $4, $5) 
+	orderQuery := `INSERT INTO orders (user_id, subtotal, discount_amount, coupon_code, tax_amount, tax_region, total_amount, currency, shipping_address, status, created_at, updated_at) 
+                   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) 
                    RETURNING id, created_at, updated_at`
 
 	now := time.Now()
 	err := q.QueryRowContext(ctx, orderQuery,
 		order.UserID,
+		order.Subtotal,
+		order.Discount,
+		order.CouponCode,
+		order.Tax,
+		order.TaxRegion,
 		order.TotalAmount,
+		order.TotalAmount.Currency,
+		order.ShippingAddress,
 		order.Status,
 		now,
 		now,
@@ -66,16 +55,18 @@ func (r *PostgresOrderRepository) Save(ctx context.Context, order *domain.Order)
 	}
 
 	// Insert all order items into the 'order_items' table.
-	itemQuery := `INSERT INTO order_items (order_id, product_id, quantity, price_at_order) VALUES `
+	itemQuery := `INSERT INTO order_items (order_id, product_id, quantity, price_at_order, base_price, base_currency, exchange_rate, tax_rate, tax_amount) VALUES `
 
 	vals := []interface{}{}
 	var placeholders []string
 
 	for i, item := range order.OrderItems {
-		p_num := i * 4
-		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)", p_num+1, p_num+2, p_num+3, p_num+4))
+		p_num := i * 9
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			p_num+1, p_num+2, p_num+3, p_num+4, p_num+5, p_num+6, p_num+7, p_num+8, p_num+9))
 
-		vals = append(vals, order.ID, item.Product.ID, item.Quantity, item.PriceAtOrder)
+		vals = append(vals, order.ID, item.Product.ID, item.Quantity, item.PriceAtOrder,
+			item.BasePrice, item.BasePrice.Currency, item.ExchangeRate, item.TaxRate, item.Tax)
 	}
 
 	itemQuery += strings.Join(placeholders, ", ")
@@ -112,3 +103,176 @@ func (r *PostgresOrderRepository) Save(ctx context.Context, order *domain.Order)
 
 	return nil
 }
+
+// UpdateStatus persists the current status of an existing order.
+func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, order *domain.Order) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := q.ExecContext(ctx, query, order.Status, order.UpdatedAt, order.ID)
+	if err != nil {
+		return fmt.Errorf("error updating order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("order not found for status update")
+	}
+
+	return nil
+}
+
+// FindByID retrieves a single order together with its items and their products.
+func (r *PostgresOrderRepository) FindByID(ctx context.Context, id int64) (*domain.Order, error) {
+	return r.findOne(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id)
+}
+
+// FindByIDForUpdate retrieves a single order like FindByID and locks it until the
+// transaction ends, so that concurrent changes to the order are serialized.
+func (r *PostgresOrderRepository) FindByIDForUpdate(ctx context.Context, id int64) (*domain.Order, error) {
+	return r.findOne(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1 FOR UPDATE`, id)
+}
+
+// findOne retrieves the single order selected by query with its items, or nil, nil if there is none.
+func (r *PostgresOrderRepository) findOne(ctx context.Context, query string, args ...interface{}) (*domain.Order, error) {
+	q := getQuerier(ctx, r.db)
+
+	o, err := scanOrder(q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning order: %w", err)
+	}
+
+	orders := []domain.Order{*o}
+	if err := r.loadOrderItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+
+	return &orders[0], nil
+}
+
+// FindByUserID retrieves a paginated list of a user's orders, newest first.
+func (r *PostgresOrderRepository) FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]domain.Order, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + orderColumns + ` 
+			   FROM orders 
+			   WHERE user_id = $1 
+			   ORDER BY created_at DESC, id DESC 
+			   LIMIT $2 OFFSET $3`
+
+	rows, err := q.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []domain.Order
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning order row: %w", err)
+		}
+		orders = append(orders, *o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	if err := r.loadOrderItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+const orderColumns = `id, user_id, subtotal, discount_amount, coupon_code, tax_amount, tax_region, total_amount, currency, shipping_address, status, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrder scans a row selected with orderColumns. All amounts of an order share its currency.
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var o domain.Order
+	var shippingAddress []byte
+	err := row.Scan(
+		&o.ID, &o.UserID, &o.Subtotal, &o.Discount, &o.CouponCode, &o.Tax, &o.TaxRegion, &o.TotalAmount, &o.TotalAmount.Currency,
+		&shippingAddress, &o.Status, &o.CreatedAt, &o.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if shippingAddress != nil {
+		o.ShippingAddress = &domain.PostalAddress{}
+		if err := o.ShippingAddress.Scan(shippingAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	o.Subtotal.Currency = o.TotalAmount.Currency
+	o.Discount.Currency = o.TotalAmount.Currency
+	o.Tax.Currency = o.TotalAmount.Currency
+	return &o, nil
+}
+
+// loadOrderItems fetches the items of all given orders in a single query,
+// joined with their products, and attaches them to the matching order.
+func (r *PostgresOrderRepository) loadOrderItems(ctx context.Context, q querier, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]int64, len(orders))
+	indexByID := make(map[int64]int, len(orders))
+	for i, o := range orders {
+		orderIDs[i] = o.ID
+		indexByID[o.ID] = i
+	}
+
+	query := `SELECT oi.id, oi.order_id, oi.quantity, oi.price_at_order, o.currency, 
+			   oi.base_price, oi.base_currency, oi.exchange_rate, oi.tax_rate, oi.tax_amount, 
+			   p.id, p.name, p.price, p.currency, p.tax_category, p.quantity, p.created_at, p.updated_at 
+			   FROM order_items oi 
+			   JOIN orders o ON o.id = oi.order_id 
+			   JOIN products p ON p.id = oi.product_id 
+			   WHERE oi.order_id = ANY($1) 
+			   ORDER BY oi.id ASC`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(orderIDs))
+	if err != nil {
+		return fmt.Errorf("error querying order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.OrderItem
+		p := &item.Product
+		if err := rows.Scan(
+			&item.ID, &item.OrderID, &item.Quantity, &item.PriceAtOrder, &item.PriceAtOrder.Currency,
+			&item.BasePrice, &item.BasePrice.Currency, &item.ExchangeRate, &item.TaxRate, &item.Tax,
+			&p.ID, &p.Name, &p.Price, &p.Price.Currency, &p.TaxCategory, &p.Quantity, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("error scanning order item row: %w", err)
+		}
+		item.Tax.Currency = item.PriceAtOrder.Currency
+
+		i := indexByID[item.OrderID]
+		orders[i].OrderItems = append(orders[i].OrderItems, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return nil
+}
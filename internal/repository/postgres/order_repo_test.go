@@ -3,13 +3,18 @@ package postgres_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"sync"
 	"testing"
 
 	"github.com/elokanugrah/go-order-system/internal/config"
 	"github.com/elokanugrah/go-order-system/internal/database"
 	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
 	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/tax"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -42,7 +47,7 @@ func (s *OrderRepositorySuite) TearDownSuite() {
 // TearDownTest runs after each test function.
 // It cleans all relevant tables to ensure test isolation.
 func (s *OrderRepositorySuite) TearDownTest() {
-	_, err := s.db.Exec("TRUNCATE TABLE order_items, orders, products RESTART IDENTITY CASCADE")
+	_, err := s.db.Exec("TRUNCATE TABLE order_items, orders, products, outbox, idempotency_keys, addresses, users RESTART IDENTITY CASCADE")
 	s.Suite.NoError(err)
 }
 
@@ -56,8 +61,10 @@ func (s *OrderRepositorySuite) TestSave() {
 	assert := s.Suite.Assert()
 	ctx := context.Background()
 
-	product1 := &domain.Product{Name: "Laptop", Price: 15000000, Quantity: 10}
-	product2 := &domain.Product{Name: "Mouse", Price: 500000, Quantity: 20}
+	assert.NoError(createUsers(s.db, 123))
+
+	product1 := &domain.Product{Name: "Laptop", Price: idr("15000000"), Quantity: 10}
+	product2 := &domain.Product{Name: "Mouse", Price: idr("500000"), Quantity: 20}
 	err := s.productRepo.Save(ctx, product1)
 	assert.NoError(err)
 	err = s.productRepo.Save(ctx, product2)
@@ -68,13 +75,13 @@ func (s *OrderRepositorySuite) TestSave() {
 		UserID: 123,
 		Status: domain.StatusPending,
 		OrderItems: []domain.OrderItem{
-			{Product: *product1, Quantity: 1, PriceAtOrder: product1.Price},
-			{Product: *product2, Quantity: 2, PriceAtOrder: product2.Price},
+			{Product: *product1, Quantity: 1, PriceAtOrder: product1.Price, BasePrice: product1.Price, ExchangeRate: domain.IdentityRate},
+			{Product: *product2, Quantity: 2, PriceAtOrder: product2.Price, BasePrice: product2.Price, ExchangeRate: domain.IdentityRate},
 		},
 	}
 
-	orderToSave.CalculateTotalAmount()
-	expectedTotal := (1 * 15000000.0) + (2 * 500000.0)
+	assert.NoError(orderToSave.CalculateTotalAmount())
+	expectedTotal := idr("16000000") // (1 * 15000000) + (2 * 500000)
 	assert.Equal(expectedTotal, orderToSave.TotalAmount)
 
 	tx, err := s.db.Begin()
@@ -99,7 +106,7 @@ func (s *OrderRepositorySuite) TestSave() {
 
 	// Verify the 'orders' table
 	var dbUserID int64
-	var dbTotal float64
+	var dbTotal domain.Money
 	err = tx.QueryRowContext(ctx, "SELECT user_id, total_amount FROM orders WHERE id = $1", orderToSave.ID).Scan(&dbUserID, &dbTotal)
 	assert.NoError(err)
 	assert.Equal(int64(123), dbUserID)
@@ -126,3 +133,360 @@ func (s *OrderRepositorySuite) TestSave() {
 
 	tx.Commit()
 }
+
+// TestFindByIDAndFindByUserID tests reading orders back with their items hydrated.
+func (s *OrderRepositorySuite) TestFindByIDAndFindByUserID() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 321))
+
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	first := &domain.Order{
+		UserID:     321,
+		Status:     domain.StatusPending,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 2, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(first.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, first))
+
+	second := &domain.Order{
+		UserID:     321,
+		Status:     domain.StatusPending,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 1, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(second.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, second))
+
+	// Act
+	found, err := s.orderRepo.FindByID(ctx, first.ID)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotNil(found)
+	assert.Equal(int64(321), found.UserID)
+	assert.Equal(first.TotalAmount, found.TotalAmount)
+	assert.Len(found.OrderItems, 1)
+	assert.Equal(2, found.OrderItems[0].Quantity)
+	assert.Equal("Keyboard", found.OrderItems[0].Product.Name)
+
+	orders, err := s.orderRepo.FindByUserID(ctx, 321, 10, 0)
+	assert.NoError(err)
+	assert.Len(orders, 2)
+	assert.Equal(second.ID, orders[0].ID) // Newest first
+	assert.Len(orders[0].OrderItems, 1)
+	assert.Len(orders[1].OrderItems, 1)
+
+	// A missing order is reported as nil without an error.
+	missing, err := s.orderRepo.FindByID(ctx, 99999)
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// TestFindByID_ExchangeRateSnapshot tests that the currency and conversion of every item survive a round trip.
+func (s *OrderRepositorySuite) TestFindByID_ExchangeRateSnapshot() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 321))
+
+	product := &domain.Product{Name: "Headphones", Price: idr("1625000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	rate, err := domain.ParseRate("0.00006154")
+	assert.NoError(err)
+	order, err := domain.NewOrder(321, []domain.OrderItem{{
+		Product:      *product,
+		Quantity:     1,
+		PriceAtOrder: product.Price.Convert("USD", rate),
+		BasePrice:    product.Price,
+		ExchangeRate: rate,
+	}})
+	assert.NoError(err)
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	// Act
+	found, err := s.orderRepo.FindByID(ctx, order.ID)
+
+	// Assert
+	assert.NoError(err)
+	assert.Equal(domain.NewMoney(10000, "USD"), found.TotalAmount)
+	item := found.OrderItems[0]
+	assert.Equal(domain.NewMoney(10000, "USD"), item.PriceAtOrder)
+	assert.Equal(idr("1625000"), item.BasePrice)
+	assert.Equal(rate, item.ExchangeRate)
+}
+
+// TestFindByID_ShippingAddress tests that the shipping address snapshot survives a round trip.
+func (s *OrderRepositorySuite) TestFindByID_ShippingAddress() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+
+	product := &domain.Product{Name: "Speaker", Price: idr("900000"), Quantity: 5}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	order, err := domain.NewOrder(123, []domain.OrderItem{{Product: *product, Quantity: 1, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}})
+	assert.NoError(err)
+	address := jakartaAddress()
+	order.ShippingAddress = &address
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	// Act
+	found, err := s.orderRepo.FindByID(ctx, order.ID)
+
+	// Assert
+	assert.NoError(err)
+	assert.Equal(&address, found.ShippingAddress)
+}
+
+// TestUpdateStatus tests that a status change is persisted.
+func (s *OrderRepositorySuite) TestUpdateStatus() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+
+	product := &domain.Product{Name: "Monitor", Price: idr("2500000"), Quantity: 5}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	order := &domain.Order{
+		UserID:     123,
+		Status:     domain.StatusPending,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 1, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(order.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	// Act
+	assert.NoError(order.ChangeStatus(domain.StatusPaid))
+	err := s.orderRepo.UpdateStatus(ctx, order)
+
+	// Assert
+	assert.NoError(err)
+	found, err := s.orderRepo.FindByID(ctx, order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusPaid, found.Status)
+}
+
+// TestCreateOrder_ConcurrentStockReservation fires many concurrent orders at a single
+// product and checks that stock is never oversold.
+func (s *OrderRepositorySuite) TestCreateOrder_ConcurrentStockReservation() {
+	assert := s.Suite.Assert()
+	ctx := asAdmin()
+
+	const stock = 5
+	const buyers = 20
+
+	assert.NoError(createUsers(s.db, userIDs(buyers)...))
+
+	product := &domain.Product{Name: "Limited Edition", Price: idr("100000"), Quantity: stock}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, postgres.NewTransactionManager(s.db), postgres.NewOutboxRepository(s.db), postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		succeeded    int
+		insufficient int
+		unexpected   []error
+	)
+
+	// Act
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			_, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+				UserID: userID,
+				Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 1}},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, domain.ErrInsufficientStock):
+				insufficient++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}(int64(i + 1))
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Empty(unexpected)
+	assert.Equal(stock, succeeded)
+	assert.Equal(buyers-stock, insufficient)
+
+	found, err := s.productRepo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(0, found.Quantity)
+
+	var orderedUnits int
+	err = s.db.QueryRowContext(ctx, "SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE product_id = $1", product.ID).Scan(&orderedUnits)
+	assert.NoError(err)
+	assert.Equal(stock, orderedUnits)
+
+	// Exactly one event must have been recorded per successful order.
+	var events int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox WHERE queue_name = 'orders.created'").Scan(&events)
+	assert.NoError(err)
+	assert.Equal(stock, events)
+}
+
+// TestCreateOrderWithIdempotencyKey_ConcurrentRetries fires the same request with the same
+// idempotency key concurrently and checks that the order is created exactly once.
+func (s *OrderRepositorySuite) TestCreateOrderWithIdempotencyKey_ConcurrentRetries() {
+	assert := s.Suite.Assert()
+	ctx := asAdmin()
+
+	const retries = 10
+
+	assert.NoError(createUsers(s.db, 1))
+
+	product := &domain.Product{Name: "Keyboard", Price: idr("100000"), Quantity: 50}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, postgres.NewTransactionManager(s.db), postgres.NewOutboxRepository(s.db), postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	input := dto.CreateOrderInput{
+		UserID: 1,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 2}},
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		orderIDs   = make(map[int64]bool)
+		replays    int
+		unexpected []error
+	)
+
+	// Act
+	for i := 0; i < retries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order, replayed, err := orderUseCase.CreateOrderWithIdempotencyKey(ctx, "retry-key", input)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				unexpected = append(unexpected, err)
+				return
+			}
+			orderIDs[order.ID] = true
+			if replayed {
+				replays++
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Empty(unexpected)
+	assert.Len(orderIDs, 1)
+	assert.Equal(retries-1, replays)
+
+	found, err := s.productRepo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(48, found.Quantity)
+}
+
+// TestCancelOrder_Concurrent cancels the same order concurrently and checks that
+// its stock is restored exactly once.
+func (s *OrderRepositorySuite) TestCancelOrder_Concurrent() {
+	assert := s.Suite.Assert()
+	ctx := asAdmin()
+
+	const cancellers = 10
+
+	assert.NoError(createUsers(s.db, 1))
+
+	product := &domain.Product{Name: "Keyboard", Price: idr("100000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, postgres.NewTransactionManager(s.db), postgres.NewOutboxRepository(s.db), postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID: 1,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 3}},
+	})
+	s.Suite.Require().NoError(err)
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		cancelled  int
+		rejected   int
+		unexpected []error
+	)
+
+	// Act
+	for i := 0; i < cancellers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := orderUseCase.CancelOrder(ctx, order.ID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				cancelled++
+			case errors.Is(err, domain.ErrInvalidStatusTransition):
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Empty(unexpected)
+	assert.Equal(1, cancelled)
+	assert.Equal(cancellers-1, rejected)
+
+	found, err := s.productRepo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(10, found.Quantity)
+
+	var events int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox WHERE queue_name = 'orders.cancelled'").Scan(&events)
+	assert.NoError(err)
+	assert.Equal(1, events)
+}
+
+// idr parses a decimal amount in the default currency, failing loudly on bad test data.
+func idr(amount string) domain.Money {
+	m, err := domain.ParseMoney(amount, domain.DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// defaultTaxCalculator returns the built-in tax rules, failing loudly if they are invalid.
+func defaultTaxCalculator() usecase.TaxCalculator {
+	calculator, err := tax.NewRuleCalculator(tax.DefaultRules())
+	if err != nil {
+		panic(err)
+	}
+	return calculator
+}
+
+// newUserUseCase returns a user use case backed by the test database.
+func newUserUseCase(db *sql.DB) *usecase.UserUseCase {
+	return usecase.NewUserUseCase(postgres.NewUserRepository(db), postgres.NewAddressRepository(db), postgres.NewOrderRepository(db))
+}
+
+// asAdmin returns a context authenticated as an admin, who may place orders for any user.
+func asAdmin() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: 1, Roles: []domain.Role{domain.RoleAdmin}})
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresOutboxRepository implements the usecase.OutboxRepository interface.
+var _ usecase.OutboxRepository = (*PostgresOutboxRepository)(nil)
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// Save inserts a new outbox message. It is meant to be called within the
+// transaction that produced the event.
+func (r *PostgresOutboxRepository) Save(ctx context.Context, msg *domain.OutboxMessage) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO outbox (queue_name, payload, next_attempt_at, created_at) 
+			   VALUES ($1, $2, $3, $4) 
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		msg.QueueName,
+		msg.Payload,
+		msg.NextAttemptAt,
+		msg.CreatedAt,
+	).Scan(&msg.ID)
+	if err != nil {
+		return fmt.Errorf("error saving outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// FindPending retrieves unsent messages that are due for a publish attempt, oldest first.
+// Within a transaction the rows stay locked until it ends, and rows locked by
+// another relay are skipped, so several relays can run side by side.
+func (r *PostgresOutboxRepository) FindPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, queue_name, payload, attempts, last_error, next_attempt_at, created_at, sent_at 
+			   FROM outbox 
+			   WHERE sent_at IS NULL AND next_attempt_at <= now() 
+			   ORDER BY id ASC 
+			   LIMIT $1 
+			   FOR UPDATE SKIP LOCKED`
+
+	rows, err := q.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var m domain.OutboxMessage
+		var sentAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.QueueName, &m.Payload, &m.Attempts, &m.LastError, &m.NextAttemptAt, &m.CreatedAt, &sentAt); err != nil {
+			return nil, fmt.Errorf("error scanning outbox row: %w", err)
+		}
+		if sentAt.Valid {
+			m.SentAt = &sentAt.Time
+		}
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Update persists the delivery state of an outbox message.
+func (r *PostgresOutboxRepository) Update(ctx context.Context, msg *domain.OutboxMessage) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE outbox 
+			   SET attempts = $1, last_error = $2, next_attempt_at = $3, sent_at = $4 
+			   WHERE id = $5`
+
+	result, err := q.ExecContext(ctx, query,
+		msg.Attempts,
+		msg.LastError,
+		msg.NextAttemptAt,
+		msg.SentAt,
+		msg.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating outbox message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("outbox message not found for update")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxRepositorySuite struct {
+	suite.Suite
+
+	db        *sql.DB
+	repo      *postgres.PostgresOutboxRepository
+	txManager usecase.TransactionManager
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *OutboxRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewOutboxRepository(s.db)
+	s.txManager = postgres.NewTransactionManager(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *OutboxRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *OutboxRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE outbox RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestOutboxRepository(t *testing.T) {
+	suite.Run(t, new(OutboxRepositorySuite))
+}
+
+// TestSaveFindPendingAndUpdate tests the full lifecycle of an outbox message.
+func (s *OutboxRepositorySuite) TestSaveFindPendingAndUpdate() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	msg := domain.NewOutboxMessage("orders.created", []byte(`{"order_id": 1}`))
+	assert.NoError(s.repo.Save(ctx, msg))
+	assert.NotZero(msg.ID)
+
+	// Act
+	pending, err := s.repo.FindPending(ctx, 10)
+
+	// Assert
+	assert.NoError(err)
+	assert.Len(pending, 1)
+	assert.Equal("orders.created", pending[0].QueueName)
+	assert.JSONEq(`{"order_id": 1}`, string(pending[0].Payload))
+
+	// A failed message is not due until its next attempt time.
+	pending[0].MarkFailed(errors.New("broker unavailable"), time.Now().Add(time.Hour))
+	assert.NoError(s.repo.Update(ctx, &pending[0]))
+	pending, err = s.repo.FindPending(ctx, 10)
+	assert.NoError(err)
+	assert.Empty(pending)
+
+	// A sent message is never returned again.
+	_, err = s.db.Exec("UPDATE outbox SET next_attempt_at = now()")
+	assert.NoError(err)
+	pending, err = s.repo.FindPending(ctx, 10)
+	assert.NoError(err)
+	assert.Len(pending, 1)
+	assert.Equal(1, pending[0].Attempts)
+	pending[0].MarkSent()
+	assert.NoError(s.repo.Update(ctx, &pending[0]))
+	pending, err = s.repo.FindPending(ctx, 10)
+	assert.NoError(err)
+	assert.Empty(pending)
+}
+
+// TestSave_RolledBackWithTransaction tests that an event is discarded together with its transaction.
+func (s *OutboxRepositorySuite) TestSave_RolledBackWithTransaction() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	errOrderFailed := errors.New("order failed")
+
+	// Act
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.repo.Save(txCtx, domain.NewOutboxMessage("orders.created", []byte(`{}`))); err != nil {
+			return err
+		}
+		return errOrderFailed
+	})
+
+	// Assert
+	assert.ErrorIs(err, errOrderFailed)
+	pending, err := s.repo.FindPending(ctx, 10)
+	assert.NoError(err)
+	assert.Empty(pending)
+}
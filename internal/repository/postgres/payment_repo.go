@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresPaymentRepository implements the usecase.PaymentRepository interface.
+var _ usecase.PaymentRepository = (*PostgresPaymentRepository)(nil)
+
+type PostgresPaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) *PostgresPaymentRepository {
+	return &PostgresPaymentRepository{db: db}
+}
+
+const paymentColumns = `id, order_id, provider, provider_payment_id, amount, refunded_amount, currency, status, created_at, updated_at`
+
+// Save inserts a new payment.
+func (r *PostgresPaymentRepository) Save(ctx context.Context, payment *domain.Payment) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO payments (order_id, provider, provider_payment_id, amount, refunded_amount, currency, status, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		payment.OrderID,
+		payment.Provider,
+		payment.ProviderPaymentID,
+		payment.Amount,
+		payment.Refunded,
+		payment.Amount.Currency,
+		payment.Status,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	).Scan(&payment.ID)
+	if err != nil {
+		return fmt.Errorf("error saving payment: %w", err)
+	}
+
+	return nil
+}
+
+// FindByOrderID retrieves the payments of an order, oldest first.
+func (r *PostgresPaymentRepository) FindByOrderID(ctx context.Context, orderID int64) ([]domain.Payment, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE order_id = $1 ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []domain.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating payment rows: %w", err)
+	}
+
+	return payments, nil
+}
+
+// FindByProviderPaymentIDForUpdate retrieves a payment by the ID of its charge at the
+// provider and locks it until the transaction ends.
+func (r *PostgresPaymentRepository) FindByProviderPaymentIDForUpdate(ctx context.Context, provider, providerPaymentID string) (*domain.Payment, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE provider = $1 AND provider_payment_id = $2 FOR UPDATE`
+
+	payment, err := scanPayment(q.QueryRowContext(ctx, query, provider, providerPaymentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+	}
+	return payment, err
+}
+
+// Update persists the status and the refunded amount of a payment.
+func (r *PostgresPaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE payments SET status = $1, refunded_amount = $2, updated_at = $3 WHERE id = $4`
+
+	result, err := q.ExecContext(ctx, query, payment.Status, payment.Refunded, time.Now(), payment.ID)
+	if err != nil {
+		return fmt.Errorf("error updating payment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("payment not found for update")
+	}
+
+	return nil
+}
+
+func scanPayment(row rowScanner) (*domain.Payment, error) {
+	var p domain.Payment
+	err := row.Scan(&p.ID, &p.OrderID, &p.Provider, &p.ProviderPaymentID, &p.Amount, &p.Refunded, &p.Amount.Currency, &p.Status, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error scanning payment: %w", err)
+	}
+	p.Refunded.Currency = p.Amount.Currency
+	return &p, nil
+}
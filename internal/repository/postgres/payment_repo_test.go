@@ -0,0 +1,183 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresPaymentRepository
+	orderRepo   *postgres.PostgresOrderRepository
+	productRepo *postgres.PostgresProductRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *PaymentRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewPaymentRepository(s.db)
+	s.orderRepo = postgres.NewOrderRepository(s.db)
+	s.productRepo = postgres.NewProductRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *PaymentRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *PaymentRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE payments, order_items, orders, products, outbox, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestPaymentRepository(t *testing.T) {
+	suite.Run(t, new(PaymentRepositorySuite))
+}
+
+// createOrder places a pending order of one product for user 123.
+func (s *PaymentRepositorySuite) createOrder(orderUseCase *usecase.OrderUseCase) *domain.Order {
+	ctx := asAdmin()
+	s.Suite.Require().NoError(createUsers(s.db, 123))
+
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	s.Suite.Require().NoError(s.productRepo.Save(ctx, product))
+
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID: 123,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 2}},
+	})
+	s.Suite.Require().NoError(err)
+	return order
+}
+
+// TestPaymentLifecycle tests saving, finding and updating a payment.
+func (s *PaymentRepositorySuite) TestPaymentLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+	order := &domain.Order{
+		UserID:     123,
+		Status:     domain.StatusPending,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 1, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(order.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	p := domain.NewPayment(order.ID, "fake", "ch_1", order.TotalAmount)
+
+	// Act
+	err := s.repo.Save(ctx, p)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(p.ID)
+
+	assert.NoError(p.Capture())
+	assert.NoError(s.repo.Update(ctx, p))
+
+	found, err := s.repo.FindByProviderPaymentIDForUpdate(ctx, "fake", "ch_1")
+	assert.NoError(err)
+	assert.NotNil(found)
+	assert.Equal(domain.PaymentCaptured, found.Status)
+	assert.Equal(idr("750000"), found.Amount)
+
+	byOrder, err := s.repo.FindByOrderID(ctx, order.ID)
+	assert.NoError(err)
+	assert.Len(byOrder, 1)
+
+	missing, err := s.repo.FindByProviderPaymentIDForUpdate(ctx, "other", "ch_1")
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// TestHandlePaymentEvent_Idempotent delivers the same webhook event twice and checks
+// that the order is paid once.
+func (s *PaymentRepositorySuite) TestHandlePaymentEvent_Idempotent() {
+	assert := s.Suite.Assert()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	paymentUseCase := usecase.NewPaymentUseCase(s.repo, s.orderRepo, txManager, payment.NewFakeGateway(), orderUseCase)
+
+	order := s.createOrder(orderUseCase)
+	p, err := paymentUseCase.CreatePayment(asAdmin(), order.ID)
+	assert.NoError(err)
+
+	// Act
+	for i := 0; i < 2; i++ {
+		assert.NoError(paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID))
+	}
+
+	// Assert
+	found, err := s.orderRepo.FindByID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusPaid, found.Status)
+
+	payments, err := s.repo.FindByOrderID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Len(payments, 1)
+	assert.Equal(domain.PaymentCaptured, payments[0].Status)
+
+	var paidEvents int
+	assert.NoError(s.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE queue_name = $1`, "orders.paid").Scan(&paidEvents))
+	assert.Equal(1, paidEvents)
+}
+
+// TestHandlePaymentEvent_CancelledOrder authorizes a payment of an order that was
+// cancelled meanwhile and checks that the payment is voided rather than captured.
+func (s *PaymentRepositorySuite) TestHandlePaymentEvent_CancelledOrder() {
+	assert := s.Suite.Assert()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	gateway := payment.NewFakeGateway()
+	paymentUseCase := usecase.NewPaymentUseCase(s.repo, s.orderRepo, txManager, gateway, orderUseCase)
+
+	order := s.createOrder(orderUseCase)
+	p, err := paymentUseCase.CreatePayment(asAdmin(), order.ID)
+	assert.NoError(err)
+	_, err = orderUseCase.CancelOrder(asAdmin(), order.ID)
+	assert.NoError(err)
+
+	// Act
+	err = paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID)
+
+	// Assert
+	assert.NoError(err)
+	found, err := s.orderRepo.FindByID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusCancelled, found.Status)
+
+	payments, err := s.repo.FindByOrderID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Len(payments, 1)
+	assert.Equal(domain.PaymentVoided, payments[0].Status)
+	assert.ErrorIs(gateway.Capture(context.Background(), p.ProviderPaymentID, p.Amount, "capture-test"), payment.ErrChargeVoided)
+
+	var paidEvents int
+	assert.NoError(s.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE queue_name = $1`, "orders.paid").Scan(&paidEvents))
+	assert.Equal(0, paidEvents)
+}
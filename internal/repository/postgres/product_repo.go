@@ -20,14 +20,18 @@ type PostgresProductRepository struct {
 
 // Save inserts a new product into the database.
 func (r *PostgresProductRepository) Save(ctx context.Context, product *domain.Product) error {
-	query := `INSERT INTO products (name, price, quantity, created_at, updated_at) 
-			   VALUES ($1, $2, $3, $4, $5) 
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO products (name, price, currency, tax_category, quantity, created_at, updated_at) 
+			   VALUES ($1, $2, $3, $4, $5, $6, $7) 
 			   RETURNING id, created_at, updated_at`
 
 	now := time.Now()
-	err := r.db.QueryRowContext(ctx, query,
+	err := q.QueryRowContext(ctx, query,
 		product.Name,
 		product.Price,
+		product.Price.Currency,
+		product.TaxCategory.OrDefault(),
 		product.Quantity,
 		now,
 		now,
@@ -42,13 +46,17 @@ func (r *PostgresProductRepository) Save(ctx context.Context, product *domain.Pr
 
 // Update modifies an existing product in the database.
 func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	q := getQuerier(ctx, r.db)
+
 	query := `UPDATE products 
-			   SET name = $1, price = $2, quantity = $3, updated_at = $4 
-			   WHERE id = $5`
+			   SET name = $1, price = $2, currency = $3, tax_category = $4, quantity = $5, updated_at = $6 
+			   WHERE id = $7`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := q.ExecContext(ctx, query,
 		product.Name,
 		product.Price,
+		product.Price.Currency,
+		product.TaxCategory.OrDefault(),
 		product.Quantity,
 		time.Now(),
 		product.ID,
@@ -70,9 +78,11 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.
 
 // Delete removes a product from the database by its ID.
 func (r *PostgresProductRepository) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+
 	query := `DELETE FROM products WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := q.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting product: %w", err)
 	}
@@ -90,12 +100,14 @@ func (r *PostgresProductRepository) Delete(ctx context.Context, id int64) error
 
 // FindAll retrieves a paginated list of all products.
 func (r *PostgresProductRepository) FindAll(ctx context.Context, limit int, offset int) ([]domain.Product, error) {
-	query := `SELECT id, name, price, quantity, created_at, updated_at 
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, name, price, currency, tax_category, quantity, created_at, updated_at 
 			   FROM products 
 			   ORDER BY id ASC 
 			   LIMIT $1 OFFSET $2`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := q.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error querying products: %w", err)
 	}
@@ -104,7 +116,7 @@ func (r *PostgresProductRepository) FindAll(ctx context.Context, limit int, offs
 	var products []domain.Product
 	for rows.Next() {
 		var p domain.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Quantity, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Price.Currency, &p.TaxCategory, &p.Quantity, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("error scanning product row: %w", err)
 		}
 		products = append(products, p)
@@ -119,11 +131,13 @@ func (r *PostgresProductRepository) FindAll(ctx context.Context, limit int, offs
 
 // FindByID retrieves a single product from the database by its ID.
 func (r *PostgresProductRepository) FindByID(ctx context.Context, id int64) (*domain.Product, error) {
-	query := `SELECT id, name, price, quantity, created_at, updated_at FROM products WHERE id = $1`
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, name, price, currency, tax_category, quantity, created_at, updated_at FROM products WHERE id = $1`
 	var p domain.Product
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.Name, &p.Price, &p.Quantity, &p.CreatedAt, &p.UpdatedAt,
+	err := q.QueryRowContext(ctx, query, id).Scan(
+		&p.ID, &p.Name, &p.Price, &p.Price.Currency, &p.TaxCategory, &p.Quantity, &p.CreatedAt, &p.UpdatedAt,
 	)
 
 	if err != nil {
@@ -138,11 +152,32 @@ func (r *PostgresProductRepository) FindByID(ctx context.Context, id int64) (*do
 
 // FindManyByIDs retrieves multiple products based on a slice of IDs.
 func (r *PostgresProductRepository) FindManyByIDs(ctx context.Context, ids []int64) ([]domain.Product, error) {
-	query := `SELECT id, name, price, quantity, created_at, updated_at 
+	query := `SELECT id, name, price, currency, tax_category, quantity, created_at, updated_at 
 			   FROM products 
 			   WHERE id = ANY($1)`
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	return r.findMany(ctx, query, ids)
+}
+
+// FindManyByIDsForUpdate retrieves multiple products and locks their rows until
+// the surrounding transaction ends, so concurrent orders cannot oversell stock.
+// Rows are locked in ascending ID order to avoid deadlocks between transactions.
+// It must be called within a transaction to have any effect.
+func (r *PostgresProductRepository) FindManyByIDsForUpdate(ctx context.Context, ids []int64) ([]domain.Product, error) {
+	query := `SELECT id, name, price, currency, tax_category, quantity, created_at, updated_at 
+			   FROM products 
+			   WHERE id = ANY($1) 
+			   ORDER BY id ASC 
+			   FOR UPDATE`
+
+	return r.findMany(ctx, query, ids)
+}
+
+// findMany runs a product query that takes a single array of IDs as its argument.
+func (r *PostgresProductRepository) findMany(ctx context.Context, query string, ids []int64) ([]domain.Product, error) {
+	q := getQuerier(ctx, r.db)
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
 		return nil, fmt.Errorf("error querying products by ids: %w", err)
 	}
@@ -151,7 +186,7 @@ func (r *PostgresProductRepository) FindManyByIDs(ctx context.Context, ids []int
 	var products []domain.Product
 	for rows.Next() {
 		var p domain.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Quantity, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Price.Currency, &p.TaxCategory, &p.Quantity, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("error scanning product row: %w", err)
 		}
 		products = append(products, p)
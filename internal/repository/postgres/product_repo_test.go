@@ -3,6 +3,7 @@ package postgres_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"testing"
 
@@ -10,14 +11,16 @@ import (
 	"github.com/elokanugrah/go-order-system/internal/database"
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
 	"github.com/stretchr/testify/suite"
 )
 
 type ProductRepositorySuite struct {
 	suite.Suite
 
-	db   *sql.DB
-	repo *postgres.PostgresProductRepository
+	db        *sql.DB
+	repo      *postgres.PostgresProductRepository
+	txManager usecase.TransactionManager
 }
 
 // SetupSuite runs once before all tests in this suite.
@@ -26,6 +29,7 @@ func (s *ProductRepositorySuite) SetupSuite() {
 	cfg := config.Load()
 	s.db = database.NewConnection(cfg)
 	s.repo = postgres.NewProductRepository(s.db)
+	s.txManager = postgres.NewTransactionManager(s.db)
 }
 
 // TearDownSuite runs once after all tests in this suite are finished.
@@ -55,7 +59,7 @@ func (s *ProductRepositorySuite) TestSaveAndFindByID() {
 
 	newProduct := &domain.Product{
 		Name:     "Kopi Arabica",
-		Price:    120000,
+		Price:    idr("120000"),
 		Quantity: 50,
 	}
 
@@ -93,7 +97,7 @@ func (s *ProductRepositorySuite) TestUpdate() {
 	assert := s.Suite.Assert()
 	ctx := context.Background()
 
-	productToUpdate := &domain.Product{Name: "Buku Lama", Price: 50000, Quantity: 5}
+	productToUpdate := &domain.Product{Name: "Buku Lama", Price: idr("50000"), Quantity: 5}
 	err := s.repo.Save(ctx, productToUpdate)
 	assert.NoError(err)
 
@@ -116,7 +120,7 @@ func (s *ProductRepositorySuite) TestDelete() {
 	assert := s.Suite.Assert()
 	ctx := context.Background()
 
-	productToDelete := &domain.Product{Name: "Barang Hapus", Price: 10, Quantity: 1}
+	productToDelete := &domain.Product{Name: "Barang Hapus", Price: idr("10"), Quantity: 1}
 	err := s.repo.Save(ctx, productToDelete)
 	assert.NoError(err)
 
@@ -129,3 +133,99 @@ func (s *ProductRepositorySuite) TestDelete() {
 	assert.NoError(err)
 	assert.Nil(foundProduct)
 }
+
+// TestWithTransaction_RollbackRestoresStock tests that stock changes made inside
+// a failed transaction are rolled back together with the transaction.
+func (s *ProductRepositorySuite) TestWithTransaction_RollbackRestoresStock() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Teh Hijau", Price: idr("25000"), Quantity: 10}
+	assert.NoError(s.repo.Save(ctx, product))
+
+	errOrderFailed := errors.New("order insert failed")
+
+	// Act: decrement stock inside a transaction, then fail it.
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		products, err := s.repo.FindManyByIDs(txCtx, []int64{product.ID})
+		if err != nil {
+			return err
+		}
+		p := products[0]
+		if err := p.DecreaseStock(4); err != nil {
+			return err
+		}
+		if err := s.repo.Update(txCtx, &p); err != nil {
+			return err
+		}
+		return errOrderFailed
+	})
+
+	// Assert: the error is propagated and the stock is unchanged.
+	assert.ErrorIs(err, errOrderFailed)
+	found, err := s.repo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(10, found.Quantity)
+}
+
+// TestWithTransaction_CommitPersistsStock tests that stock changes are visible after commit.
+func (s *ProductRepositorySuite) TestWithTransaction_CommitPersistsStock() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Teh Hitam", Price: idr("20000"), Quantity: 10}
+	assert.NoError(s.repo.Save(ctx, product))
+
+	// Act
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		p, err := s.repo.FindByID(txCtx, product.ID)
+		if err != nil {
+			return err
+		}
+		if err := p.DecreaseStock(4); err != nil {
+			return err
+		}
+		return s.repo.Update(txCtx, p)
+	})
+
+	// Assert
+	assert.NoError(err)
+	found, err := s.repo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(6, found.Quantity)
+}
+
+// TestWithTransaction_NestedJoinsOuter tests that a nested transaction is rolled back with the outer one.
+func (s *ProductRepositorySuite) TestWithTransaction_NestedJoinsOuter() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Kopi Susu", Price: idr("18000"), Quantity: 10}
+	assert.NoError(s.repo.Save(ctx, product))
+
+	errCheckoutFailed := errors.New("checkout failed")
+
+	// Act: the inner transaction succeeds, the outer one fails afterwards.
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		err := s.txManager.WithTransaction(txCtx, func(innerCtx context.Context) error {
+			p, err := s.repo.FindByID(innerCtx, product.ID)
+			if err != nil {
+				return err
+			}
+			if err := p.DecreaseStock(4); err != nil {
+				return err
+			}
+			return s.repo.Update(innerCtx, p)
+		})
+		if err != nil {
+			return err
+		}
+		return errCheckoutFailed
+	})
+
+	// Assert: the inner change was not committed on its own.
+	assert.ErrorIs(err, errCheckoutFailed)
+	found, err := s.repo.FindByID(ctx, product.ID)
+	assert.NoError(err)
+	assert.Equal(10, found.Quantity)
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// querier is an interface that is satisfied by both *sql.DB and *sql.Tx.
+// This allows repository methods to work with or without a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// getQuerier extracts a transaction from the context if it exists,
+// otherwise it returns the base database connection.
+// Every repository in this package must use it so that it takes part in
+// transactions started by PostgresTransactionManager.
+func getQuerier(ctx context.Context, db *sql.DB) querier {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	if ok {
+		return tx
+	}
+
+	return db
+}
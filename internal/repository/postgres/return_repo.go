@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/lib/pq"
+)
+
+// Ensure PostgresReturnRepository implements the usecase.ReturnRepository interface.
+var _ usecase.ReturnRepository = (*PostgresReturnRepository)(nil)
+
+type PostgresReturnRepository struct {
+	db *sql.DB
+}
+
+func NewReturnRepository(db *sql.DB) *PostgresReturnRepository {
+	return &PostgresReturnRepository{db: db}
+}
+
+const returnColumns = `id, order_id, user_id, reason, status, refund_amount, currency, refund_id, created_at, updated_at`
+
+// Save inserts a new return and its items.
+func (r *PostgresReturnRepository) Save(ctx context.Context, rma *domain.Return) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO returns (order_id, user_id, reason, status, refund_amount, currency, refund_id, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		rma.OrderID,
+		rma.UserID,
+		rma.Reason,
+		rma.Status,
+		rma.RefundAmount,
+		rma.RefundAmount.Currency,
+		rma.RefundID,
+		rma.CreatedAt,
+		rma.UpdatedAt,
+	).Scan(&rma.ID)
+	if err != nil {
+		return fmt.Errorf("error saving return: %w", err)
+	}
+
+	itemQuery := `INSERT INTO return_items (return_id, product_id, quantity, price_at_order) VALUES `
+
+	vals := []interface{}{}
+	var placeholders []string
+	for i, item := range rma.Items {
+		n := i * 4
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4))
+		vals = append(vals, rma.ID, item.ProductID, item.Quantity, item.PriceAtOrder)
+	}
+	itemQuery += strings.Join(placeholders, ", ") + " RETURNING id"
+
+	rows, err := q.QueryContext(ctx, itemQuery, vals...)
+	if err != nil {
+		return fmt.Errorf("error saving return items: %w", err)
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&rma.Items[i].ID); err != nil {
+			return fmt.Errorf("error scanning return item id: %w", err)
+		}
+		rma.Items[i].ReturnID = rma.ID
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating return item ids: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a return with its items.
+func (r *PostgresReturnRepository) FindByID(ctx context.Context, id int64) (*domain.Return, error) {
+	return r.findOne(ctx, `SELECT `+returnColumns+` FROM returns WHERE id = $1`, id)
+}
+
+// FindByIDForUpdate retrieves a return with its items and locks it until the transaction ends.
+func (r *PostgresReturnRepository) FindByIDForUpdate(ctx context.Context, id int64) (*domain.Return, error) {
+	return r.findOne(ctx, `SELECT `+returnColumns+` FROM returns WHERE id = $1 FOR UPDATE`, id)
+}
+
+// FindByOrderID retrieves the returns of an order with their items, oldest first.
+func (r *PostgresReturnRepository) FindByOrderID(ctx context.Context, orderID int64) ([]domain.Return, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + returnColumns + ` FROM returns WHERE order_id = $1 ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying returns: %w", err)
+	}
+	defer rows.Close()
+
+	var returns []domain.Return
+	for rows.Next() {
+		rma, err := scanReturn(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning return: %w", err)
+		}
+		returns = append(returns, *rma)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating return rows: %w", err)
+	}
+
+	if err := loadReturnItems(ctx, q, returns); err != nil {
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+// UpdateStatus persists the status and the refund of a return.
+func (r *PostgresReturnRepository) UpdateStatus(ctx context.Context, rma *domain.Return) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE returns SET status = $1, refund_amount = $2, refund_id = $3, updated_at = $4 WHERE id = $5`
+
+	result, err := q.ExecContext(ctx, query, rma.Status, rma.RefundAmount, rma.RefundID, time.Now(), rma.ID)
+	if err != nil {
+		return fmt.Errorf("error updating return: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("return not found for update")
+	}
+
+	return nil
+}
+
+// findOne retrieves the single return selected by query with its items, or nil, nil if there is none.
+func (r *PostgresReturnRepository) findOne(ctx context.Context, query string, args ...interface{}) (*domain.Return, error) {
+	q := getQuerier(ctx, r.db)
+
+	rma, err := scanReturn(q.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning return: %w", err)
+	}
+
+	returns := []domain.Return{*rma}
+	if err := loadReturnItems(ctx, q, returns); err != nil {
+		return nil, err
+	}
+	return &returns[0], nil
+}
+
+// scanReturn scans a row selected with returnColumns.
+func scanReturn(row rowScanner) (*domain.Return, error) {
+	var rma domain.Return
+	err := row.Scan(&rma.ID, &rma.OrderID, &rma.UserID, &rma.Reason, &rma.Status, &rma.RefundAmount, &rma.RefundAmount.Currency, &rma.RefundID, &rma.CreatedAt, &rma.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rma, nil
+}
+
+// loadReturnItems fetches the items of all given returns in a single query and
+// attaches them to the matching return.
+func loadReturnItems(ctx context.Context, q querier, returns []domain.Return) error {
+	if len(returns) == 0 {
+		return nil
+	}
+
+	returnIDs := make([]int64, len(returns))
+	indexByID := make(map[int64]int, len(returns))
+	for i, rma := range returns {
+		returnIDs[i] = rma.ID
+		indexByID[rma.ID] = i
+	}
+
+	query := `SELECT id, return_id, product_id, quantity, price_at_order
+			   FROM return_items
+			   WHERE return_id = ANY($1)
+			   ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(returnIDs))
+	if err != nil {
+		return fmt.Errorf("error querying return items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.ReturnItem
+		if err := rows.Scan(&item.ID, &item.ReturnID, &item.ProductID, &item.Quantity, &item.PriceAtOrder); err != nil {
+			return fmt.Errorf("error scanning return item row: %w", err)
+		}
+
+		i := indexByID[item.ReturnID]
+		item.PriceAtOrder.Currency = returns[i].RefundAmount.Currency
+		returns[i].Items = append(returns[i].Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReturnRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresReturnRepository
+	orderRepo   *postgres.PostgresOrderRepository
+	productRepo *postgres.PostgresProductRepository
+	paymentRepo *postgres.PostgresPaymentRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *ReturnRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewReturnRepository(s.db)
+	s.orderRepo = postgres.NewOrderRepository(s.db)
+	s.productRepo = postgres.NewProductRepository(s.db)
+	s.paymentRepo = postgres.NewPaymentRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *ReturnRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *ReturnRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE return_items, returns, payments, coupon_redemptions, coupons, order_items, orders, products, outbox, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestReturnRepository(t *testing.T) {
+	suite.Run(t, new(ReturnRepositorySuite))
+}
+
+// TestReturnLifecycle tests saving, finding and updating a return with its items.
+func (s *ReturnRepositorySuite) TestReturnLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+	order := &domain.Order{
+		UserID:     123,
+		Status:     domain.StatusPaid,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 2, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(order.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	rma, err := domain.NewReturn(order, []domain.ReturnItem{{ProductID: product.ID, Quantity: 1}}, "damaged", nil)
+	s.Suite.Require().NoError(err)
+
+	// Act
+	err = s.repo.Save(ctx, rma)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(rma.ID)
+	assert.NotZero(rma.Items[0].ID)
+
+	assert.NoError(rma.Approve())
+	assert.NoError(s.repo.UpdateStatus(ctx, rma))
+
+	found, err := s.repo.FindByIDForUpdate(ctx, rma.ID)
+	assert.NoError(err)
+	s.Suite.Require().NotNil(found)
+	assert.Equal(domain.ReturnApproved, found.Status)
+	assert.Equal("damaged", found.Reason)
+	assert.Equal(idr("750000"), found.RefundAmount)
+	assert.Len(found.Items, 1)
+	assert.Equal(idr("750000"), found.Items[0].PriceAtOrder)
+
+	byOrder, err := s.repo.FindByOrderID(ctx, order.ID)
+	assert.NoError(err)
+	assert.Len(byOrder, 1)
+	assert.Len(byOrder[0].Items, 1)
+
+	missing, err := s.repo.FindByID(ctx, rma.ID+1)
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// TestReceiveReturn receives an approved return and checks that its items are
+// restocked and what was paid for them refunded.
+func (s *ReturnRepositorySuite) TestReceiveReturn() {
+	assert := s.Suite.Assert()
+	require := s.Suite.Require()
+	ctx := asAdmin()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	paymentUseCase := usecase.NewPaymentUseCase(s.paymentRepo, s.orderRepo, txManager, payment.NewFakeGateway(), orderUseCase)
+	returnUseCase := usecase.NewReturnUseCase(s.repo, s.orderRepo, s.productRepo, txManager, outboxRepo, paymentUseCase)
+
+	require.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	require.NoError(s.productRepo.Save(ctx, product))
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID: 123,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 2}},
+	})
+	require.NoError(err)
+	p, err := paymentUseCase.CreatePayment(ctx, order.ID)
+	require.NoError(err)
+	require.NoError(paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID))
+
+	rma, err := returnUseCase.RequestReturn(ctx, dto.CreateReturnInput{
+		OrderID: order.ID,
+		Items:   []dto.CreateReturnItemInput{{ProductID: product.ID, Quantity: 1}},
+	})
+	require.NoError(err)
+	_, err = returnUseCase.ApproveReturn(ctx, rma.ID)
+	require.NoError(err)
+
+	// Act
+	received, err := returnUseCase.ReceiveReturn(ctx, rma.ID)
+
+	// Assert
+	assert.NoError(err)
+	assert.Equal(domain.ReturnReceived, received.Status)
+	assert.NotEmpty(received.RefundID)
+	assert.Equal(idr("832500"), received.RefundAmount) // 750000 plus 11% tax.
+
+	restocked, err := s.productRepo.FindByID(context.Background(), product.ID)
+	assert.NoError(err)
+	assert.Equal(9, restocked.Quantity)
+
+	payments, err := s.paymentRepo.FindByOrderID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(idr("832500"), payments[0].Refunded)
+
+	var receivedEvents int
+	assert.NoError(s.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE queue_name = $1`, "returns.received").Scan(&receivedEvents))
+	assert.Equal(1, receivedEvents)
+}
+
+// TestReceiveReturn_DiscountedOrder returns every item of an order placed with a
+// coupon and checks that the refund is exactly what was paid for the order.
+func (s *ReturnRepositorySuite) TestReceiveReturn_DiscountedOrder() {
+	assert := s.Suite.Assert()
+	require := s.Suite.Require()
+	ctx := asAdmin()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	couponRepo := postgres.NewCouponRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(couponRepo), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	paymentUseCase := usecase.NewPaymentUseCase(s.paymentRepo, s.orderRepo, txManager, payment.NewFakeGateway(), orderUseCase)
+	returnUseCase := usecase.NewReturnUseCase(s.repo, s.orderRepo, s.productRepo, txManager, outboxRepo, paymentUseCase)
+
+	require.NoError(createUsers(s.db, 123))
+	keyboard := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	require.NoError(s.productRepo.Save(ctx, keyboard))
+	mouse := &domain.Product{Name: "Mouse", Price: idr("333333"), Quantity: 10}
+	require.NoError(s.productRepo.Save(ctx, mouse))
+	require.NoError(couponRepo.Save(ctx, &domain.Coupon{Code: "SAVE15", Type: domain.CouponTypePercentage, Percent: 15, CreatedAt: time.Now(), UpdatedAt: time.Now()}))
+
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID:     123,
+		CouponCode: "SAVE15",
+		Items:      []dto.CreateOrderItemInput{{ProductID: keyboard.ID, Quantity: 2}, {ProductID: mouse.ID, Quantity: 3}},
+	})
+	require.NoError(err)
+	require.True(order.Discount.IsPositive())
+	p, err := paymentUseCase.CreatePayment(ctx, order.ID)
+	require.NoError(err)
+	require.NoError(paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID))
+
+	// Return the order in two parts.
+	var refunded []domain.Money
+	for _, items := range [][]dto.CreateReturnItemInput{
+		{{ProductID: keyboard.ID, Quantity: 1}, {ProductID: mouse.ID, Quantity: 1}},
+		{{ProductID: keyboard.ID, Quantity: 1}, {ProductID: mouse.ID, Quantity: 2}},
+	} {
+		rma, err := returnUseCase.RequestReturn(ctx, dto.CreateReturnInput{OrderID: order.ID, Items: items})
+		require.NoError(err)
+		_, err = returnUseCase.ApproveReturn(ctx, rma.ID)
+		require.NoError(err)
+
+		// Act
+		received, err := returnUseCase.ReceiveReturn(ctx, rma.ID)
+
+		// Assert
+		require.NoError(err)
+		refunded = append(refunded, received.RefundAmount)
+	}
+
+	total, err := refunded[0].Add(refunded[1])
+	require.NoError(err)
+	payments, err := s.paymentRepo.FindByOrderID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(total, payments[0].Refunded)
+	// Refunds are rounded down, so at most a minor unit per item is kept.
+	kept, err := order.TotalAmount.Sub(total)
+	assert.NoError(err)
+	assert.True(kept.Amount >= 0 && kept.Amount <= 2, "kept %s", kept)
+}
+
+// TestRequestReturn_Concurrent requests returns of the same order concurrently and
+// checks that no more units are returned than were ordered.
+func (s *ReturnRepositorySuite) TestRequestReturn_Concurrent() {
+	assert := s.Suite.Assert()
+	require := s.Suite.Require()
+	ctx := asAdmin()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	paymentUseCase := usecase.NewPaymentUseCase(s.paymentRepo, s.orderRepo, txManager, payment.NewFakeGateway(), orderUseCase)
+	returnUseCase := usecase.NewReturnUseCase(s.repo, s.orderRepo, s.productRepo, txManager, outboxRepo, paymentUseCase)
+
+	const requesters = 5
+
+	require.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	require.NoError(s.productRepo.Save(ctx, product))
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID: 123,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 2}},
+	})
+	require.NoError(err)
+	p, err := paymentUseCase.CreatePayment(ctx, order.ID)
+	require.NoError(err)
+	require.NoError(paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID))
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		requested  int
+		rejected   int
+		unexpected []error
+	)
+
+	// Act
+	for i := 0; i < requesters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := returnUseCase.RequestReturn(ctx, dto.CreateReturnInput{
+				OrderID: order.ID,
+				Items:   []dto.CreateReturnItemInput{{ProductID: product.ID, Quantity: 1}},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				requested++
+			case errors.Is(err, domain.ErrInvalidReturn):
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Empty(unexpected)
+	assert.Equal(2, requested)
+	assert.Equal(requesters-2, rejected)
+
+	returns, err := s.repo.FindByOrderID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Len(returns, 2)
+}
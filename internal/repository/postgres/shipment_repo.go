@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/lib/pq"
+)
+
+// Ensure PostgresShipmentRepository implements the usecase.ShipmentRepository interface.
+var _ usecase.ShipmentRepository = (*PostgresShipmentRepository)(nil)
+
+type PostgresShipmentRepository struct {
+	db *sql.DB
+}
+
+func NewShipmentRepository(db *sql.DB) *PostgresShipmentRepository {
+	return &PostgresShipmentRepository{db: db}
+}
+
+const shipmentColumns = `id, order_id, carrier, tracking_number, status, shipped_at, delivered_at, created_at, updated_at`
+
+// Save inserts a new shipment and its items.
+func (r *PostgresShipmentRepository) Save(ctx context.Context, shipment *domain.Shipment) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO shipments (order_id, carrier, tracking_number, status, shipped_at, delivered_at, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		shipment.OrderID,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+		shipment.Status,
+		shipment.ShippedAt,
+		shipment.DeliveredAt,
+		shipment.CreatedAt,
+		shipment.UpdatedAt,
+	).Scan(&shipment.ID)
+	if err != nil {
+		return fmt.Errorf("error saving shipment: %w", err)
+	}
+
+	itemQuery := `INSERT INTO shipment_items (shipment_id, product_id, quantity) VALUES `
+
+	vals := []interface{}{}
+	var placeholders []string
+	for i, item := range shipment.Items {
+		n := i * 3
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d)", n+1, n+2, n+3))
+		vals = append(vals, shipment.ID, item.ProductID, item.Quantity)
+	}
+	itemQuery += strings.Join(placeholders, ", ") + " RETURNING id"
+
+	rows, err := q.QueryContext(ctx, itemQuery, vals...)
+	if err != nil {
+		return fmt.Errorf("error saving shipment items: %w", err)
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&shipment.Items[i].ID); err != nil {
+			return fmt.Errorf("error scanning shipment item id: %w", err)
+		}
+		shipment.Items[i].ShipmentID = shipment.ID
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating shipment item ids: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a shipment with its items.
+func (r *PostgresShipmentRepository) FindByID(ctx context.Context, id int64) (*domain.Shipment, error) {
+	return r.findOne(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE id = $1`, id)
+}
+
+// FindByIDForUpdate retrieves a shipment with its items and locks it until the transaction ends.
+func (r *PostgresShipmentRepository) FindByIDForUpdate(ctx context.Context, id int64) (*domain.Shipment, error) {
+	return r.findOne(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE id = $1 FOR UPDATE`, id)
+}
+
+// FindByOrderID retrieves the shipments of an order with their items, oldest first.
+func (r *PostgresShipmentRepository) FindByOrderID(ctx context.Context, orderID int64) ([]domain.Shipment, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE order_id = $1 ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying shipments: %w", err)
+	}
+	defer rows.Close()
+
+	var shipments []domain.Shipment
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning shipment: %w", err)
+		}
+		shipments = append(shipments, *shipment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shipment rows: %w", err)
+	}
+
+	if err := loadShipmentItems(ctx, q, shipments); err != nil {
+		return nil, err
+	}
+
+	return shipments, nil
+}
+
+// UpdateStatus persists the tracking status and delivery time of a shipment.
+func (r *PostgresShipmentRepository) UpdateStatus(ctx context.Context, shipment *domain.Shipment) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE shipments SET status = $1, delivered_at = $2, updated_at = $3 WHERE id = $4`
+
+	result, err := q.ExecContext(ctx, query, shipment.Status, shipment.DeliveredAt, time.Now(), shipment.ID)
+	if err != nil {
+		return fmt.Errorf("error updating shipment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("shipment not found for update")
+	}
+
+	return nil
+}
+
+// findOne retrieves the single shipment selected by query with its items, or nil, nil if there is none.
+func (r *PostgresShipmentRepository) findOne(ctx context.Context, query string, args ...interface{}) (*domain.Shipment, error) {
+	q := getQuerier(ctx, r.db)
+
+	shipment, err := scanShipment(q.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning shipment: %w", err)
+	}
+
+	shipments := []domain.Shipment{*shipment}
+	if err := loadShipmentItems(ctx, q, shipments); err != nil {
+		return nil, err
+	}
+	return &shipments[0], nil
+}
+
+// scanShipment scans a row selected with shipmentColumns.
+func scanShipment(row rowScanner) (*domain.Shipment, error) {
+	var s domain.Shipment
+	var deliveredAt sql.NullTime
+	err := row.Scan(&s.ID, &s.OrderID, &s.Carrier, &s.TrackingNumber, &s.Status, &s.ShippedAt, &deliveredAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		s.DeliveredAt = &deliveredAt.Time
+	}
+	return &s, nil
+}
+
+// loadShipmentItems fetches the items of all given shipments in a single query and
+// attaches them to the matching shipment.
+func loadShipmentItems(ctx context.Context, q querier, shipments []domain.Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+
+	shipmentIDs := make([]int64, len(shipments))
+	indexByID := make(map[int64]int, len(shipments))
+	for i, s := range shipments {
+		shipmentIDs[i] = s.ID
+		indexByID[s.ID] = i
+	}
+
+	query := `SELECT id, shipment_id, product_id, quantity
+			   FROM shipment_items
+			   WHERE shipment_id = ANY($1)
+			   ORDER BY id ASC`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(shipmentIDs))
+	if err != nil {
+		return fmt.Errorf("error querying shipment items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.ShipmentItem
+		if err := rows.Scan(&item.ID, &item.ShipmentID, &item.ProductID, &item.Quantity); err != nil {
+			return fmt.Errorf("error scanning shipment item row: %w", err)
+		}
+
+		i := indexByID[item.ShipmentID]
+		shipments[i].Items = append(shipments[i].Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return nil
+}
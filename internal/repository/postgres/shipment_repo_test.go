@@ -0,0 +1,168 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/payment"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShipmentRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresShipmentRepository
+	orderRepo   *postgres.PostgresOrderRepository
+	productRepo *postgres.PostgresProductRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *ShipmentRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewShipmentRepository(s.db)
+	s.orderRepo = postgres.NewOrderRepository(s.db)
+	s.productRepo = postgres.NewProductRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *ShipmentRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *ShipmentRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE shipment_items, shipments, payments, order_items, orders, products, outbox, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestShipmentRepository(t *testing.T) {
+	suite.Run(t, new(ShipmentRepositorySuite))
+}
+
+// TestShipmentLifecycle tests saving, finding and updating a shipment with its items.
+func (s *ShipmentRepositorySuite) TestShipmentLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	assert.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	assert.NoError(s.productRepo.Save(ctx, product))
+	order := &domain.Order{
+		UserID:     123,
+		Status:     domain.StatusPaid,
+		OrderItems: []domain.OrderItem{{Product: *product, Quantity: 2, PriceAtOrder: product.Price, BasePrice: product.Price, ExchangeRate: domain.IdentityRate}},
+	}
+	assert.NoError(order.CalculateTotalAmount())
+	assert.NoError(s.orderRepo.Save(ctx, order))
+
+	shipment, err := domain.NewShipment(order, "JNE", "JNE123", []domain.ShipmentItem{{ProductID: product.ID, Quantity: 1}}, nil)
+	s.Suite.Require().NoError(err)
+
+	// Act
+	err = s.repo.Save(ctx, shipment)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(shipment.ID)
+	assert.NotZero(shipment.Items[0].ID)
+
+	found, err := s.repo.FindByID(ctx, shipment.ID)
+	assert.NoError(err)
+	s.Suite.Require().NotNil(found)
+	assert.Equal(domain.ShipmentShipped, found.Status)
+	assert.Nil(found.DeliveredAt)
+
+	assert.NoError(shipment.UpdateStatus(domain.ShipmentDelivered))
+	assert.NoError(s.repo.UpdateStatus(ctx, shipment))
+
+	found, err = s.repo.FindByIDForUpdate(ctx, shipment.ID)
+	assert.NoError(err)
+	s.Suite.Require().NotNil(found)
+	assert.Equal(domain.ShipmentDelivered, found.Status)
+	assert.Equal("JNE123", found.TrackingNumber)
+	assert.NotNil(found.DeliveredAt)
+	assert.Len(found.Items, 1)
+
+	byOrder, err := s.repo.FindByOrderID(ctx, order.ID)
+	assert.NoError(err)
+	assert.Len(byOrder, 1)
+	assert.Len(byOrder[0].Items, 1)
+
+	missing, err := s.repo.FindByID(ctx, shipment.ID+1)
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// TestPartialFulfillment ships an order in two shipments and delivers them, checking
+// that the order is shipped with the last item and completed with the last delivery.
+func (s *ShipmentRepositorySuite) TestPartialFulfillment() {
+	assert := s.Suite.Assert()
+	require := s.Suite.Require()
+	ctx := asAdmin()
+	txManager := postgres.NewTransactionManager(s.db)
+	outboxRepo := postgres.NewOutboxRepository(s.db)
+	orderUseCase := usecase.NewOrderUseCase(s.orderRepo, s.productRepo, txManager, outboxRepo, postgres.NewIdempotencyRepository(s.db), postgres.NewExchangeRateRepository(s.db), usecase.NewPromotionUseCase(postgres.NewCouponRepository(s.db)), defaultTaxCalculator(), newUserUseCase(s.db), postgres.NewPaymentRepository(s.db), postgres.NewShipmentRepository(s.db), postgres.NewReturnRepository(s.db))
+	shipmentUseCase := usecase.NewShipmentUseCase(s.repo, s.orderRepo, txManager, outboxRepo, orderUseCase)
+
+	require.NoError(createUsers(s.db, 123))
+	product := &domain.Product{Name: "Keyboard", Price: idr("750000"), Quantity: 10}
+	require.NoError(s.productRepo.Save(ctx, product))
+	order, err := orderUseCase.CreateOrder(ctx, dto.CreateOrderInput{
+		UserID: 123,
+		Items:  []dto.CreateOrderItemInput{{ProductID: product.ID, Quantity: 2}},
+	})
+	require.NoError(err)
+	paymentUseCase := usecase.NewPaymentUseCase(postgres.NewPaymentRepository(s.db), s.orderRepo, txManager, payment.NewFakeGateway(), orderUseCase)
+	p, err := paymentUseCase.CreatePayment(ctx, order.ID)
+	require.NoError(err)
+	require.NoError(paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, p.ProviderPaymentID))
+
+	// Act
+	var shipments []*domain.Shipment
+	for _, trackingNumber := range []string{"JNE123", "JNE124"} {
+		shipment, err := shipmentUseCase.CreateShipment(ctx, dto.CreateShipmentInput{
+			OrderID:        order.ID,
+			Carrier:        "JNE",
+			TrackingNumber: trackingNumber,
+			Items:          []dto.CreateShipmentItemInput{{ProductID: product.ID, Quantity: 1}},
+		})
+		require.NoError(err)
+		shipments = append(shipments, shipment)
+	}
+
+	// Assert
+	shipped, err := s.orderRepo.FindByID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusShipped, shipped.Status)
+
+	_, err = shipmentUseCase.UpdateShipmentStatus(ctx, shipments[0].ID, domain.ShipmentDelivered)
+	assert.NoError(err)
+	partial, err := s.orderRepo.FindByID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusShipped, partial.Status)
+
+	_, err = shipmentUseCase.UpdateShipmentStatus(ctx, shipments[1].ID, domain.ShipmentDelivered)
+	assert.NoError(err)
+	completed, err := s.orderRepo.FindByID(context.Background(), order.ID)
+	assert.NoError(err)
+	assert.Equal(domain.StatusCompleted, completed.Status)
+
+	var shipmentEvents int
+	assert.NoError(s.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE queue_name LIKE 'shipments.%'`).Scan(&shipmentEvents))
+	assert.Equal(4, shipmentEvents)
+}
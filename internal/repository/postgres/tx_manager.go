@@ -22,7 +22,13 @@ func NewTransactionManager(db *sql.DB) usecase.TransactionManager {
 // WithTransaction executes a function within a database transaction.
 // It begins a transaction, calls the provided function with a new context
 // containing the transaction, and then commits or rolls back based on the error.
+// If the context already carries a transaction, the function joins it and the
+// outermost WithTransaction decides whether to commit.
 func (tm *PostgresTransactionManager) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
 	tx, err := tm.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
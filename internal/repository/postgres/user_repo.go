@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure PostgresUserRepository implements the usecase.UserRepository interface.
+var _ usecase.UserRepository = (*PostgresUserRepository)(nil)
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Save inserts a new user.
+func (r *PostgresUserRepository) Save(ctx context.Context, user *domain.User) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `INSERT INTO users (email, name, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4)
+			   RETURNING id`
+
+	err := q.QueryRowContext(ctx, query, user.Email, user.Name, user.CreatedAt, user.UpdatedAt).Scan(&user.ID)
+	if err != nil {
+		return fmt.Errorf("error saving user: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a user by their ID.
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, email, name, created_at, updated_at FROM users WHERE id = $1`
+
+	return scanUserOrNil(q.QueryRowContext(ctx, query, id))
+}
+
+// FindByEmail retrieves a user by their email address.
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, email, name, created_at, updated_at FROM users WHERE email = $1`
+
+	return scanUserOrNil(q.QueryRowContext(ctx, query, email))
+}
+
+func scanUserOrNil(row *sql.Row) (*domain.User, error) {
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Return nil, nil to indicate not found, use case will handle it.
+		}
+		return nil, fmt.Errorf("error scanning user: %w", err)
+	}
+	return &u, nil
+}
+
+// FindAll retrieves a paginated list of all users.
+func (r *PostgresUserRepository) FindAll(ctx context.Context, limit int, offset int) ([]domain.User, error) {
+	q := getQuerier(ctx, r.db)
+
+	query := `SELECT id, email, name, created_at, updated_at
+			   FROM users
+			   ORDER BY id ASC
+			   LIMIT $1 OFFSET $2`
+
+	rows, err := q.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error querying users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning user row: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// Update persists the details of an existing user.
+func (r *PostgresUserRepository) Update(ctx context.Context, user *domain.User) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `UPDATE users SET email = $1, name = $2, updated_at = $3 WHERE id = $4`
+
+	result, err := q.ExecContext(ctx, query, user.Email, user.Name, time.Now(), user.ID)
+	if err != nil {
+		return fmt.Errorf("error updating user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("user not found for update")
+	}
+
+	return nil
+}
+
+// Delete removes a user. Their addresses and carts are removed by the foreign key cascade.
+func (r *PostgresUserRepository) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+
+	query := `DELETE FROM users WHERE id = $1`
+
+	result, err := q.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("user not found for delete")
+	}
+
+	return nil
+}
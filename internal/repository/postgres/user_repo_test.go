@@ -0,0 +1,184 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/config"
+	"github.com/elokanugrah/go-order-system/internal/database"
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/repository/postgres"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserRepositorySuite struct {
+	suite.Suite
+
+	db          *sql.DB
+	repo        *postgres.PostgresUserRepository
+	addressRepo *postgres.PostgresAddressRepository
+}
+
+// SetupSuite runs once before all tests in this suite.
+// It's used for setting up the database connection.
+func (s *UserRepositorySuite) SetupSuite() {
+	cfg := config.Load()
+	s.db = database.NewConnection(cfg)
+	s.repo = postgres.NewUserRepository(s.db)
+	s.addressRepo = postgres.NewAddressRepository(s.db)
+}
+
+// TearDownSuite runs once after all tests in this suite are finished.
+func (s *UserRepositorySuite) TearDownSuite() {
+	if err := s.db.Close(); err != nil {
+		log.Fatalf("Failed to close test database connection: %v", err)
+	}
+}
+
+// TearDownTest runs after each test function.
+// It cleans all relevant tables to ensure test isolation.
+func (s *UserRepositorySuite) TearDownTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE addresses, users RESTART IDENTITY CASCADE")
+	s.Suite.NoError(err)
+}
+
+// This function is the entry point for running the test suite.
+func TestUserRepository(t *testing.T) {
+	suite.Run(t, new(UserRepositorySuite))
+}
+
+// TestUserLifecycle tests saving, finding, updating and deleting a user.
+func (s *UserRepositorySuite) TestUserLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	user, err := domain.NewUser("Budi@Example.com", "Budi")
+	assert.NoError(err)
+
+	// Act
+	err = s.repo.Save(ctx, user)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(user.ID)
+
+	byEmail, err := s.repo.FindByEmail(ctx, "budi@example.com")
+	assert.NoError(err)
+	assert.NotNil(byEmail)
+	assert.Equal(user.ID, byEmail.ID)
+
+	assert.NoError(user.Update("budi.santoso@example.com", "Budi Santoso"))
+	assert.NoError(s.repo.Update(ctx, user))
+
+	found, err := s.repo.FindByID(ctx, user.ID)
+	assert.NoError(err)
+	assert.Equal("budi.santoso@example.com", found.Email)
+	assert.Equal("Budi Santoso", found.Name)
+
+	all, err := s.repo.FindAll(ctx, 10, 0)
+	assert.NoError(err)
+	assert.Len(all, 1)
+
+	assert.NoError(s.repo.Delete(ctx, user.ID))
+	deleted, err := s.repo.FindByID(ctx, user.ID)
+	assert.NoError(err)
+	assert.Nil(deleted)
+	assert.Error(s.repo.Delete(ctx, user.ID))
+}
+
+// TestSave_DuplicateEmail tests that the database rejects a second user with the same email.
+func (s *UserRepositorySuite) TestSave_DuplicateEmail() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	first, err := domain.NewUser("siti@example.com", "Siti")
+	assert.NoError(err)
+	assert.NoError(s.repo.Save(ctx, first))
+
+	second, err := domain.NewUser("siti@example.com", "Another Siti")
+	assert.NoError(err)
+
+	// Act
+	err = s.repo.Save(ctx, second)
+
+	// Assert
+	assert.Error(err)
+}
+
+// TestAddressLifecycle tests the addresses of a user, which are deleted along with the user.
+func (s *UserRepositorySuite) TestAddressLifecycle() {
+	assert := s.Suite.Assert()
+	ctx := context.Background()
+
+	user, err := domain.NewUser("budi@example.com", "Budi")
+	assert.NoError(err)
+	assert.NoError(s.repo.Save(ctx, user))
+
+	address, err := domain.NewAddress(user.ID, "Home", jakartaAddress())
+	assert.NoError(err)
+
+	// Act
+	err = s.addressRepo.Save(ctx, address)
+
+	// Assert
+	assert.NoError(err)
+	assert.NotZero(address.ID)
+
+	found, err := s.addressRepo.FindByID(ctx, address.ID)
+	assert.NoError(err)
+	assert.Equal(jakartaAddress(), found.PostalAddress)
+	assert.Equal("Home", found.Label)
+
+	postal := jakartaAddress()
+	postal.Line2 = "Lantai 3"
+	assert.NoError(found.Update("Office", postal))
+	assert.NoError(s.addressRepo.Update(ctx, found))
+
+	addresses, err := s.addressRepo.FindByUserID(ctx, user.ID)
+	assert.NoError(err)
+	assert.Len(addresses, 1)
+	assert.Equal("Office", addresses[0].Label)
+	assert.Equal("Lantai 3", addresses[0].Line2)
+
+	assert.NoError(s.repo.Delete(ctx, user.ID))
+	missing, err := s.addressRepo.FindByID(ctx, address.ID)
+	assert.NoError(err)
+	assert.Nil(missing)
+}
+
+// jakartaAddress returns a valid postal address for tests.
+func jakartaAddress() domain.PostalAddress {
+	return domain.PostalAddress{
+		RecipientName: "Budi Santoso",
+		Phone:         "+6281234567890",
+		Line1:         "Jl. Sudirman No. 1",
+		City:          "Jakarta",
+		Province:      "DKI Jakarta",
+		PostalCode:    "10220",
+		Country:       "ID",
+	}
+}
+
+// createUsers inserts users with the given IDs, which orders must refer to.
+func createUsers(db *sql.DB, ids ...int64) error {
+	for _, id := range ids {
+		_, err := db.Exec(`INSERT INTO users (id, email, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING`,
+			id, fmt.Sprintf("user%d@example.com", id), fmt.Sprintf("User %d", id))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userIDs returns the IDs 1 to n.
+func userIDs(n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
@@ -0,0 +1,101 @@
+// Package tax provides a rule-based usecase.TaxCalculator.
+package tax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+)
+
+// Ensure RuleCalculator implements the usecase.TaxCalculator interface.
+var _ usecase.TaxCalculator = (*RuleCalculator)(nil)
+
+// Rule sets the tax rate of a product category in a region.
+// A rule without a category applies to every category of its region that has no rule of its own.
+type Rule struct {
+	Region   string             `json:"region"`
+	Category domain.TaxCategory `json:"category"`
+	Rate     domain.TaxRate     `json:"rate"`
+}
+
+// DefaultRules are the Indonesian rules: 11% VAT (PPN), with exempt products untaxed.
+func DefaultRules() []Rule {
+	ppn, _ := domain.ParseTaxRate("0.11")
+	return []Rule{
+		{Region: "ID", Category: domain.TaxCategoryStandard, Rate: ppn},
+		{Region: "ID", Category: domain.TaxCategoryExempt, Rate: 0},
+	}
+}
+
+// LoadRules reads rules from a JSON file such as:
+//
+//	[{"region": "ID", "category": "standard", "rate": "0.11"}]
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tax rules file: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing tax rules file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// RuleCalculator looks tax rates up in a fixed set of rules.
+type RuleCalculator struct {
+	rates      map[ruleKey]domain.TaxRate
+	categories map[domain.TaxCategory]bool
+}
+
+type ruleKey struct {
+	region   string
+	category domain.TaxCategory
+}
+
+// NewRuleCalculator returns a calculator for the given rules.
+// No two rules may cover the same region and category.
+func NewRuleCalculator(rules []Rule) (*RuleCalculator, error) {
+	rates := make(map[ruleKey]domain.TaxRate, len(rules))
+	categories := make(map[domain.TaxCategory]bool)
+	for _, rule := range rules {
+		if rule.Region == "" {
+			return nil, fmt.Errorf("tax rule for category %q has no region", rule.Category)
+		}
+
+		key := ruleKey{region: rule.Region, category: rule.Category}
+		if _, ok := rates[key]; ok {
+			return nil, fmt.Errorf("duplicate tax rule for %s/%s", rule.Region, rule.Category)
+		}
+		rates[key] = rule.Rate
+		if rule.Category != "" {
+			categories[rule.Category] = true
+		}
+	}
+	return &RuleCalculator{rates: rates, categories: categories}, nil
+}
+
+// TaxRate returns the rate of the rule for the category in the region, falling back to
+// the region-wide rule. Products without a category are taxed as TaxCategoryStandard.
+func (c *RuleCalculator) TaxRate(_ context.Context, category domain.TaxCategory, region string) (domain.TaxRate, error) {
+	category = category.OrDefault()
+
+	if rate, ok := c.rates[ruleKey{region: region, category: category}]; ok {
+		return rate, nil
+	}
+	if rate, ok := c.rates[ruleKey{region: region}]; ok {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("%w: %s in %s", domain.ErrTaxRuleNotFound, category, region)
+}
+
+// KnowsCategory reports whether a rule of any region names the category.
+// Region-wide rules name no category, so they do not make every category known.
+func (c *RuleCalculator) KnowsCategory(_ context.Context, category domain.TaxCategory) (bool, error) {
+	return c.categories[category.OrDefault()], nil
+}
@@ -0,0 +1,103 @@
+package tax_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/tax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rate parses a tax rate, failing loudly on bad test data.
+func rate(s string) domain.TaxRate {
+	r, err := domain.ParseTaxRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func TestRuleCalculator(t *testing.T) {
+	calculator, err := tax.NewRuleCalculator([]tax.Rule{
+		{Region: "ID", Category: domain.TaxCategoryStandard, Rate: rate("0.11")},
+		{Region: "ID", Category: domain.TaxCategoryExempt, Rate: 0},
+		{Region: "SG", Rate: rate("0.09")},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		category domain.TaxCategory
+		region   string
+		expected domain.TaxRate
+	}{
+		{"category rule", domain.TaxCategoryStandard, "ID", rate("0.11")},
+		{"exempt category", domain.TaxCategoryExempt, "ID", 0},
+		{"missing category is standard", "", "ID", rate("0.11")},
+		{"region-wide rule", domain.TaxCategoryExempt, "SG", rate("0.09")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := calculator.TaxRate(context.Background(), tc.category, tc.region)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("should report a category without a rule in the region", func(t *testing.T) {
+		_, err := calculator.TaxRate(context.Background(), "luxury", "ID")
+
+		assert.ErrorIs(t, err, domain.ErrTaxRuleNotFound)
+	})
+
+	t.Run("should report an unknown region", func(t *testing.T) {
+		_, err := calculator.TaxRate(context.Background(), domain.TaxCategoryStandard, "MY")
+
+		assert.ErrorIs(t, err, domain.ErrTaxRuleNotFound)
+	})
+
+	t.Run("should know only the categories that rules name", func(t *testing.T) {
+		for category, expected := range map[domain.TaxCategory]bool{
+			domain.TaxCategoryStandard: true,
+			domain.TaxCategoryExempt:   true,
+			"":                         true,
+			"luxury":                   false,
+		} {
+			known, err := calculator.KnowsCategory(context.Background(), category)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, known, category)
+		}
+	})
+}
+
+func TestNewRuleCalculator_RejectsDuplicateRules(t *testing.T) {
+	_, err := tax.NewRuleCalculator([]tax.Rule{
+		{Region: "ID", Category: domain.TaxCategoryStandard, Rate: rate("0.11")},
+		{Region: "ID", Category: domain.TaxCategoryStandard, Rate: rate("0.12")},
+	})
+
+	assert.Error(t, err)
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tax.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"region": "ID", "category": "standard", "rate": "0.12"},
+		{"region": "ID", "category": "exempt", "rate": 0}
+	]`), 0o600))
+
+	rules, err := tax.LoadRules(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []tax.Rule{
+		{Region: "ID", Category: domain.TaxCategoryStandard, Rate: rate("0.12")},
+		{Region: "ID", Category: domain.TaxCategoryExempt, Rate: 0},
+	}, rules)
+}
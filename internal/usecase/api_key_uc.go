@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+)
+
+var ErrAPIKeyNotFound = domain.NewError(domain.KindNotFound, "api_key_not_found", "API key not found")
+
+type APIKeyUseCase struct {
+	apiKeyRepo APIKeyRepository
+}
+
+func NewAPIKeyUseCase(ar APIKeyRepository) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo: ar,
+	}
+}
+
+// CreateAPIKey mints and stores a new API key. The plain key is returned
+// only here; afterwards just a hash of its secret is known.
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, input dto.CreateAPIKeyInput) (*dto.CreatedAPIKey, error) {
+	if err := authorize(ctx, domain.ScopeManageAPIKeys); err != nil {
+		return nil, err
+	}
+
+	key, plain, err := domain.NewAPIKey(input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedAPIKey{APIKey: key, Key: plain}, nil
+}
+
+// ListAPIKeys returns every API key, including expired and revoked ones.
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	if err := authorize(ctx, domain.ScopeManageAPIKeys); err != nil {
+		return nil, err
+	}
+
+	return uc.apiKeyRepo.FindAll(ctx)
+}
+
+// RevokeAPIKey permanently disables an API key.
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id int64) (*domain.APIKey, error) {
+	if err := authorize(ctx, domain.ScopeManageAPIKeys); err != nil {
+		return nil, err
+	}
+
+	key, err := uc.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	if err := key.Revoke(); err != nil {
+		return nil, err
+	}
+	if err := uc.apiKeyRepo.Revoke(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// AuthenticateAPIKey returns the principal of a plain API key and records its use.
+// Unknown, expired and revoked keys all return domain.ErrInvalidAPIKey.
+func (uc *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, plain string) (*domain.Principal, error) {
+	prefix, secret, err := domain.ParseAPIKey(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := uc.apiKeyRepo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || !key.Matches(secret) || !key.IsActiveAt(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	if key.Touch(now) {
+		// The last use is informational, so failing to record it does not fail the request.
+		if err := uc.apiKeyRepo.TouchLastUsed(ctx, key); err != nil {
+			log.Printf("WARN: failed to record use of API key %d: %v", key.ID, err)
+		}
+	}
+
+	return key.Principal(), nil
+}
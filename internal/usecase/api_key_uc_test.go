@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyUseCase(t *testing.T) {
+	var mockAPIKeyRepo *mocks.APIKeyRepository
+	var apiKeyUseCase *usecase.APIKeyUseCase
+
+	setup := func() {
+		mockAPIKeyRepo = new(mocks.APIKeyRepository)
+		apiKeyUseCase = usecase.NewAPIKeyUseCase(mockAPIKeyRepo)
+	}
+
+	// mint creates a key as the repository would return it, with its plain key.
+	mint := func(t *testing.T) (*domain.APIKey, string) {
+		key, plain, err := domain.NewAPIKey("warehouse", []domain.Scope{domain.ScopeTransitionOrders}, nil)
+		require.NoError(t, err)
+		key.ID = 7
+		return key, plain
+	}
+
+	t.Run("CreateAPIKey", func(t *testing.T) {
+		t.Run("should store the key and return the plain key once", func(t *testing.T) {
+			setup()
+			mockAPIKeyRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+
+			created, err := apiKeyUseCase.CreateAPIKey(asUser(1, domain.RoleAdmin), dto.CreateAPIKeyInput{
+				Name:   "warehouse",
+				Scopes: []domain.Scope{domain.ScopeTransitionOrders},
+			})
+
+			require.NoError(t, err)
+			_, secret, err := domain.ParseAPIKey(created.Key)
+			require.NoError(t, err)
+			assert.True(t, created.APIKey.Matches(secret))
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+
+		t.Run("should forbid non-admins to create keys", func(t *testing.T) {
+			setup()
+
+			_, err := apiKeyUseCase.CreateAPIKey(asUser(1, domain.RoleStaff), dto.CreateAPIKeyInput{
+				Name:   "warehouse",
+				Scopes: []domain.Scope{domain.ScopeTransitionOrders},
+			})
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockAPIKeyRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("RevokeAPIKey", func(t *testing.T) {
+		t.Run("should revoke the key", func(t *testing.T) {
+			setup()
+			key, _ := mint(t)
+			mockAPIKeyRepo.On("FindByID", mock.Anything, int64(7)).Return(key, nil).Once()
+			mockAPIKeyRepo.On("Revoke", mock.Anything, key).Return(nil).Once()
+
+			revoked, err := apiKeyUseCase.RevokeAPIKey(asUser(1, domain.RoleAdmin), 7)
+
+			require.NoError(t, err)
+			assert.NotNil(t, revoked.RevokedAt)
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+
+		t.Run("should return revoked error when a concurrent revoke came first", func(t *testing.T) {
+			setup()
+			key, _ := mint(t)
+			mockAPIKeyRepo.On("FindByID", mock.Anything, int64(7)).Return(key, nil).Once()
+			mockAPIKeyRepo.On("Revoke", mock.Anything, key).Return(domain.ErrAPIKeyRevoked).Once()
+
+			_, err := apiKeyUseCase.RevokeAPIKey(asUser(1, domain.RoleAdmin), 7)
+
+			assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+		})
+
+		t.Run("should return not found error when the key does not exist", func(t *testing.T) {
+			setup()
+			mockAPIKeyRepo.On("FindByID", mock.Anything, int64(99)).Return(nil, nil).Once()
+
+			_, err := apiKeyUseCase.RevokeAPIKey(asUser(1, domain.RoleAdmin), 99)
+
+			assert.ErrorIs(t, err, usecase.ErrAPIKeyNotFound)
+		})
+	})
+
+	t.Run("AuthenticateAPIKey", func(t *testing.T) {
+		t.Run("should return the principal of the key and record its use", func(t *testing.T) {
+			setup()
+			key, plain := mint(t)
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, key.Prefix).Return(key, nil).Once()
+			mockAPIKeyRepo.On("TouchLastUsed", mock.Anything, mock.MatchedBy(func(k *domain.APIKey) bool {
+				return k.LastUsedAt != nil
+			})).Return(nil).Once()
+
+			principal, err := apiKeyUseCase.AuthenticateAPIKey(context.Background(), plain)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), principal.APIKeyID)
+			assert.Zero(t, principal.UserID)
+			assert.Equal(t, []domain.Scope{domain.ScopeTransitionOrders}, principal.Scopes)
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+
+		t.Run("should not fail when recording the use fails", func(t *testing.T) {
+			setup()
+			key, plain := mint(t)
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, key.Prefix).Return(key, nil).Once()
+			mockAPIKeyRepo.On("TouchLastUsed", mock.Anything, key).Return(errors.New("db down")).Once()
+
+			_, err := apiKeyUseCase.AuthenticateAPIKey(context.Background(), plain)
+
+			assert.NoError(t, err)
+		})
+
+		t.Run("should reject wrong, expired and revoked keys", func(t *testing.T) {
+			setup()
+			key, plain := mint(t)
+			expired, expiredPlain := mint(t)
+			past := time.Now().Add(-time.Minute)
+			expired.ExpiresAt = &past
+			revoked, revokedPlain := mint(t)
+			require.NoError(t, revoked.Revoke())
+
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, key.Prefix).Return(key, nil)
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, expired.Prefix).Return(expired, nil)
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, revoked.Prefix).Return(revoked, nil)
+			mockAPIKeyRepo.On("FindByPrefix", mock.Anything, "gos_unknown").Return(nil, nil)
+
+			for _, candidate := range []string{plain + "x", expiredPlain, revokedPlain, "gos_unknown.secret", "not-a-key"} {
+				principal, err := apiKeyUseCase.AuthenticateAPIKey(context.Background(), candidate)
+
+				assert.ErrorIs(t, err, domain.ErrInvalidAPIKey, candidate)
+				assert.Nil(t, principal)
+			}
+			mockAPIKeyRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+		})
+	})
+}
+
+func TestPolicy_APIKeyScopes(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.TransactionManager), new(mocks.OutboxRepository), new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+	mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Order{ID: 1, UserID: 123}, nil)
+
+	withScopes := func(scopes ...domain.Scope) context.Context {
+		return domain.WithPrincipal(context.Background(), &domain.Principal{APIKeyID: 7, Scopes: scopes})
+	}
+
+	_, err := orderUseCase.GetOrderByID(withScopes(domain.ScopeReadAnyOrder), 1)
+	assert.NoError(t, err)
+
+	_, err = orderUseCase.GetOrderByID(withScopes(domain.ScopeTransitionOrders), 1)
+	assert.ErrorIs(t, err, usecase.ErrForbidden)
+
+	_, err = orderUseCase.CreateOrder(withScopes(domain.ScopeReadAnyOrder), dto.CreateOrderInput{Items: []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 1}}})
+	assert.ErrorIs(t, err, usecase.ErrForbidden, "an API key is no user, so it owns no orders")
+
+	// A key that may act for any user still has to name the user.
+	items := []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 1}}
+	_, err = orderUseCase.CreateOrder(withScopes(domain.ScopeActForAnyUser), dto.CreateOrderInput{Items: items})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	_, _, err = orderUseCase.CreateOrderWithIdempotencyKey(withScopes(domain.ScopeActForAnyUser), "key-1", dto.CreateOrderInput{Items: items})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	_, err = orderUseCase.ListOrdersByUser(withScopes(domain.ScopeReadAnyOrder), 0, 1, 10)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	mockOrderRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+)
+
+var ErrCartNotFound = domain.NewError(domain.KindNotFound, "cart_not_found", "cart not found")
+
+type CartUseCase struct {
+	cartRepo     CartRepository
+	productRepo  ProductRepository
+	rateProvider RateProvider
+	txManager    TransactionManager
+	orderUseCase *OrderUseCase
+}
+
+// Checkout goes through OrderUseCase.CreateOrder, so carts and orders share stock and pricing rules.
+func NewCartUseCase(cr CartRepository, pr ProductRepository, rp RateProvider, tm TransactionManager, ouc *OrderUseCase) *CartUseCase {
+	return &CartUseCase{
+		cartRepo:     cr,
+		productRepo:  pr,
+		rateProvider: rp,
+		txManager:    tm,
+		orderUseCase: ouc,
+	}
+}
+
+// CreateCart creates an empty cart for an existing user.
+func (uc *CartUseCase) CreateCart(ctx context.Context, input dto.CreateCartInput) (*domain.Cart, error) {
+	if err := authorizeOwner(ctx, input.UserID, domain.ScopeActForAnyUser); err != nil {
+		return nil, err
+	}
+	if input.UserID <= 0 {
+		return nil, domain.NewFieldError(domain.ErrInvalidInput, "user_id", "must be positive")
+	}
+	if _, err := uc.orderUseCase.users.findUser(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	cart := domain.NewCart(input.UserID, input.Currency)
+	if err := uc.cartRepo.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// GetCart returns a cart priced with the current product prices and stock.
+func (uc *CartUseCase) GetCart(ctx context.Context, id int64) (*dto.CartView, error) {
+	cart, err := uc.cartRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, ErrCartNotFound
+	}
+	if err := authorizeOwner(ctx, cart.UserID, domain.ScopeActForAnyUser); err != nil {
+		return nil, err
+	}
+
+	return uc.price(ctx, cart)
+}
+
+// AddItem adds a quantity of a product to a cart, on top of any quantity already in it.
+func (uc *CartUseCase) AddItem(ctx context.Context, cartID, productID int64, quantity int) (*dto.CartView, error) {
+	err := uc.modifyCart(ctx, cartID, func(txCtx context.Context, cart *domain.Cart) error {
+		product, err := uc.productRepo.FindByID(txCtx, productID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return domain.Unprocessable(ErrProductNotFound)
+		}
+
+		item, err := cart.AddItem(productID, quantity)
+		if err != nil {
+			return err
+		}
+		return uc.cartRepo.SaveItem(txCtx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetCart(ctx, cartID)
+}
+
+// UpdateItemQuantity replaces the quantity of a product already in a cart.
+func (uc *CartUseCase) UpdateItemQuantity(ctx context.Context, cartID, productID int64, quantity int) (*dto.CartView, error) {
+	err := uc.modifyCart(ctx, cartID, func(txCtx context.Context, cart *domain.Cart) error {
+		item, err := cart.SetItemQuantity(productID, quantity)
+		if err != nil {
+			return err
+		}
+		return uc.cartRepo.SaveItem(txCtx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetCart(ctx, cartID)
+}
+
+// RemoveItem removes a product from a cart.
+func (uc *CartUseCase) RemoveItem(ctx context.Context, cartID, productID int64) (*dto.CartView, error) {
+	err := uc.modifyCart(ctx, cartID, func(txCtx context.Context, cart *domain.Cart) error {
+		if err := cart.RemoveItem(productID); err != nil {
+			return err
+		}
+		return uc.cartRepo.DeleteItem(txCtx, cartID, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetCart(ctx, cartID)
+}
+
+// Checkout turns a cart into an order through OrderUseCase.CreateOrder and marks
+// the cart as checked out, in a single transaction. The coupon, tax region and
+// shipping address in input are applied to the order. A cart can be checked out only once.
+func (uc *CartUseCase) Checkout(ctx context.Context, cartID int64, input dto.CheckoutInput) (*domain.Order, error) {
+	var order *domain.Order
+
+	err := uc.modifyCart(ctx, cartID, func(txCtx context.Context, cart *domain.Cart) error {
+		if err := cart.CanCheckOut(); err != nil {
+			return err
+		}
+
+		items := make([]dto.CreateOrderItemInput, len(cart.Items))
+		for i, item := range cart.Items {
+			items[i] = dto.CreateOrderItemInput{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+			}
+		}
+
+		var err error
+		order, err = uc.orderUseCase.CreateOrder(txCtx, dto.CreateOrderInput{
+			UserID:     cart.UserID,
+			Currency:   cart.Currency,
+			CouponCode: input.CouponCode,
+			Region:     input.Region,
+			Items:      items,
+
+			ShippingAddressID: input.ShippingAddressID,
+			ShippingAddress:   input.ShippingAddress,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := cart.CheckOut(order.ID); err != nil {
+			return err
+		}
+		return uc.cartRepo.Update(txCtx, cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// modifyCart runs fn on a locked cart within a transaction, so concurrent
+// changes to the same cart are applied one after the other. Only the owner
+// of the cart may change it.
+func (uc *CartUseCase) modifyCart(ctx context.Context, cartID int64, fn func(txCtx context.Context, cart *domain.Cart) error) error {
+	return uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		cart, err := uc.cartRepo.FindByIDForUpdate(txCtx, cartID)
+		if err != nil {
+			return err
+		}
+		if cart == nil {
+			return ErrCartNotFound
+		}
+		if err := authorizeOwner(ctx, cart.UserID, domain.ScopeActForAnyUser); err != nil {
+			return err
+		}
+
+		return fn(txCtx, cart)
+	})
+}
+
+// price builds the view of a cart from the current prices and stock of its products,
+// converted into the cart currency.
+func (uc *CartUseCase) price(ctx context.Context, cart *domain.Cart) (*dto.CartView, error) {
+	view := &dto.CartView{
+		ID:       cart.ID,
+		UserID:   cart.UserID,
+		Currency: cart.Currency,
+		Status:   cart.Status,
+		OrderID:  cart.OrderID,
+		Items:    make([]dto.CartItemView, 0, len(cart.Items)),
+		Subtotal: domain.NewMoney(0, cart.Currency),
+	}
+	if len(cart.Items) == 0 {
+		return view, nil
+	}
+
+	productIDs := make([]int64, len(cart.Items))
+	for i, item := range cart.Items {
+		productIDs[i] = item.ProductID
+	}
+	products, err := uc.productRepo.FindManyByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	productsByID := make(map[int64]domain.Product, len(products))
+	for _, p := range products {
+		productsByID[p.ID] = p
+	}
+
+	rates := make(map[string]domain.Rate)
+	for _, item := range cart.Items {
+		itemView := dto.CartItemView{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: domain.NewMoney(0, cart.Currency),
+			LineTotal: domain.NewMoney(0, cart.Currency),
+		}
+
+		if p, ok := productsByID[item.ProductID]; ok {
+			rate, err := lookupRate(ctx, uc.rateProvider, p.Price.Currency, cart.Currency, rates)
+			if err != nil {
+				return nil, err
+			}
+
+			itemView.Name = p.Name
+			itemView.UnitPrice = p.Price.Convert(cart.Currency, rate)
+			itemView.LineTotal = itemView.UnitPrice.Mul(item.Quantity)
+			itemView.AvailableQuantity = p.Quantity
+			itemView.Available = p.IsStockAvailable(item.Quantity)
+
+			view.Subtotal, err = view.Subtotal.Add(itemView.LineTotal)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		view.Items = append(view.Items, itemView)
+	}
+
+	return view, nil
+}
@@ -0,0 +1,262 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCartUseCase(t *testing.T) {
+	var mockCartRepo *mocks.CartRepository
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var cartUseCase *usecase.CartUseCase
+
+	// setup is a helper function to initialize components for each test.
+	setup := func() {
+		mockCartRepo = new(mocks.CartRepository)
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		rateProvider := new(mocks.RateProvider)
+
+		orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), rateProvider, usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+		cartUseCase = usecase.NewCartUseCase(mockCartRepo, mockProductRepo, rateProvider, mockTxManager, orderUseCase)
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Maybe()
+	}
+
+	// openCart returns an open cart with two products in it.
+	openCart := func() *domain.Cart {
+		return &domain.Cart{
+			ID:       1,
+			UserID:   123,
+			Currency: domain.DefaultCurrency,
+			Status:   domain.CartStatusOpen,
+			Items: []domain.CartItem{
+				{ID: 1, CartID: 1, ProductID: 1, Quantity: 2},
+				{ID: 2, CartID: 1, ProductID: 2, Quantity: 3},
+			},
+		}
+	}
+
+	t.Run("GetCart", func(t *testing.T) {
+		t.Run("should price items live and report stock availability", func(t *testing.T) {
+			setup()
+			cart := openCart()
+			cart.Items = append(cart.Items, domain.CartItem{ID: 3, CartID: 1, ProductID: 3, Quantity: 1})
+
+			mockCartRepo.On("FindByID", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1, 2, 3}).Return([]domain.Product{
+				{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+				{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 2},
+				// Product 3 no longer exists.
+			}, nil).Once()
+
+			view, err := cartUseCase.GetCart(asUser(123, domain.RoleCustomer), 1)
+
+			assert.NoError(t, err)
+			assert.Len(t, view.Items, 3)
+			assert.Equal(t, idr("20000"), view.Items[0].LineTotal)
+			assert.True(t, view.Items[0].Available)
+			assert.Equal(t, idr("15000"), view.Items[1].LineTotal)
+			assert.False(t, view.Items[1].Available) // Only 2 of 3 in stock.
+			assert.Equal(t, 2, view.Items[1].AvailableQuantity)
+			assert.False(t, view.Items[2].Available)
+			assert.Equal(t, idr("35000"), view.Subtotal)
+			mockProductRepo.AssertExpectations(t)
+		})
+
+		t.Run("should return not found error when cart does not exist", func(t *testing.T) {
+			setup()
+			mockCartRepo.On("FindByID", mock.Anything, int64(99)).Return(nil, nil).Once()
+
+			view, err := cartUseCase.GetCart(asUser(123, domain.RoleCustomer), 99)
+
+			assert.ErrorIs(t, err, usecase.ErrCartNotFound)
+			assert.Nil(t, view)
+		})
+
+		t.Run("should forbid access to carts of other users", func(t *testing.T) {
+			setup()
+			mockCartRepo.On("FindByID", mock.Anything, int64(1)).Return(openCart(), nil).Once()
+
+			view, err := cartUseCase.GetCart(asUser(456, domain.RoleCustomer), 1)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			assert.Nil(t, view)
+		})
+	})
+
+	t.Run("AddItem", func(t *testing.T) {
+		t.Run("should add to the quantity already in the cart", func(t *testing.T) {
+			setup()
+			cart := openCart()
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1}, nil).Once()
+			mockCartRepo.On("SaveItem", mock.Anything, mock.MatchedBy(func(item *domain.CartItem) bool {
+				return item.ProductID == 1 && item.Quantity == 5
+			})).Return(nil).Once()
+			mockCartRepo.On("FindByID", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1, 2}).Return([]domain.Product{}, nil).Once()
+
+			_, err := cartUseCase.AddItem(asUser(123, domain.RoleCustomer), 1, 1, 3)
+
+			assert.NoError(t, err)
+			mockCartRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject an unknown product", func(t *testing.T) {
+			setup()
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(openCart(), nil).Once()
+			mockProductRepo.On("FindByID", mock.Anything, int64(99)).Return(nil, nil).Once()
+
+			view, err := cartUseCase.AddItem(asUser(123, domain.RoleCustomer), 1, 99, 1)
+
+			assert.ErrorIs(t, err, usecase.ErrProductNotFound)
+			assert.Nil(t, view)
+			mockCartRepo.AssertNotCalled(t, "SaveItem", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("RemoveItem", func(t *testing.T) {
+		t.Run("should report a product that is not in the cart", func(t *testing.T) {
+			setup()
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(openCart(), nil).Once()
+
+			_, err := cartUseCase.RemoveItem(asUser(123, domain.RoleCustomer), 1, 99)
+
+			assert.ErrorIs(t, err, domain.ErrCartItemNotFound)
+			mockCartRepo.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("Checkout", func(t *testing.T) {
+		t.Run("should create an order from the cart and mark it checked out", func(t *testing.T) {
+			setup()
+			cart := openCart()
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return([]domain.Product{
+				{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+				{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+			}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
+			mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).
+				Run(func(args mock.Arguments) { args.Get(1).(*domain.Order).ID = 42 }).
+				Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+			mockCartRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.Cart) bool {
+				return c.Status == domain.CartStatusCheckedOut && *c.OrderID == 42
+			})).Return(nil).Once()
+
+			order, err := cartUseCase.Checkout(asUser(123, domain.RoleCustomer), 1, dto.CheckoutInput{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(42), order.ID)
+			assert.Equal(t, int64(123), order.UserID)
+			assert.Equal(t, idr("35000"), order.TotalAmount)
+			mockProductRepo.AssertExpectations(t)
+			mockOrderRepo.AssertExpectations(t)
+			mockCartRepo.AssertExpectations(t)
+		})
+
+		t.Run("should apply the tax region and shipping address to the order", func(t *testing.T) {
+			setup()
+			cart := openCart()
+			address := &domain.PostalAddress{RecipientName: "Budi", Phone: "+6281234567890", Line1: "Jl. Sudirman 1", City: "Jakarta", PostalCode: "10220", Country: "ID"}
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return([]domain.Product{
+				{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+				{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+			}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
+			mockOrderRepo.On("Save", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+				return o.TaxRegion == "ID-BA" && o.ShippingAddress != nil && *o.ShippingAddress == *address
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+			mockCartRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Cart")).Return(nil).Once()
+
+			_, err := cartUseCase.Checkout(asUser(123, domain.RoleCustomer), 1, dto.CheckoutInput{Region: "ID-BA", ShippingAddress: address})
+
+			assert.NoError(t, err)
+			mockOrderRepo.AssertExpectations(t)
+		})
+
+		t.Run("should not check out a cart twice", func(t *testing.T) {
+			setup()
+			cart := openCart()
+			assert.NoError(t, cart.CheckOut(42))
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(cart, nil).Once()
+
+			order, err := cartUseCase.Checkout(asUser(123, domain.RoleCustomer), 1, dto.CheckoutInput{})
+
+			assert.ErrorIs(t, err, domain.ErrCartCheckedOut)
+			assert.Nil(t, order)
+			mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should keep the cart open when the order fails", func(t *testing.T) {
+			setup()
+			cart := openCart()
+
+			mockCartRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(cart, nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return([]domain.Product{
+				{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 1},
+				{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+			}, nil).Once()
+
+			order, err := cartUseCase.Checkout(asUser(123, domain.RoleCustomer), 1, dto.CheckoutInput{})
+
+			assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+			assert.Nil(t, order)
+			assert.Equal(t, domain.CartStatusOpen, cart.Status)
+			mockCartRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("CreateCart", func(t *testing.T) {
+		t.Run("should create an empty cart in the default currency", func(t *testing.T) {
+			setup()
+			mockCartRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Cart")).Return(nil).Once()
+
+			cart, err := cartUseCase.CreateCart(asUser(123, domain.RoleCustomer), dto.CreateCartInput{UserID: 123})
+
+			assert.NoError(t, err)
+			assert.Equal(t, domain.DefaultCurrency, cart.Currency)
+			assert.Equal(t, domain.CartStatusOpen, cart.Status)
+			mockCartRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject unknown users", func(t *testing.T) {
+			setup()
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("FindByID", mock.Anything, int64(999)).Return(nil, nil).Once()
+			users := usecase.NewUserUseCase(userRepo, new(mocks.AddressRepository), mockOrderRepo)
+			orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), users, new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+			cartUseCase := usecase.NewCartUseCase(mockCartRepo, mockProductRepo, new(mocks.RateProvider), mockTxManager, orderUseCase)
+
+			_, err := cartUseCase.CreateCart(asUser(1, domain.RoleAdmin), dto.CreateCartInput{UserID: 999})
+
+			assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+			mockCartRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -14,6 +14,7 @@ type ProductRepository interface {
 	// Read
 	FindByID(ctx context.Context, id int64) (*domain.Product, error)
 	FindManyByIDs(ctx context.Context, ids []int64) ([]domain.Product, error)
+	FindManyByIDsForUpdate(ctx context.Context, ids []int64) ([]domain.Product, error)
 	FindAll(ctx context.Context, limit, offset int) ([]domain.Product, error)
 
 	// Update
@@ -27,6 +28,222 @@ type ProductRepository interface {
 type OrderRepository interface {
 	// Create
 	Save(ctx context.Context, order *domain.Order) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.Order, error)
+	// FindByIDForUpdate also locks the order until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id int64) (*domain.Order, error)
+	FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]domain.Order, error)
+
+	// Update
+	UpdateStatus(ctx context.Context, order *domain.Order) error
+}
+
+// UserRepository stores customer accounts.
+//
+//go:generate mockery --name UserRepository --output ./mocks --case=snake
+type UserRepository interface {
+	// Create
+	Save(ctx context.Context, user *domain.User) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.User, error)
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindAll(ctx context.Context, limit, offset int) ([]domain.User, error)
+
+	// Update
+	Update(ctx context.Context, user *domain.User) error
+
+	// Delete also deletes the addresses of the user.
+	Delete(ctx context.Context, id int64) error
+}
+
+// AddressRepository stores the addresses saved by users.
+//
+//go:generate mockery --name AddressRepository --output ./mocks --case=snake
+type AddressRepository interface {
+	// Create
+	Save(ctx context.Context, address *domain.Address) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.Address, error)
+	FindByUserID(ctx context.Context, userID int64) ([]domain.Address, error)
+
+	// Update
+	Update(ctx context.Context, address *domain.Address) error
+
+	// Delete
+	Delete(ctx context.Context, id int64) error
+}
+
+// PaymentRepository stores the payments of orders.
+//
+//go:generate mockery --name PaymentRepository --output ./mocks --case=snake
+type PaymentRepository interface {
+	// Create
+	Save(ctx context.Context, payment *domain.Payment) error
+
+	// Read
+	FindByOrderID(ctx context.Context, orderID int64) ([]domain.Payment, error)
+	// FindByProviderPaymentIDForUpdate also locks the payment until the transaction ends.
+	FindByProviderPaymentIDForUpdate(ctx context.Context, provider, providerPaymentID string) (*domain.Payment, error)
+
+	// Update persists the status and the refunded amount of a payment.
+	Update(ctx context.Context, payment *domain.Payment) error
+}
+
+// ReturnRepository stores the return requests of orders and their items.
+//
+//go:generate mockery --name ReturnRepository --output ./mocks --case=snake
+type ReturnRepository interface {
+	// Create
+	Save(ctx context.Context, rma *domain.Return) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.Return, error)
+	FindByIDForUpdate(ctx context.Context, id int64) (*domain.Return, error)
+	FindByOrderID(ctx context.Context, orderID int64) ([]domain.Return, error)
+
+	// Update persists the status and the refund of a return.
+	UpdateStatus(ctx context.Context, rma *domain.Return) error
+}
+
+// ShipmentRepository stores the shipments of orders and their items.
+//
+//go:generate mockery --name ShipmentRepository --output ./mocks --case=snake
+type ShipmentRepository interface {
+	// Create
+	Save(ctx context.Context, shipment *domain.Shipment) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.Shipment, error)
+	FindByIDForUpdate(ctx context.Context, id int64) (*domain.Shipment, error)
+	FindByOrderID(ctx context.Context, orderID int64) ([]domain.Shipment, error)
+
+	// Update persists the tracking status and delivery time of a shipment.
+	UpdateStatus(ctx context.Context, shipment *domain.Shipment) error
+}
+
+// CartRepository stores shopping carts and their items.
+//
+//go:generate mockery --name CartRepository --output ./mocks --case=snake
+type CartRepository interface {
+	// Create
+	Save(ctx context.Context, cart *domain.Cart) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.Cart, error)
+	FindByIDForUpdate(ctx context.Context, id int64) (*domain.Cart, error)
+
+	// Update
+	Update(ctx context.Context, cart *domain.Cart) error
+	SaveItem(ctx context.Context, item *domain.CartItem) error
+
+	// Delete
+	DeleteItem(ctx context.Context, cartID, productID int64) error
+}
+
+// CouponRepository stores coupons and the orders that redeemed them.
+//
+//go:generate mockery --name CouponRepository --output ./mocks --case=snake
+type CouponRepository interface {
+	// Create
+	Save(ctx context.Context, coupon *domain.Coupon) error
+	SaveRedemption(ctx context.Context, redemption *domain.CouponRedemption) error
+
+	// Read
+	FindByCode(ctx context.Context, code string) (*domain.Coupon, error)
+	FindByCodeForUpdate(ctx context.Context, code string) (*domain.Coupon, error)
+	CountRedemptionsByUser(ctx context.Context, couponID, userID int64) (int, error)
+
+	// Update
+	Update(ctx context.Context, coupon *domain.Coupon) error
+}
+
+// APIKeyRepository stores the API keys of machine clients.
+//
+//go:generate mockery --name APIKeyRepository --output ./mocks --case=snake
+type APIKeyRepository interface {
+	// Create
+	Save(ctx context.Context, key *domain.APIKey) error
+
+	// Read
+	FindByID(ctx context.Context, id int64) (*domain.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	FindAll(ctx context.Context) ([]domain.APIKey, error)
+
+	// Update
+	TouchLastUsed(ctx context.Context, key *domain.APIKey) error
+	Revoke(ctx context.Context, key *domain.APIKey) error
+}
+
+// OutboxRepository stores events that still have to be published to the message broker.
+//
+//go:generate mockery --name OutboxRepository --output ./mocks --case=snake
+type OutboxRepository interface {
+	// Create
+	Save(ctx context.Context, msg *domain.OutboxMessage) error
+
+	// Read
+	FindPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+
+	// Update
+	Update(ctx context.Context, msg *domain.OutboxMessage) error
+}
+
+// IdempotencyRepository stores the outcome of requests sent with an idempotency key.
+//
+//go:generate mockery --name IdempotencyRepository --output ./mocks --case=snake
+type IdempotencyRepository interface {
+	// Create inserts the key unless it already exists and reports whether it was inserted.
+	// Within a transaction it waits for any other transaction holding the same key.
+	Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error)
+
+	// Read
+	FindByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error)
+
+	// Update
+	Update(ctx context.Context, key *domain.IdempotencyKey) error
+}
+
+// RateProvider provides the exchange rates used to price orders in another currency.
+//
+//go:generate mockery --name RateProvider --output ./mocks --case=snake
+type RateProvider interface {
+	// FindRate returns nil, nil if no rate is known for the currency pair.
+	FindRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error)
+}
+
+// TaxCalculator determines the tax rate of products.
+//
+//go:generate mockery --name TaxCalculator --output ./mocks --case=snake
+type TaxCalculator interface {
+	// TaxRate returns the rate at which products of a category are taxed in a region.
+	// It returns an error wrapping domain.ErrTaxRuleNotFound if no rule applies.
+	TaxRate(ctx context.Context, category domain.TaxCategory, region string) (domain.TaxRate, error)
+	// KnowsCategory reports whether any rule names the category, so products may be filed under it.
+	KnowsCategory(ctx context.Context, category domain.TaxCategory) (bool, error)
+}
+
+// PaymentGateway charges customers through a payment provider. A charge is created
+// when an order is paid for, and the provider reports through a webhook whether the
+// customer authorized it; only then is its amount captured.
+// Capture, Void and Refund take an idempotency key: the provider performs an operation
+// once per key and answers repeated calls with the same key with the first result, so
+// callers may repeat a call whose outcome they did not learn.
+//
+//go:generate mockery --name PaymentGateway --output ./mocks --case=snake
+type PaymentGateway interface {
+	// Name identifies the provider. It is stored with every payment.
+	Name() string
+	// CreateCharge asks the provider to charge amount for an order and returns the ID of the charge.
+	CreateCharge(ctx context.Context, orderID int64, amount domain.Money) (string, error)
+	// Capture takes the authorized amount of a charge. Capturing a captured charge again has no effect.
+	Capture(ctx context.Context, providerPaymentID string, amount domain.Money, idempotencyKey string) error
+	// Void releases the authorization of a charge that was not captured, so the customer is not charged.
+	Void(ctx context.Context, providerPaymentID string, idempotencyKey string) error
+	// Refund pays back part or all of a captured charge and returns the ID of the refund.
+	Refund(ctx context.Context, providerPaymentID string, amount domain.Money, idempotencyKey string) (string, error)
 }
 
 // TransactionManager defines the contract for database transaction management.
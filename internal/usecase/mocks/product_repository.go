@@ -122,6 +122,36 @@ func (_m *ProductRepository) FindManyByIDs(ctx context.Context, ids []int64) ([]
 	return r0, r1
 }
 
+// FindManyByIDsForUpdate provides a mock function with given fields: ctx, ids
+func (_m *ProductRepository) FindManyByIDsForUpdate(ctx context.Context, ids []int64) ([]domain.Product, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindManyByIDsForUpdate")
+	}
+
+	var r0 []domain.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Product, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []domain.Product); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, product
 func (_m *ProductRepository) Save(ctx context.Context, product *domain.Product) error {
 	ret := _m.Called(ctx, product)
@@ -2,34 +2,102 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
-	"log"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/events"
 )
 
+var (
+	ErrOrderNotFound        = domain.NewError(domain.KindNotFound, "order_not_found", "order not found")
+	ErrIdempotencyKeyReused = domain.NewError(domain.KindUnprocessable, "idempotency_key_reused", "idempotency key was already used for a different request")
+	ErrExchangeRateNotFound = domain.NewError(domain.KindUnprocessable, "exchange_rate_not_found", "exchange rate not found")
+	ErrUnknownProduct       = domain.NewError(domain.KindUnprocessable, "unknown_product", "one or more products not found")
+	ErrOrderPaymentCaptured = domain.NewError(domain.KindConflict, "order_payment_captured", "order has a captured payment, return its items to refund them")
+	ErrOrderHasShipments    = domain.NewError(domain.KindConflict, "order_has_shipments", "order has shipments and can no longer be cancelled")
+	ErrOrderHasReturns      = domain.NewError(domain.KindConflict, "order_has_returns", "order has returns and can no longer be cancelled")
+	ErrOrderNotCaptured     = domain.NewError(domain.KindConflict, "order_not_captured", "order has no captured payment")
+	ErrOrderNotFullyShipped = domain.NewError(domain.KindConflict, "order_not_fully_shipped", "order has items that are not in a shipment")
+	ErrOrderNotDelivered    = domain.NewError(domain.KindConflict, "order_not_delivered", "order has shipments that were not delivered")
+)
+
+// UnknownProductsError reports the products of an order that do not exist.
+// It wraps ErrUnknownProduct.
+type UnknownProductsError struct {
+	ProductIDs []int64
+}
+
+func (e *UnknownProductsError) Error() string {
+	ids := make([]string, len(e.ProductIDs))
+	for i, id := range e.ProductIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return fmt.Sprintf("%v: %s", ErrUnknownProduct, strings.Join(ids, ", "))
+}
+
+func (e *UnknownProductsError) Unwrap() error {
+	return ErrUnknownProduct
+}
+
+// statusEventTypes maps an order status to the event recorded when an order enters it.
+var statusEventTypes = map[domain.OrderStatus]events.Type{
+	domain.StatusPaid:      events.TypeOrderPaid,
+	domain.StatusShipped:   events.TypeOrderShipped,
+	domain.StatusCompleted: events.TypeOrderCompleted,
+	domain.StatusCancelled: events.TypeOrderCancelled,
+}
+
 type OrderUseCase struct {
-	orderRepo   OrderRepository
-	productRepo ProductRepository
-	txManager   TransactionManager
-	broker      MessageBroker
+	orderRepo       OrderRepository
+	productRepo     ProductRepository
+	txManager       TransactionManager
+	outboxRepo      OutboxRepository
+	idempotencyRepo IdempotencyRepository
+	rateProvider    RateProvider
+	promotions      *PromotionUseCase
+	taxCalculator   TaxCalculator
+	users           *UserUseCase
+	paymentRepo     PaymentRepository
+	shipmentRepo    ShipmentRepository
+	returnRepo      ReturnRepository
 }
 
-// penambahan parameter mb
-func NewOrderUseCase(or OrderRepository, pr ProductRepository, tm TransactionManager, mb MessageBroker) *OrderUseCase {
+// Events are not published directly, they are written to the outbox and relayed by OutboxRelay.
+// Payments, shipments and returns are only read, to check whether an order may still be cancelled.
+func NewOrderUseCase(or OrderRepository, pr ProductRepository, tm TransactionManager, obr OutboxRepository, ir IdempotencyRepository, rp RateProvider, promotions *PromotionUseCase, tc TaxCalculator, users *UserUseCase, pyr PaymentRepository, sr ShipmentRepository, rr ReturnRepository) *OrderUseCase {
 	return &OrderUseCase{
-		orderRepo:   or,
-		productRepo: pr,
-		txManager:   tm,
-		broker:      mb,
+		orderRepo:       or,
+		productRepo:     pr,
+		txManager:       tm,
+		outboxRepo:      obr,
+		idempotencyRepo: ir,
+		rateProvider:    rp,
+		promotions:      promotions,
+		taxCalculator:   tc,
+		users:           users,
+		paymentRepo:     pyr,
+		shipmentRepo:    sr,
+		returnRepo:      rr,
 	}
 }
 
+// CreateOrder places an order for input.UserID, who must be the caller unless the caller may act for any user.
 func (uc *OrderUseCase) CreateOrder(ctx context.Context, input dto.CreateOrderInput) (*domain.Order, error) {
+	if err := authorizeOwner(ctx, input.UserID, domain.ScopeActForAnyUser); err != nil {
+		return nil, err
+	}
+	// API keys are not users, so they have to name the user they order for.
+	if input.UserID <= 0 {
+		return nil, domain.NewFieldError(domain.ErrInvalidInput, "user_id", "must be positive")
+	}
 	if len(input.Items) == 0 {
-		return nil, errors.New("order must contain at least one item")
+		return nil, domain.ErrEmptyOrder
 	}
 
 	var createdOrder *domain.Order
@@ -37,89 +105,560 @@ func (uc *OrderUseCase) CreateOrder(ctx context.Context, input dto.CreateOrderIn
 	// --- Transactional Business Logic ---
 	// using the callback pattern provided by our TransactionManager.
 	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
-		// Get all product IDs from the input to fetch them in one query.
-		productIDs := make([]int64, len(input.Items))
-		itemMap := make(map[int64]dto.CreateOrderItemInput)
-		for i, item := range input.Items {
-			if item.Quantity <= 0 {
-				return errors.New("item quantity must be positive")
+		var err error
+		createdOrder, err = uc.placeOrder(txCtx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return createdOrder, nil
+}
+
+// CreateOrderWithIdempotencyKey creates an order at most once per idempotency key.
+// The key is reserved in the same transaction that creates the order, so a concurrent
+// request with the same key waits for the first one and then replays its result.
+// Replaying returns the order as it was originally created and replayed set to true.
+// Reusing a key for a different request returns ErrIdempotencyKeyReused.
+func (uc *OrderUseCase) CreateOrderWithIdempotencyKey(ctx context.Context, key string, input dto.CreateOrderInput) (order *domain.Order, replayed bool, err error) {
+	if err := authorizeOwner(ctx, input.UserID, domain.ScopeActForAnyUser); err != nil {
+		return nil, false, err
+	}
+	if input.UserID <= 0 {
+		return nil, false, domain.NewFieldError(domain.ErrInvalidInput, "user_id", "must be positive")
+	}
+	if len(input.Items) == 0 {
+		return nil, false, domain.ErrEmptyOrder
+	}
+
+	requestHash, err := hashRequest(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		record := domain.NewIdempotencyKey(key, requestHash)
+
+		// Blocks while another transaction holds the same key.
+		reserved, err := uc.idempotencyRepo.Create(txCtx, record)
+		if err != nil {
+			return err
+		}
+
+		if !reserved {
+			existing, err := uc.idempotencyRepo.FindByKey(txCtx, key)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return fmt.Errorf("idempotency key %q vanished after conflict", key)
+			}
+			if existing.RequestHash != requestHash {
+				return ErrIdempotencyKeyReused
 			}
-			productIDs[i] = item.ProductID
-			itemMap[item.ProductID] = item
+
+			order = &domain.Order{}
+			if err := json.Unmarshal(existing.Response, order); err != nil {
+				return fmt.Errorf("failed to unmarshal stored response: %w", err)
+			}
+			replayed = true
+			return nil
 		}
 
-		// Fetch all required products from the database.
-		products, err := uc.productRepo.FindManyByIDs(txCtx, productIDs)
+		order, err = uc.placeOrder(txCtx, input)
 		if err != nil {
 			return err
 		}
-		if len(products) != len(productIDs) {
-			return errors.New("one or more products not found")
+
+		response, err := json.Marshal(order)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response: %w", err)
 		}
+		record.Complete(response)
+
+		return uc.idempotencyRepo.Update(txCtx, record)
+	})
+	if err != nil {
+		return nil, false, err
+	}
 
-		var orderItems []domain.OrderItem
-		var productsToUpdate []*domain.Product
+	return order, replayed, nil
+}
 
-		// Validate stock and prepare domain objects.
-		for _, p := range products {
-			itemInput := itemMap[p.ID]
+// placeOrder reserves stock and persists a new order together with its orders.created event.
+// Product prices are converted into the order currency at the current exchange rate,
+// which is snapshotted on every item. A coupon code in the input is applied to the
+// order and redeemed in the same transaction, and every item is taxed at the rate of
+// its product's tax category in the order region. The shipping address is copied onto
+// the order. It must be called within a transaction.
+func (uc *OrderUseCase) placeOrder(txCtx context.Context, input dto.CreateOrderInput) (*domain.Order, error) {
+	shippingAddress, err := uc.users.shippingAddress(txCtx, input.UserID, input.ShippingAddressID, input.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
 
-			if !p.IsStockAvailable(itemInput.Quantity) {
-				return domain.ErrInsufficientStock
-			}
+	currency := input.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	region := input.Region
+	if region == "" {
+		region = domain.DefaultTaxRegion
+	}
+
+	items, err := mergeItems(input.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all product IDs from the input to fetch them in one query.
+	productIDs := make([]int64, len(items))
+	itemMap := make(map[int64]dto.CreateOrderItemInput)
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+		itemMap[item.ProductID] = item
+	}
+
+	// Fetch and lock all required products so concurrent orders wait for this one
+	// instead of reserving the same stock.
+	products, err := uc.productRepo.FindManyByIDsForUpdate(txCtx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) != len(productIDs) {
+		return nil, missingProducts(productIDs, products)
+	}
+
+	// Check the stock of every item first, so that all shortages are reported at once.
+	var shortages []domain.StockShortage
+	for _, p := range products {
+		requested := itemMap[p.ID].Quantity
+		if !p.IsStockAvailable(requested) {
+			shortages = append(shortages, domain.StockShortage{ProductID: p.ID, Requested: requested, Available: p.Quantity})
+		}
+	}
+	if len(shortages) > 0 {
+		return nil, &domain.InsufficientStockError{Shortages: shortages}
+	}
+
+	var orderItems []domain.OrderItem
+	var productsToUpdate []*domain.Product
+	rates := make(map[string]domain.Rate)
+
+	// Prepare domain objects.
+	for _, p := range products {
+		itemInput := itemMap[p.ID]
+
+		if err := p.DecreaseStock(itemInput.Quantity); err != nil {
+			return nil, err
+		}
+
+		rate, err := lookupRate(txCtx, uc.rateProvider, p.Price.Currency, currency, rates)
+		if err != nil {
+			return nil, err
+		}
+
+		orderItems = append(orderItems, domain.OrderItem{
+			Product:      p,
+			Quantity:     itemInput.Quantity,
+			PriceAtOrder: p.Price.Convert(currency, rate),
+			BasePrice:    p.Price,
+			ExchangeRate: rate,
+		})
+
+		productToUpdate := p
+		productsToUpdate = append(productsToUpdate, &productToUpdate)
+	}
+
+	// Create the main Order domain object.
+	order, err := domain.NewOrder(input.UserID, orderItems)
+	if err != nil {
+		return nil, err
+	}
+	order.ShippingAddress = shippingAddress
+
+	var coupon *domain.Coupon
+	if input.CouponCode != "" {
+		coupon, err = uc.promotions.applyCoupon(txCtx, input.CouponCode, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	taxRates := make([]domain.TaxRate, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		taxRates[i], err = uc.taxCalculator.TaxRate(txCtx, item.Product.TaxCategory, region)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := order.ApplyTax(region, taxRates); err != nil {
+		return nil, err
+	}
+
+	// Persist the order and its items to the database.
+	if err := uc.orderRepo.Save(txCtx, order); err != nil {
+		return nil, err
+	}
+
+	if coupon != nil {
+		if err := uc.promotions.redeemCoupon(txCtx, coupon, order); err != nil {
+			return nil, err
+		}
+	}
+
+	// Persist the updated product stock for all affected products.
+	for _, p := range productsToUpdate {
+		if err := uc.productRepo.Update(txCtx, p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := enqueueEvent(txCtx, uc.outboxRepo, events.TypeOrderCreated, events.NewOrderCreated(order)); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// mergeItems validates the quantities of the order lines and merges the lines of the
+// same product into one, keeping the order in which products first appear.
+func mergeItems(items []dto.CreateOrderItemInput) ([]dto.CreateOrderItemInput, error) {
+	merged := make([]dto.CreateOrderItemInput, 0, len(items))
+	positions := make(map[int64]int)
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return nil, domain.NewFieldError(domain.ErrInvalidQuantity, fmt.Sprintf("items[%d].quantity", i), "must be positive")
+		}
+		if pos, ok := positions[item.ProductID]; ok {
+			merged[pos].Quantity += item.Quantity
+			continue
+		}
+		positions[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged, nil
+}
+
+// missingProducts returns an UnknownProductsError listing the requested IDs that
+// are not among the products found.
+func missingProducts(productIDs []int64, found []domain.Product) error {
+	exists := make(map[int64]bool, len(found))
+	for _, p := range found {
+		exists[p.ID] = true
+	}
+
+	var missing []int64
+	for _, id := range productIDs {
+		if !exists[id] {
+			missing = append(missing, id)
+		}
+	}
+	return &UnknownProductsError{ProductIDs: missing}
+}
+
+// lookupRate returns the rate converting from one currency to another, looking up
+// each source currency at most once through the rates cache.
+func lookupRate(ctx context.Context, provider RateProvider, from, to string, rates map[string]domain.Rate) (domain.Rate, error) {
+	if from == to {
+		return domain.IdentityRate, nil
+	}
+	if rate, ok := rates[from]; ok {
+		return rate, nil
+	}
 
-			if err := p.DecreaseStock(itemInput.Quantity); err != nil {
+	exchangeRate, err := provider.FindRate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if exchangeRate == nil {
+		return 0, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, from, to)
+	}
+
+	rates[from] = exchangeRate.Rate
+	return exchangeRate.Rate, nil
+}
+
+// hashRequest returns a fingerprint of the order input used to detect reused idempotency keys.
+func hashRequest(input dto.CreateOrderInput) (string, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// TransitionOrder moves an order to the given status if the domain state machine allows it,
+// and records an "orders.<status>" event in the same transaction. Orders normally move
+// on through their payments and shipments; moving them by hand is only allowed where
+// those records agree: an order is only paid if its payment was captured, shipped if
+// all of its items are in shipments, and completed if all of them were delivered.
+func (uc *OrderUseCase) TransitionOrder(ctx context.Context, id int64, status domain.OrderStatus) (*domain.Order, error) {
+	if err := authorize(ctx, domain.ScopeTransitionOrders); err != nil {
+		return nil, err
+	}
+
+	// Cancellation also has to give the reserved stock back.
+	if status == domain.StatusCancelled {
+		return uc.CancelOrder(ctx, id)
+	}
+
+	var order *domain.Order
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Lock the order, so concurrent transitions see each other's status instead of
+		// all moving on from the same one.
+		var err error
+		order, err = uc.orderRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return ErrOrderNotFound
+		}
+
+		if order.Status.CanTransitionTo(status) {
+			if err := uc.checkTransition(txCtx, order, status); err != nil {
 				return err
 			}
+		}
+		return uc.changeStatus(txCtx, order, status)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			orderItems = append(orderItems, domain.OrderItem{
-				Product:      p,
-				Quantity:     itemInput.Quantity,
-				PriceAtOrder: p.Price,
-			})
+	return order, nil
+}
 
-			productToUpdate := p
-			productsToUpdate = append(productsToUpdate, &productToUpdate)
+// checkTransition reports an error if the payments or shipments of an order do not
+// back the status it is moved to by hand. It must be called within a transaction that
+// locked the order.
+func (uc *OrderUseCase) checkTransition(txCtx context.Context, order *domain.Order, status domain.OrderStatus) error {
+	switch status {
+	case domain.StatusPaid:
+		captured, err := uc.hasCapturedPayment(txCtx, order.ID)
+		if err != nil {
+			return err
 		}
+		if !captured {
+			return ErrOrderNotCaptured
+		}
+	case domain.StatusShipped, domain.StatusCompleted:
+		shipments, err := uc.shipmentRepo.FindByOrderID(txCtx, order.ID)
+		if err != nil {
+			return err
+		}
+		if !domain.FullyShipped(order, domain.ShippedQuantities(shipments)) {
+			return ErrOrderNotFullyShipped
+		}
+		if status == domain.StatusCompleted && !domain.AllDelivered(shipments) {
+			return ErrOrderNotDelivered
+		}
+	}
+	return nil
+}
+
+// changeStatus moves an order to the given status, persists it and records the event
+// of the new status. It must be called within a transaction that locked the order
+// with FindByIDForUpdate.
+func (uc *OrderUseCase) changeStatus(txCtx context.Context, order *domain.Order, status domain.OrderStatus) error {
+	if err := order.ChangeStatus(status); err != nil {
+		return err
+	}
+
+	if err := uc.orderRepo.UpdateStatus(txCtx, order); err != nil {
+		return err
+	}
+
+	return enqueueEvent(txCtx, uc.outboxRepo, statusEventTypes[order.Status], events.NewOrderStatusChanged(order))
+}
+
+// CancelOrder cancels a pending or paid order, restores the stock of every item
+// and records an "orders.cancelled" event, all in the same transaction.
+func (uc *OrderUseCase) CancelOrder(ctx context.Context, id int64) (*domain.Order, error) {
+	if err := authorize(ctx, domain.ScopeTransitionOrders); err != nil {
+		return nil, err
+	}
 
-		// Create the main Order domain object.
-		createdOrder, err = domain.NewOrder(input.UserID, orderItems)
+	var order *domain.Order
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Lock the order, so that of concurrent cancellations only the first one
+		// restores the stock and the others see the order cancelled.
+		var err error
+		order, err = uc.orderRepo.FindByIDForUpdate(txCtx, id)
 		if err != nil {
 			return err
 		}
+		if order == nil {
+			return ErrOrderNotFound
+		}
+		if err := uc.checkCancellable(txCtx, order); err != nil {
+			return err
+		}
 
-		// Persist the order and its items to the database.
-		if err := uc.orderRepo.Save(txCtx, createdOrder); err != nil {
+		if err := order.ChangeStatus(domain.StatusCancelled); err != nil {
+			return err
+		}
+
+		if err := uc.orderRepo.UpdateStatus(txCtx, order); err != nil {
+			return err
+		}
+
+		// Lock the current product rows so the restored stock is computed from
+		// the latest quantity rather than the one read with the order.
+		productIDs := make([]int64, len(order.OrderItems))
+		for i, item := range order.OrderItems {
+			productIDs[i] = item.Product.ID
+		}
+		products, err := uc.productRepo.FindManyByIDsForUpdate(txCtx, productIDs)
+		if err != nil {
 			return err
 		}
 
-		// Persist the updated product stock for all affected products.
-		for _, p := range productsToUpdate {
-			if err := uc.productRepo.Update(txCtx, p); err != nil {
+		productsByID := make(map[int64]*domain.Product, len(products))
+		for i := range products {
+			productsByID[products[i].ID] = &products[i]
+		}
+
+		// Return the ordered quantities to stock.
+		for _, item := range order.OrderItems {
+			p, ok := productsByID[item.Product.ID]
+			if !ok {
+				return fmt.Errorf("product %d of order %d not found", item.Product.ID, order.ID)
+			}
+			if err := p.IncreaseStock(item.Quantity); err != nil {
 				return err
 			}
 		}
 
-		return nil
+		// Persist the restored stock for all affected products.
+		for i := range products {
+			if err := uc.productRepo.Update(txCtx, &products[i]); err != nil {
+				return err
+			}
+		}
+
+		return enqueueEvent(txCtx, uc.outboxRepo, events.TypeOrderCancelled, events.NewOrderStatusChanged(order))
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create the message payload
-	eventPayload, err := json.Marshal(map[string]interface{}{
-		"order_id": createdOrder.ID,
-		"user_id":  createdOrder.UserID,
-	})
+	return order, nil
+}
+
+// checkCancellable reports an error if a paid order may not be cancelled although its
+// status allows it: cancelling restocks every ordered item, so none of them may have
+// left in a shipment or be returned, and it would not refund a captured payment.
+// Refunds go through returns instead. Pending orders have nothing to check. It must be called within a
+// transaction that locked the order.
+func (uc *OrderUseCase) checkCancellable(txCtx context.Context, order *domain.Order) error {
+	if order.Status != domain.StatusPaid {
+		return nil
+	}
+
+	shipments, err := uc.shipmentRepo.FindByOrderID(txCtx, order.ID)
 	if err != nil {
-		log.Printf("ERROR: failed to marshal event payload for order %d: %v", createdOrder.ID, err)
-	} else {
-		// Publish the event.
-		err = uc.broker.Publish(ctx, "orders.created", eventPayload)
-		if err != nil {
-			log.Printf("ERROR: failed to publish order.created event for order %d: %v", createdOrder.ID, err)
+		return err
+	}
+	if len(shipments) > 0 {
+		return ErrOrderHasShipments
+	}
+
+	returns, err := uc.returnRepo.FindByOrderID(txCtx, order.ID)
+	if err != nil {
+		return err
+	}
+	if len(domain.ReturnedQuantities(returns)) > 0 {
+		return ErrOrderHasReturns
+	}
+
+	captured, err := uc.hasCapturedPayment(txCtx, order.ID)
+	if err != nil {
+		return err
+	}
+	if captured {
+		return ErrOrderPaymentCaptured
+	}
+	return nil
+}
+
+// hasCapturedPayment reports whether an order has a captured payment.
+func (uc *OrderUseCase) hasCapturedPayment(ctx context.Context, orderID int64) (bool, error) {
+	payments, err := uc.paymentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	for _, payment := range payments {
+		if payment.Status == domain.PaymentCaptured {
+			return true, nil
 		}
 	}
+	return false, nil
+}
 
-	return createdOrder, nil
+// enqueueEvent wraps the payload in an event envelope and writes it to the outbox,
+// addressed to the queue named after the event type.
+// It must be called within the transaction that made the change, so the event
+// is stored if and only if the change is committed.
+func enqueueEvent(txCtx context.Context, outboxRepo OutboxRepository, eventType events.Type, payload interface{}) error {
+	env, err := events.New(txCtx, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	return outboxRepo.Save(txCtx, domain.NewOutboxMessage(string(eventType), body))
+}
+
+// GetOrderByID retrieves a single order with its items.
+// Customers may only retrieve their own orders.
+func (uc *OrderUseCase) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	order, err := uc.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := authorizeOwner(ctx, order.UserID, domain.ScopeReadAnyOrder); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ListOrdersByUser handles listing a user's orders with pagination.
+// Customers may only list their own orders.
+func (uc *OrderUseCase) ListOrdersByUser(ctx context.Context, userID int64, page, pageSize int) ([]domain.Order, error) {
+	if err := authorizeOwner(ctx, userID, domain.ScopeReadAnyOrder); err != nil {
+		return nil, err
+	}
+	if userID <= 0 {
+		return nil, domain.NewFieldError(domain.ErrInvalidInput, "user_id", "must be positive")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 { // Limit page size to a max of 100.
+		pageSize = 10
+	}
+
+	// Calculate offset for the database query.
+	offset := (page - 1) * pageSize
+
+	orders, err := uc.orderRepo.FindByUserID(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
 }
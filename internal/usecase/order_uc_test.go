@@ -2,22 +2,68 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/events"
 	"github.com/elokanugrah/go-order-system/internal/usecase"
 	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// outboxMessageFor matches an outbox message destined for the given queue
+// that carries a valid event envelope of the same type.
+func outboxMessageFor(queueName string) interface{} {
+	return mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+		env, err := events.Decode(msg.Payload)
+		return err == nil && msg.QueueName == queueName && string(env.Type) == queueName
+	})
+}
+
+// idr parses a decimal amount in the default currency, failing loudly on bad test data.
+func idr(amount string) domain.Money {
+	m, err := domain.ParseMoney(amount, domain.DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// asUser returns a context authenticated as the user with the given roles.
+func asUser(userID int64, roles ...domain.Role) context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: userID, Roles: roles})
+}
+
+// zeroTax returns a tax calculator that knows every category and rates every product at zero,
+// for tests that are not about tax.
+func zeroTax() *mocks.TaxCalculator {
+	calculator := new(mocks.TaxCalculator)
+	calculator.On("TaxRate", mock.Anything, mock.Anything, mock.Anything).Return(domain.TaxRate(0), nil).Maybe()
+	calculator.On("KnowsCategory", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	return calculator
+}
+
+// knownUsers returns a user use case that finds every user and has no saved addresses,
+// for tests that are not about users.
+func knownUsers() *usecase.UserUseCase {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(func(_ context.Context, id int64) *domain.User {
+		return &domain.User{ID: id, Email: "user@example.com", Name: "User"}
+	}, nil).Maybe()
+	return usecase.NewUserUseCase(userRepo, new(mocks.AddressRepository), new(mocks.OrderRepository))
+}
+
 func TestOrderUseCase_CreateOrder(t *testing.T) {
 	var mockProductRepo *mocks.ProductRepository
 	var mockOrderRepo *mocks.OrderRepository
 	var mockTxManager *mocks.TransactionManager
-	var mockMessageBroker *mocks.MessageBroker
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockRateProvider *mocks.RateProvider
 	var orderUseCase *usecase.OrderUseCase
 
 	// setup is a helper function to initialize components for each test.
@@ -25,9 +71,10 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		mockProductRepo = new(mocks.ProductRepository)
 		mockOrderRepo = new(mocks.OrderRepository)
 		mockTxManager = new(mocks.TransactionManager)
-		mockMessageBroker = new(mocks.MessageBroker)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockRateProvider = new(mocks.RateProvider)
 
-		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockMessageBroker)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), mockRateProvider, usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
 	}
 
 	t.Run("should create order successfully when all conditions are met", func(t *testing.T) {
@@ -42,8 +89,8 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		}
 
 		mockProducts := []domain.Product{
-			{ID: 1, Name: "Product A", Price: 10000, Quantity: 10},
-			{ID: 2, Name: "Product B", Price: 5000, Quantity: 5},
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+			{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
 		}
 
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
@@ -53,23 +100,126 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1, 2}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return(mockProducts, nil).Once()
 		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
 		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
 
-		mockMessageBroker.On("Publish", mock.Anything, "orders.created", mock.AnythingOfType("[]uint8")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, createdOrder)
-		assert.Equal(t, float64(25000), createdOrder.TotalAmount)
+		assert.Equal(t, idr("25000"), createdOrder.TotalAmount)
 		assert.Equal(t, domain.StatusPending, createdOrder.Status)
 
 		mockProductRepo.AssertExpectations(t)
 		mockOrderRepo.AssertExpectations(t)
 		mockTxManager.AssertExpectations(t)
-		mockMessageBroker.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should record an orders.created event with items and totals", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{
+			UserID: 123,
+			Items:  []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 2}},
+		}
+		mockProducts := []domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+
+		var saved *domain.OutboxMessage
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).
+			Run(func(args mock.Arguments) { saved = args.Get(1).(*domain.OutboxMessage) }).
+			Return(nil).Once()
+
+		_, err := orderUseCase.CreateOrder(events.WithCorrelationID(asUser(123, domain.RoleCustomer), "req-1"), input)
+
+		assert.NoError(t, err)
+		env, err := events.Decode(saved.Payload)
+		assert.NoError(t, err)
+		assert.Equal(t, "req-1", env.CorrelationID)
+
+		var payload events.OrderCreated
+		assert.NoError(t, env.DecodePayload(&payload))
+		assert.Equal(t, int64(123), payload.UserID)
+		assert.Equal(t, idr("20000"), payload.TotalAmount)
+		assert.Equal(t, domain.StatusPending, payload.Status)
+		assert.Equal(t, []events.OrderItem{{ProductID: 1, ProductName: "Product A", Quantity: 2, PriceAtOrder: idr("10000"), Tax: idr("0")}}, payload.Items)
+	})
+
+	t.Run("should convert prices into the order currency and snapshot the rate", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{
+			UserID:   123,
+			Currency: "USD",
+			Items: []dto.CreateOrderItemInput{
+				{ProductID: 1, Quantity: 2},
+				{ProductID: 2, Quantity: 1},
+			},
+		}
+		mockProducts := []domain.Product{
+			{ID: 1, Name: "Product A", Price: idr("162500"), Quantity: 10},
+			{ID: 2, Name: "Product B", Price: domain.NewMoney(499, "USD"), Quantity: 5},
+		}
+		rate, err := domain.ParseRate("0.00006154")
+		assert.NoError(t, err)
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return(mockProducts, nil).Once()
+		mockRateProvider.On("FindRate", mock.Anything, "IDR", "USD").
+			Return(&domain.ExchangeRate{From: "IDR", To: "USD", Rate: rate}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.NoError(t, err)
+		// 162500 IDR * 0.00006154 = 10.00025 USD, rounded to 10.00.
+		assert.Equal(t, domain.NewMoney(1000, "USD"), createdOrder.OrderItems[0].PriceAtOrder)
+		assert.Equal(t, idr("162500"), createdOrder.OrderItems[0].BasePrice)
+		assert.Equal(t, rate, createdOrder.OrderItems[0].ExchangeRate)
+		assert.Equal(t, domain.NewMoney(499, "USD"), createdOrder.OrderItems[1].PriceAtOrder)
+		assert.Equal(t, domain.IdentityRate, createdOrder.OrderItems[1].ExchangeRate)
+		assert.Equal(t, domain.NewMoney(2499, "USD"), createdOrder.TotalAmount)
+		mockRateProvider.AssertExpectations(t)
+	})
+
+	t.Run("should return error when no exchange rate is known", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{
+			UserID:   123,
+			Currency: "USD",
+			Items:    []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 1}},
+		}
+		mockProducts := []domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockRateProvider.On("FindRate", mock.Anything, "IDR", "USD").Return(nil, nil).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.ErrorIs(t, err, usecase.ErrExchangeRateNotFound)
+		assert.Nil(t, createdOrder)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 	})
 
 	t.Run("should return error when item quantity is not positive", func(t *testing.T) {
@@ -87,17 +237,17 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
 			Return(errors.New("item quantity must be positive")).Once() // Simulate error from within transaction
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "item quantity must be positive")
 		assert.Nil(t, createdOrder)
 
 		// Assert that no repository or message broker calls were made inside the successful part of the transaction
-		mockProductRepo.AssertNotCalled(t, "FindManyByIDs", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "FindManyByIDsForUpdate", mock.Anything, mock.Anything)
 		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t) // Ensure the On call was met
 	})
@@ -106,7 +256,7 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		setup()
 
 		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 20}}}
-		mockProducts := []domain.Product{{ID: 1, Name: "Product A", Price: 10000, Quantity: 10}}
+		mockProducts := []domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}
 
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
 			Return(domain.ErrInsufficientStock). // Directly return the expected error
@@ -116,9 +266,9 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
@@ -126,11 +276,54 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 
 		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t) // Ensure the On call was met
 	})
 
+	t.Run("should report every item that is short of stock", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{
+			{ProductID: 1, Quantity: 20},
+			{ProductID: 2, Quantity: 1},
+			{ProductID: 3, Quantity: 4},
+		}}
+		mockProducts := []domain.Product{
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+			{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+			{ID: 3, Name: "Product C", Price: idr("2000"), Quantity: 0},
+		}
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2, 3}).Return(mockProducts, nil).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.Nil(t, createdOrder)
+		var stockErr *domain.InsufficientStockError
+		require.ErrorAs(t, err, &stockErr)
+		assert.Equal(t, []domain.StockShortage{
+			{ProductID: 1, Requested: 20, Available: 10},
+			{ProductID: 3, Requested: 4, Available: 0},
+		}, stockErr.Shortages)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should forbid customers to order for other users", func(t *testing.T) {
+		setup()
+		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 1}}}
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(456, domain.RoleCustomer), input)
+
+		assert.ErrorIs(t, err, usecase.ErrForbidden)
+		assert.Nil(t, createdOrder)
+		mockTxManager.AssertNotCalled(t, "WithTransaction", mock.Anything, mock.Anything)
+	})
+
 	t.Run("should return error when product is not found", func(t *testing.T) {
 		setup()
 
@@ -138,25 +331,112 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		mockProducts := []domain.Product{}
 
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
-			Return(errors.New("one or more products not found")). // Directly return the expected error
+			Return(usecase.ErrUnknownProduct). // Directly return the expected error
 			Run(func(args mock.Arguments) {
 				callback := args.Get(1).(func(ctx context.Context) error)
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{99}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{99}).Return(mockProducts, nil).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Nil(t, createdOrder)
+		assert.ErrorIs(t, err, usecase.ErrUnknownProduct)
 		assert.Contains(t, err.Error(), "one or more products not found")
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t)
 	})
 
-	t.Run("should return error if productRepo.FindManyByIDs fails", func(t *testing.T) {
+	t.Run("should report exactly which products are not found", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{
+			{ProductID: 7, Quantity: 1},
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 3, Quantity: 1},
+		}}
+		mockProducts := []domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{7, 1, 3}).Return(mockProducts, nil).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.Nil(t, createdOrder)
+		assert.ErrorIs(t, err, usecase.ErrUnknownProduct)
+		var unknownErr *usecase.UnknownProductsError
+		require.ErrorAs(t, err, &unknownErr)
+		assert.Equal(t, []int64{7, 3}, unknownErr.ProductIDs)
+		assert.EqualError(t, err, "one or more products not found: 7, 3")
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should merge lines of the same product", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, Quantity: 1},
+			{ProductID: 1, Quantity: 3},
+		}}
+		mockProducts := []domain.Product{
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+			{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+		}
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == 1 && p.Quantity == 5
+		})).Return(nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == 2 && p.Quantity == 4
+		})).Return(nil).Once()
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		require.NoError(t, err)
+		require.Len(t, createdOrder.OrderItems, 2)
+		assert.Equal(t, int64(1), createdOrder.OrderItems[0].Product.ID)
+		assert.Equal(t, 5, createdOrder.OrderItems[0].Quantity)
+		assert.Equal(t, idr("55000"), createdOrder.TotalAmount)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a non-positive quantity on a duplicated line", func(t *testing.T) {
+		setup()
+
+		input := dto.CreateOrderInput{UserID: 123, Items: []dto.CreateOrderItemInput{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 1, Quantity: -1},
+		}}
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.Nil(t, createdOrder)
+		assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+		var fieldErr *domain.FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "items[1].quantity", fieldErr.Field)
+		mockProductRepo.AssertNotCalled(t, "FindManyByIDsForUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if productRepo.FindManyByIDsForUpdate fails", func(t *testing.T) {
 		setup()
 
 		input := dto.CreateOrderInput{
@@ -173,14 +453,14 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1}).Return(nil, expectedErr).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(nil, expectedErr).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, createdOrder)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t)
 	})
@@ -194,7 +474,7 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		}
 
 		mockProducts := []domain.Product{
-			{ID: 1, Name: "Product A", Price: 10000, Quantity: 10},
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
 		}
 		expectedErr := errors.New("save order failed")
 
@@ -205,16 +485,16 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
 		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
 		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(expectedErr).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, createdOrder)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t)
 	})
@@ -228,7 +508,7 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		}
 
 		mockProducts := []domain.Product{
-			{ID: 1, Name: "Product A", Price: 10000, Quantity: 10},
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
 		}
 		expectedErr := errors.New("update product failed")
 
@@ -239,16 +519,16 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				callback(context.Background())
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
 		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
 		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(expectedErr).Once()
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, createdOrder)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t)
 	})
@@ -266,21 +546,21 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
 			Return(expectedErr).Once() // Transaction manager itself fails
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, createdOrder)
 
-		mockProductRepo.AssertNotCalled(t, "FindManyByIDs", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "FindManyByIDsForUpdate", mock.Anything, mock.Anything)
 		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t)
 	})
 
-	t.Run("should handle broker.Publish returning an error but still return created order", func(t *testing.T) {
+	t.Run("should return error if outboxRepo.Save fails", func(t *testing.T) {
 		setup()
 
 		input := dto.CreateOrderInput{
@@ -291,33 +571,31 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 		}
 
 		mockProducts := []domain.Product{
-			{ID: 1, Name: "Product A", Price: 10000, Quantity: 10},
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
 		}
+		expectedErr := errors.New("save outbox message failed")
 
+		// The event is part of the transaction, so failing to store it must fail the order.
 		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
-			Return(nil).
-			Run(func(args mock.Arguments) {
-				callback := args.Get(1).(func(ctx context.Context) error)
-				callback(context.Background())
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
 			}).Once()
 
-		mockProductRepo.On("FindManyByIDs", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return(mockProducts, nil).Once()
 		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
 		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(expectedErr).Once()
 
-		mockMessageBroker.On("Publish", mock.Anything, "orders.created", mock.AnythingOfType("[]uint8")).Return(errors.New("broker publish error")).Once()
-
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
-		assert.NoError(t, err)
-		assert.NotNil(t, createdOrder)
-		assert.Equal(t, float64(20000), createdOrder.TotalAmount)
-		assert.Equal(t, domain.StatusPending, createdOrder.Status)
+		assert.Error(t, err)
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, createdOrder)
 
 		mockProductRepo.AssertExpectations(t)
 		mockOrderRepo.AssertExpectations(t)
 		mockTxManager.AssertExpectations(t)
-		mockMessageBroker.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
 	})
 
 	t.Run("should return error when input items is empty", func(t *testing.T) {
@@ -328,19 +606,721 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 			Items:  []dto.CreateOrderItemInput{}, // Empty items
 		}
 
-		createdOrder, err := orderUseCase.CreateOrder(context.Background(), input)
+		createdOrder, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
 
 		assert.Error(t, err)
-		assert.Equal(t, "order must contain at least one item", err.Error())
+		assert.ErrorIs(t, err, domain.ErrEmptyOrder)
 		assert.Nil(t, createdOrder)
 
 		// Assert that no repository or message broker calls were made inside the transaction's successful path
-		mockProductRepo.AssertNotCalled(t, "FindManyByIDs", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "FindManyByIDsForUpdate", mock.Anything, mock.Anything)
 		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
-		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 		mockTxManager.AssertNotCalled(t, "WithTransaction", mock.Anything, mock.Anything)
 
 		mockTxManager.AssertExpectations(t) // Ensure the On call was met
 	})
 }
+
+func TestOrderUseCase_CreateOrderWithCoupon(t *testing.T) {
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockCouponRepo *mocks.CouponRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	// setup is a helper function to initialize components for each test.
+	setup := func() {
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockCouponRepo = new(mocks.CouponRepository)
+
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(mockCouponRepo), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return([]domain.Product{
+			{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10},
+			{ID: 2, Name: "Product B", Price: idr("5000"), Quantity: 5},
+		}, nil).Once()
+	}
+
+	input := dto.CreateOrderInput{
+		UserID:     123,
+		CouponCode: "save10",
+		Items: []dto.CreateOrderItemInput{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("should apply the discount and redeem the coupon in the same transaction", func(t *testing.T) {
+		setup()
+		coupon := &domain.Coupon{ID: 7, Code: "SAVE10", Type: domain.CouponTypePercentage, Percent: 10, MaxUses: 100, UsedCount: 4}
+
+		mockCouponRepo.On("FindByCodeForUpdate", mock.Anything, "SAVE10").Return(coupon, nil).Once()
+		mockCouponRepo.On("CountRedemptionsByUser", mock.Anything, int64(7), int64(123)).Return(0, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Order).ID = 42 }).
+			Return(nil).Once()
+		mockCouponRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.Coupon) bool {
+			return c.UsedCount == 5
+		})).Return(nil).Once()
+		mockCouponRepo.On("SaveRedemption", mock.Anything, mock.MatchedBy(func(r *domain.CouponRedemption) bool {
+			return r.CouponID == 7 && r.UserID == 123 && r.OrderID == 42 && r.Discount == idr("2500")
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, idr("25000"), order.Subtotal)
+		assert.Equal(t, idr("2500"), order.Discount)
+		assert.Equal(t, idr("22500"), order.TotalAmount)
+		assert.Equal(t, "SAVE10", order.CouponCode)
+		mockCouponRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a coupon the user has used up", func(t *testing.T) {
+		setup()
+		coupon := &domain.Coupon{ID: 7, Code: "SAVE10", Type: domain.CouponTypePercentage, Percent: 10, MaxUsesPerUser: 1}
+
+		mockCouponRepo.On("FindByCodeForUpdate", mock.Anything, "SAVE10").Return(coupon, nil).Once()
+		mockCouponRepo.On("CountRedemptionsByUser", mock.Anything, int64(7), int64(123)).Return(1, nil).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.ErrorIs(t, err, domain.ErrCouponUsageLimitReached)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		mockCouponRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found error for an unknown coupon", func(t *testing.T) {
+		setup()
+		mockCouponRepo.On("FindByCodeForUpdate", mock.Anything, "SAVE10").Return(nil, nil).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.ErrorIs(t, err, usecase.ErrCouponNotFound)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderUseCase_CreateOrderWithTax(t *testing.T) {
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockTaxCalculator *mocks.TaxCalculator
+	var orderUseCase *usecase.OrderUseCase
+
+	// setup is a helper function to initialize components for each test.
+	setup := func() {
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockTaxCalculator = new(mocks.TaxCalculator)
+
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), mockTaxCalculator, knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return([]domain.Product{
+			{ID: 1, Name: "Kopi", Price: idr("10000"), TaxCategory: domain.TaxCategoryStandard, Quantity: 10},
+			{ID: 2, Name: "Beras", Price: idr("5000"), TaxCategory: domain.TaxCategoryExempt, Quantity: 5},
+		}, nil).Once()
+	}
+
+	input := dto.CreateOrderInput{
+		UserID: 123,
+		Items: []dto.CreateOrderItemInput{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("should tax each item at the rate of its category in the default region", func(t *testing.T) {
+		setup()
+		ppn, err := domain.ParseTaxRate("0.11")
+		require.NoError(t, err)
+
+		mockTaxCalculator.On("TaxRate", mock.Anything, domain.TaxCategoryStandard, domain.DefaultTaxRegion).Return(ppn, nil).Once()
+		mockTaxCalculator.On("TaxRate", mock.Anything, domain.TaxCategoryExempt, domain.DefaultTaxRegion).Return(domain.TaxRate(0), nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Times(2)
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ppn, order.OrderItems[0].TaxRate)
+		assert.Equal(t, idr("2200"), order.OrderItems[0].Tax)
+		assert.Equal(t, idr("0"), order.OrderItems[1].Tax)
+		assert.Equal(t, idr("25000"), order.Subtotal)
+		assert.Equal(t, idr("2200"), order.Tax)
+		assert.Equal(t, idr("27200"), order.TotalAmount)
+		assert.Equal(t, domain.DefaultTaxRegion, order.TaxRegion)
+		mockTaxCalculator.AssertExpectations(t)
+	})
+
+	t.Run("should reject an order with a product that has no tax rule in the region", func(t *testing.T) {
+		setup()
+		input := input
+		input.Region = "MY"
+
+		mockTaxCalculator.On("TaxRate", mock.Anything, domain.TaxCategoryStandard, "MY").
+			Return(domain.TaxRate(0), domain.ErrTaxRuleNotFound).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), input)
+
+		assert.ErrorIs(t, err, domain.ErrTaxRuleNotFound)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderUseCase_CreateOrderWithIdempotencyKey(t *testing.T) {
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockIdempotencyRepo *mocks.IdempotencyRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	input := dto.CreateOrderInput{
+		UserID: 123,
+		Items:  []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 2}},
+	}
+
+	setup := func() {
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockIdempotencyRepo = new(mocks.IdempotencyRepository)
+
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, mockIdempotencyRepo, new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+	}
+
+	// storedKey runs a first request through the use case and returns the key it stored.
+	storedKey := func(t *testing.T) *domain.IdempotencyKey {
+		var stored *domain.IdempotencyKey
+
+		setup()
+		mockIdempotencyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyKey")).Return(true, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).
+			Return([]domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+		mockIdempotencyRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.IdempotencyKey")).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(*domain.IdempotencyKey)
+			}).Return(nil).Once()
+
+		_, _, err := orderUseCase.CreateOrderWithIdempotencyKey(asUser(123, domain.RoleCustomer), "key-1", input)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		return stored
+	}
+
+	t.Run("should create order and store the response for a new key", func(t *testing.T) {
+		stored := storedKey(t)
+
+		assert.Equal(t, "key-1", stored.Key)
+		assert.NotEmpty(t, stored.RequestHash)
+		assert.NotNil(t, stored.CompletedAt)
+
+		var order domain.Order
+		require.NoError(t, json.Unmarshal(stored.Response, &order))
+		assert.Equal(t, idr("20000"), order.TotalAmount)
+
+		mockProductRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+		mockIdempotencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("should replay the stored order without creating a new one", func(t *testing.T) {
+		stored := storedKey(t)
+
+		setup()
+		mockIdempotencyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyKey")).Return(false, nil).Once()
+		mockIdempotencyRepo.On("FindByKey", mock.Anything, "key-1").Return(stored, nil).Once()
+
+		order, replayed, err := orderUseCase.CreateOrderWithIdempotencyKey(asUser(123, domain.RoleCustomer), "key-1", input)
+
+		assert.NoError(t, err)
+		assert.True(t, replayed)
+		assert.Equal(t, idr("20000"), order.TotalAmount)
+		mockProductRepo.AssertNotCalled(t, "FindManyByIDsForUpdate", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		mockIdempotencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a key reused with a different request", func(t *testing.T) {
+		stored := storedKey(t)
+
+		setup()
+		mockIdempotencyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyKey")).Return(false, nil).Once()
+		mockIdempotencyRepo.On("FindByKey", mock.Anything, "key-1").Return(stored, nil).Once()
+
+		other := dto.CreateOrderInput{
+			UserID: 123,
+			Items:  []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 3}},
+		}
+		order, replayed, err := orderUseCase.CreateOrderWithIdempotencyKey(asUser(123, domain.RoleCustomer), "key-1", other)
+
+		assert.ErrorIs(t, err, usecase.ErrIdempotencyKeyReused)
+		assert.False(t, replayed)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderUseCase_GetOrderByID(t *testing.T) {
+	var mockOrderRepo *mocks.OrderRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	setup := func() {
+		mockOrderRepo = new(mocks.OrderRepository)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.TransactionManager), new(mocks.OutboxRepository), new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+	}
+
+	t.Run("should return order successfully when order is found", func(t *testing.T) {
+		setup()
+		expectedOrder := &domain.Order{ID: 1, UserID: 123, OrderItems: []domain.OrderItem{{ID: 1, OrderID: 1, Quantity: 2}}}
+		mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(expectedOrder, nil).Once()
+
+		order, err := orderUseCase.GetOrderByID(asUser(123, domain.RoleCustomer), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOrder, order)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found error when order does not exist", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByID", mock.Anything, int64(99)).Return(nil, nil).Once()
+
+		order, err := orderUseCase.GetOrderByID(asUser(123, domain.RoleCustomer), 99)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderNotFound)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("should forbid customers to read orders of other users", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Order{ID: 1, UserID: 123}, nil).Once()
+
+		order, err := orderUseCase.GetOrderByID(asUser(456, domain.RoleCustomer), 1)
+
+		assert.ErrorIs(t, err, usecase.ErrForbidden)
+		assert.Nil(t, order)
+	})
+
+	t.Run("should let staff read orders of any user", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Order{ID: 1, UserID: 123}, nil).Once()
+
+		order, err := orderUseCase.GetOrderByID(asUser(1, domain.RoleStaff), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(123), order.UserID)
+	})
+
+	t.Run("should require an authenticated caller", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Order{ID: 1, UserID: 123}, nil).Once()
+
+		_, err := orderUseCase.GetOrderByID(context.Background(), 1)
+
+		assert.ErrorIs(t, err, usecase.ErrUnauthenticated)
+	})
+}
+
+func TestOrderUseCase_ListOrdersByUser(t *testing.T) {
+	var mockOrderRepo *mocks.OrderRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	setup := func() {
+		mockOrderRepo = new(mocks.OrderRepository)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), new(mocks.TransactionManager), new(mocks.OutboxRepository), new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+	}
+
+	t.Run("should list orders with the computed offset", func(t *testing.T) {
+		setup()
+		expectedOrders := []domain.Order{{ID: 3, UserID: 123}, {ID: 4, UserID: 123}}
+		mockOrderRepo.On("FindByUserID", mock.Anything, int64(123), 20, 20).Return(expectedOrders, nil).Once()
+
+		orders, err := orderUseCase.ListOrdersByUser(asUser(123, domain.RoleCustomer), 123, 2, 20)
+
+		assert.NoError(t, err)
+		assert.Len(t, orders, 2)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("should fall back to default pagination on invalid values", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByUserID", mock.Anything, int64(123), 10, 0).Return([]domain.Order{}, nil).Once()
+
+		_, err := orderUseCase.ListOrdersByUser(asUser(123, domain.RoleCustomer), 123, 0, 500)
+
+		assert.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("should forbid customers to list orders of other users", func(t *testing.T) {
+		setup()
+
+		_, err := orderUseCase.ListOrdersByUser(asUser(456, domain.RoleCustomer), 123, 1, 10)
+
+		assert.ErrorIs(t, err, usecase.ErrForbidden)
+		mockOrderRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderUseCase_TransitionOrder(t *testing.T) {
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockPaymentRepo *mocks.PaymentRepository
+	var mockShipmentRepo *mocks.ShipmentRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	setup := func() {
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockPaymentRepo = new(mocks.PaymentRepository)
+		mockShipmentRepo = new(mocks.ShipmentRepository)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), mockPaymentRepo, mockShipmentRepo, new(mocks.ReturnRepository))
+
+		// Run the callback and propagate its error, like the real transaction manager.
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+	}
+
+	t.Run("should transition order and publish event", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{ID: 1, UserID: 123, Status: domain.StatusPending}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return([]domain.Payment{{ID: 5, OrderID: 1, Status: domain.PaymentCaptured}}, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Status == domain.StatusPaid
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.paid")).Return(nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusPaid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPaid, order.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not pay an order without a captured payment", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{ID: 1, UserID: 123, Status: domain.StatusPending}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return([]domain.Payment{{ID: 5, OrderID: 1, Status: domain.PaymentPending}}, nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusPaid)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderNotCaptured)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should ship an order whose items are all in shipments", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			UserID:     123,
+			Status:     domain.StatusPaid,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1}, Quantity: 2}},
+		}
+		shipments := []domain.Shipment{
+			{ID: 3, OrderID: 1, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}},
+			{ID: 4, OrderID: 1, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}},
+		}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(shipments, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Status == domain.StatusShipped
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.shipped")).Return(nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusShipped)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusShipped, order.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not ship an order with items that are not in a shipment", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			UserID:     123,
+			Status:     domain.StatusPaid,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1}, Quantity: 2}},
+		}
+		shipments := []domain.Shipment{{ID: 3, OrderID: 1, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}}}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(shipments, nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusShipped)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderNotFullyShipped)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not complete an order with shipments that were not delivered", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			UserID:     123,
+			Status:     domain.StatusShipped,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1}, Quantity: 2}},
+		}
+		shipments := []domain.Shipment{
+			{ID: 3, OrderID: 1, Status: domain.ShipmentDelivered, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}},
+			{ID: 4, OrderID: 1, Status: domain.ShipmentInTransit, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}},
+		}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(shipments, nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusCompleted)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderNotDelivered)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject an illegal transition", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{ID: 1, UserID: 123, Status: domain.StatusCancelled}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 1, domain.StatusPaid)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found error when order does not exist", func(t *testing.T) {
+		setup()
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(99)).Return(nil, nil).Once()
+
+		order, err := orderUseCase.TransitionOrder(asUser(1, domain.RoleStaff), 99, domain.StatusPaid)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderNotFound)
+		assert.Nil(t, order)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should forbid customers to transition orders", func(t *testing.T) {
+		setup()
+
+		order, err := orderUseCase.TransitionOrder(asUser(123, domain.RoleCustomer), 1, domain.StatusShipped)
+
+		assert.ErrorIs(t, err, usecase.ErrForbidden)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "FindByIDForUpdate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderUseCase_CancelOrder(t *testing.T) {
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockPaymentRepo *mocks.PaymentRepository
+	var mockShipmentRepo *mocks.ShipmentRepository
+	var mockReturnRepo *mocks.ReturnRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	setup := func() {
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockPaymentRepo = new(mocks.PaymentRepository)
+		mockShipmentRepo = new(mocks.ShipmentRepository)
+		mockReturnRepo = new(mocks.ReturnRepository)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), mockPaymentRepo, mockShipmentRepo, mockReturnRepo)
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+	}
+
+	t.Run("should cancel order and restore stock for every item", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:     1,
+			UserID: 123,
+			Status: domain.StatusPaid,
+			OrderItems: []domain.OrderItem{
+				{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2},
+				{Product: domain.Product{ID: 2, Quantity: 4}, Quantity: 1},
+			},
+		}
+		// Stock has changed since the order was read, the locked rows are authoritative.
+		lockedProducts := []domain.Product{{ID: 1, Quantity: 7}, {ID: 2, Quantity: 4}}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(nil, nil).Once()
+		// Rejected returns do not send anything back.
+		mockReturnRepo.On("FindByOrderID", mock.Anything, int64(1)).Return([]domain.Return{{ID: 7, OrderID: 1, Status: domain.ReturnRejected, Items: []domain.ReturnItem{{ProductID: 1, Quantity: 1}}}}, nil).Once()
+		mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return([]domain.Payment{{ID: 5, OrderID: 1, Status: domain.PaymentFailed}}, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1, 2}).Return(lockedProducts, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Status == domain.StatusCancelled
+		})).Return(nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == 1 && p.Quantity == 9
+		})).Return(nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == 2 && p.Quantity == 5
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.cancelled")).Return(nil).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusCancelled, order.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not cancel a paid order with a captured payment", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			Status:     domain.StatusPaid,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2}},
+		}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(nil, nil).Once()
+		mockReturnRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(nil, nil).Once()
+		mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return([]domain.Payment{{ID: 5, OrderID: 1, Status: domain.PaymentCaptured}}, nil).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderPaymentCaptured)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not cancel a paid order with shipments", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			Status:     domain.StatusPaid,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2}},
+		}
+		// One of the two units was shipped, the order is still paid.
+		shipments := []domain.Shipment{{ID: 3, OrderID: 1, Items: []domain.ShipmentItem{{ProductID: 1, Quantity: 1}}}}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(shipments, nil).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderHasShipments)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not cancel a paid order with returns", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			Status:     domain.StatusPaid,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2}},
+		}
+		// The returned unit was already restocked when the return was received.
+		returns := []domain.Return{{ID: 7, OrderID: 1, Status: domain.ReturnReceived, Items: []domain.ReturnItem{{ProductID: 1, Quantity: 1}}}}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(nil, nil).Once()
+		mockReturnRepo.On("FindByOrderID", mock.Anything, int64(1)).Return(returns, nil).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.ErrorIs(t, err, usecase.ErrOrderHasReturns)
+		assert.Nil(t, order)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not restore stock when order cannot be cancelled", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			Status:     domain.StatusShipped,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2}},
+		}
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+		assert.Nil(t, order)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail the transaction when restoring stock fails", func(t *testing.T) {
+		setup()
+		existingOrder := &domain.Order{
+			ID:         1,
+			Status:     domain.StatusPending,
+			OrderItems: []domain.OrderItem{{Product: domain.Product{ID: 1, Quantity: 8}, Quantity: 2}},
+		}
+		expectedErr := errors.New("update product failed")
+		mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(existingOrder, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Quantity: 8}}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(expectedErr).Once()
+
+		order, err := orderUseCase.CancelOrder(asUser(1, domain.RoleStaff), 1)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, order)
+		mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// outboxBaseBackoff is the delay before the first retry of a failed publish.
+	outboxBaseBackoff = time.Second
+	// outboxMaxBackoff caps the delay between retries.
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// OutboxRelay publishes pending outbox messages to the message broker.
+// A message is only marked as sent after the broker accepted it, which gives
+// at-least-once delivery: consumers must tolerate duplicates.
+type OutboxRelay struct {
+	outboxRepo OutboxRepository
+	txManager  TransactionManager
+	broker     MessageBroker
+	batchSize  int
+}
+
+func NewOutboxRelay(obr OutboxRepository, tm TransactionManager, mb MessageBroker, batchSize int) *OutboxRelay {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &OutboxRelay{
+		outboxRepo: obr,
+		txManager:  tm,
+		broker:     mb,
+		batchSize:  batchSize,
+	}
+}
+
+// Run relays pending messages every pollInterval until the context is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back, otherwise wait for the next tick.
+		relayed, err := r.RelayPending(ctx)
+		if err != nil {
+			log.Printf("ERROR: failed to relay outbox messages: %v", err)
+		}
+		if err == nil && relayed == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of due messages and returns how many were processed.
+// Messages that fail to publish are rescheduled with exponential backoff.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	var processed int
+
+	err := r.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		messages, err := r.outboxRepo.FindPending(txCtx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range messages {
+			msg := &messages[i]
+
+			if err := r.broker.Publish(ctx, msg.QueueName, msg.Payload); err != nil {
+				log.Printf("WARN: failed to publish outbox message %d to %s (attempt %d): %v", msg.ID, msg.QueueName, msg.Attempts+1, err)
+				msg.MarkFailed(err, time.Now().Add(outboxBackoff(msg.Attempts)))
+			} else {
+				msg.MarkSent()
+			}
+
+			if err := r.outboxRepo.Update(txCtx, msg); err != nil {
+				return err
+			}
+		}
+
+		processed = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return processed, nil
+}
+
+// outboxBackoff returns the delay before the next attempt after the given number of failed attempts.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 0; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
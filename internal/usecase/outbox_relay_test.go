@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxRelay_RelayPending(t *testing.T) {
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockMessageBroker *mocks.MessageBroker
+	var relay *usecase.OutboxRelay
+
+	setup := func() {
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockMessageBroker = new(mocks.MessageBroker)
+		relay = usecase.NewOutboxRelay(mockOutboxRepo, mockTxManager, mockMessageBroker, 10)
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Once()
+	}
+
+	t.Run("should publish pending messages and mark them as sent", func(t *testing.T) {
+		setup()
+		pending := []domain.OutboxMessage{
+			{ID: 1, QueueName: "orders.created", Payload: []byte(`{"order_id":1}`)},
+			{ID: 2, QueueName: "orders.paid", Payload: []byte(`{"order_id":2}`)},
+		}
+		mockOutboxRepo.On("FindPending", mock.Anything, 10).Return(pending, nil).Once()
+		mockMessageBroker.On("Publish", mock.Anything, "orders.created", []byte(`{"order_id":1}`)).Return(nil).Once()
+		mockMessageBroker.On("Publish", mock.Anything, "orders.paid", []byte(`{"order_id":2}`)).Return(nil).Once()
+		mockOutboxRepo.On("Update", mock.Anything, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+			return msg.SentAt != nil && msg.Attempts == 0
+		})).Return(nil).Twice()
+
+		relayed, err := relay.RelayPending(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, relayed)
+		mockOutboxRepo.AssertExpectations(t)
+		mockMessageBroker.AssertExpectations(t)
+	})
+
+	t.Run("should reschedule a message with backoff when publishing fails", func(t *testing.T) {
+		setup()
+		pending := []domain.OutboxMessage{
+			{ID: 1, QueueName: "orders.created", Payload: []byte(`{}`), Attempts: 2},
+		}
+		mockOutboxRepo.On("FindPending", mock.Anything, 10).Return(pending, nil).Once()
+		mockMessageBroker.On("Publish", mock.Anything, "orders.created", mock.Anything).Return(errors.New("broker unavailable")).Once()
+		mockOutboxRepo.On("Update", mock.Anything, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+			// Third failed attempt: the delay has doubled twice from the 1s base.
+			return msg.SentAt == nil &&
+				msg.Attempts == 3 &&
+				msg.LastError == "broker unavailable" &&
+				msg.NextAttemptAt.After(time.Now().Add(3*time.Second))
+		})).Return(nil).Once()
+
+		relayed, err := relay.RelayPending(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, relayed)
+		mockOutboxRepo.AssertExpectations(t)
+		mockMessageBroker.AssertExpectations(t)
+	})
+
+	t.Run("should return error when pending messages cannot be loaded", func(t *testing.T) {
+		setup()
+		expectedErr := errors.New("database error")
+		mockOutboxRepo.On("FindPending", mock.Anything, 10).Return(nil, expectedErr).Once()
+
+		relayed, err := relay.RelayPending(context.Background())
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, relayed)
+		mockMessageBroker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+var (
+	ErrPaymentNotFound   = domain.NewError(domain.KindNotFound, "payment_not_found", "payment not found")
+	ErrNoCapturedPayment = domain.NewError(domain.KindUnprocessable, "no_captured_payment", "order has no captured payment to refund")
+)
+
+type PaymentUseCase struct {
+	paymentRepo PaymentRepository
+	orderRepo   OrderRepository
+	txManager   TransactionManager
+	gateway     PaymentGateway
+	orders      *OrderUseCase
+}
+
+// Orders are moved to paid through OrderUseCase, so they record the same events as
+// any other status change.
+func NewPaymentUseCase(pr PaymentRepository, or OrderRepository, tm TransactionManager, gateway PaymentGateway, orders *OrderUseCase) *PaymentUseCase {
+	return &PaymentUseCase{
+		paymentRepo: pr,
+		orderRepo:   or,
+		txManager:   tm,
+		gateway:     gateway,
+		orders:      orders,
+	}
+}
+
+// CreatePayment charges the total amount of a pending order at the payment provider.
+// The order stays pending until the provider reports that the charge was authorized.
+// Customers may only pay for their own orders.
+func (uc *PaymentUseCase) CreatePayment(ctx context.Context, orderID int64) (*domain.Payment, error) {
+	order, err := uc.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := authorizeOwner(ctx, order.UserID, domain.ScopeActForAnyUser); err != nil {
+		return nil, err
+	}
+	if order.Status != domain.StatusPending {
+		return nil, domain.ErrOrderNotPayable
+	}
+
+	providerPaymentID, err := uc.gateway.CreateCharge(ctx, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create charge: %w", err)
+	}
+
+	payment := domain.NewPayment(order.ID, uc.gateway.Name(), providerPaymentID, order.TotalAmount)
+	if err := uc.paymentRepo.Save(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// HandlePaymentEvent applies an event reported by the payment provider. An authorized
+// payment is captured and moves its order to paid; if the order can no longer be paid,
+// e.g. because it was cancelled meanwhile, the payment is voided instead and never
+// captured. Providers may deliver an event more than once: events for failed payments
+// are ignored, and for captured or voided payments the capture or void is sent to the
+// provider again, in case it did not go through the first time.
+// It trusts its caller to have verified that the event comes from the provider.
+func (uc *PaymentUseCase) HandlePaymentEvent(ctx context.Context, event domain.PaymentEvent, providerPaymentID string) error {
+	if !event.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrUnsupportedPaymentEvent, event)
+	}
+
+	var payment *domain.Payment
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// The lock serializes deliveries of the same event.
+		var err error
+		payment, err = uc.paymentRepo.FindByProviderPaymentIDForUpdate(txCtx, uc.gateway.Name(), providerPaymentID)
+		if err != nil {
+			return err
+		}
+		if payment == nil {
+			return ErrPaymentNotFound
+		}
+		if payment.Status != domain.PaymentPending {
+			return nil
+		}
+
+		if event == domain.PaymentEventFailed {
+			return uc.failPayment(txCtx, payment)
+		}
+
+		// Lock the order, so it cannot be cancelled between checking its status
+		// and capturing the payment.
+		order, err := uc.orderRepo.FindByIDForUpdate(txCtx, payment.OrderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return fmt.Errorf("order %d of payment %d not found", payment.OrderID, payment.ID)
+		}
+		if !order.Status.CanTransitionTo(domain.StatusPaid) {
+			if err := payment.Void(); err != nil {
+				return err
+			}
+			return uc.paymentRepo.Update(txCtx, payment)
+		}
+
+		if err := payment.Capture(); err != nil {
+			return err
+		}
+		if err := uc.paymentRepo.Update(txCtx, payment); err != nil {
+			return err
+		}
+
+		return uc.orders.changeStatus(txCtx, order, domain.StatusPaid)
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.settle(ctx, payment)
+}
+
+func (uc *PaymentUseCase) failPayment(txCtx context.Context, payment *domain.Payment) error {
+	if err := payment.Fail(); err != nil {
+		return err
+	}
+	return uc.paymentRepo.Update(txCtx, payment)
+}
+
+// settle captures a captured payment at the provider, or releases the authorization
+// of a voided one. It is called after the status of the payment was committed, so a
+// rolled back transaction never leaves behind a capture that was not recorded, and
+// it uses the same idempotency key every time, so it may be repeated when it failed.
+func (uc *PaymentUseCase) settle(ctx context.Context, payment *domain.Payment) error {
+	switch payment.Status {
+	case domain.PaymentCaptured:
+		key := fmt.Sprintf("payment-%d-capture", payment.ID)
+		if err := uc.gateway.Capture(ctx, payment.ProviderPaymentID, payment.Amount, key); err != nil {
+			return fmt.Errorf("failed to capture payment %d: %w", payment.ID, err)
+		}
+	case domain.PaymentVoided:
+		key := fmt.Sprintf("payment-%d-void", payment.ID)
+		if err := uc.gateway.Void(ctx, payment.ProviderPaymentID, key); err != nil {
+			return fmt.Errorf("failed to void payment %d: %w", payment.ID, err)
+		}
+	}
+	return nil
+}
+
+// reserveRefund records that amount of the captured payment of an order is paid back,
+// at most what is left on the payment, and returns the amount. The payment is locked,
+// so concurrent refunds cannot exceed it together. It must be called within a
+// transaction; the refund is sent to the provider by sendRefund once it committed.
+func (uc *PaymentUseCase) reserveRefund(txCtx context.Context, orderID int64, amount domain.Money) (domain.Money, error) {
+	captured, err := uc.capturedPayment(txCtx, orderID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	payment, err := uc.paymentRepo.FindByProviderPaymentIDForUpdate(txCtx, captured.Provider, captured.ProviderPaymentID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if payment == nil {
+		return domain.Money{}, fmt.Errorf("payment %d of order %d not found", captured.ID, orderID)
+	}
+
+	refundable, err := payment.Refundable()
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if !refundable.IsPositive() {
+		return domain.Money{}, domain.ErrRefundExceedsPayment
+	}
+	if cmp, err := amount.Cmp(refundable); err != nil {
+		return domain.Money{}, err
+	} else if cmp > 0 {
+		amount = refundable
+	}
+	if err := payment.Refund(amount); err != nil {
+		return domain.Money{}, err
+	}
+
+	if err := uc.paymentRepo.Update(txCtx, payment); err != nil {
+		return domain.Money{}, err
+	}
+
+	return amount, nil
+}
+
+// sendRefund pays back amount of the captured payment of an order through the payment
+// provider and returns the ID of the refund. The provider refunds once per key, so it
+// may be repeated with the same key when it failed.
+func (uc *PaymentUseCase) sendRefund(ctx context.Context, orderID int64, amount domain.Money, key string) (string, error) {
+	payment, err := uc.capturedPayment(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+
+	refundID, err := uc.gateway.Refund(ctx, payment.ProviderPaymentID, amount, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to refund payment %d: %w", payment.ID, err)
+	}
+	return refundID, nil
+}
+
+// capturedPayment finds the captured payment of an order.
+func (uc *PaymentUseCase) capturedPayment(ctx context.Context, orderID int64) (*domain.Payment, error) {
+	payments, err := uc.paymentRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range payments {
+		if payments[i].Status == domain.PaymentCaptured {
+			return &payments[i], nil
+		}
+	}
+	return nil, ErrNoCapturedPayment
+}
@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentUseCase(t *testing.T) {
+	var mockPaymentRepo *mocks.PaymentRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockGateway *mocks.PaymentGateway
+	var paymentUseCase *usecase.PaymentUseCase
+
+	setup := func() {
+		mockPaymentRepo = new(mocks.PaymentRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockGateway = new(mocks.PaymentGateway)
+		mockGateway.On("Name").Return("fake").Maybe()
+
+		txManager := new(mocks.TransactionManager)
+		txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Maybe()
+
+		orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), txManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+		paymentUseCase = usecase.NewPaymentUseCase(mockPaymentRepo, mockOrderRepo, txManager, mockGateway, orderUseCase)
+	}
+
+	pendingOrder := func() *domain.Order {
+		return &domain.Order{ID: 10, UserID: 123, TotalAmount: idr("25000"), Status: domain.StatusPending}
+	}
+	pendingPayment := func() *domain.Payment {
+		return &domain.Payment{ID: 5, OrderID: 10, Provider: "fake", ProviderPaymentID: "ch_1", Amount: idr("25000"), Status: domain.PaymentPending}
+	}
+
+	t.Run("CreatePayment", func(t *testing.T) {
+		t.Run("should charge the total amount of the order", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(pendingOrder(), nil).Once()
+			mockGateway.On("CreateCharge", mock.Anything, int64(10), idr("25000")).Return("ch_1", nil).Once()
+			mockPaymentRepo.On("Save", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.OrderID == 10 && p.Provider == "fake" && p.ProviderPaymentID == "ch_1" && p.Status == domain.PaymentPending
+			})).Return(nil).Once()
+
+			payment, err := paymentUseCase.CreatePayment(asUser(123, domain.RoleCustomer), 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, idr("25000"), payment.Amount)
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+		})
+
+		t.Run("should only charge pending orders", func(t *testing.T) {
+			setup()
+			order := pendingOrder()
+			order.Status = domain.StatusPaid
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(order, nil).Once()
+
+			_, err := paymentUseCase.CreatePayment(asUser(123, domain.RoleCustomer), 10)
+
+			assert.ErrorIs(t, err, domain.ErrOrderNotPayable)
+			mockGateway.AssertNotCalled(t, "CreateCharge", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should forbid customers to pay for orders of other users", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(pendingOrder(), nil).Once()
+
+			_, err := paymentUseCase.CreatePayment(asUser(456, domain.RoleCustomer), 10)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockGateway.AssertNotCalled(t, "CreateCharge", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return an error for unknown orders", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(nil, nil).Once()
+
+			_, err := paymentUseCase.CreatePayment(asUser(123, domain.RoleCustomer), 10)
+
+			assert.ErrorIs(t, err, usecase.ErrOrderNotFound)
+		})
+	})
+
+	t.Run("HandlePaymentEvent", func(t *testing.T) {
+		t.Run("should capture an authorized payment and mark the order as paid", func(t *testing.T) {
+			setup()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(pendingPayment(), nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(pendingOrder(), nil).Once()
+			mockGateway.On("Capture", mock.Anything, "ch_1", idr("25000"), "payment-5-capture").Return(nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.Status == domain.PaymentCaptured
+			})).Return(nil).Once()
+			mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+				return o.Status == domain.StatusPaid
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.paid")).Return(nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.NoError(t, err)
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+			mockOrderRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should send the capture again for captured payments", func(t *testing.T) {
+			setup()
+			payment := pendingPayment()
+			payment.Status = domain.PaymentCaptured
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(payment, nil).Once()
+			mockGateway.On("Capture", mock.Anything, "ch_1", idr("25000"), "payment-5-capture").Return(nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.NoError(t, err)
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+			mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should ignore events for failed payments", func(t *testing.T) {
+			setup()
+			payment := pendingPayment()
+			payment.Status = domain.PaymentFailed
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(payment, nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.NoError(t, err)
+			mockGateway.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should void the payment instead of capturing it if the order cannot be paid", func(t *testing.T) {
+			setup()
+			order := pendingOrder()
+			order.Status = domain.StatusCancelled
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(pendingPayment(), nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(order, nil).Once()
+			mockGateway.On("Void", mock.Anything, "ch_1", "payment-5-void").Return(nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.Status == domain.PaymentVoided
+			})).Return(nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.NoError(t, err)
+			mockPaymentRepo.AssertExpectations(t)
+			mockGateway.AssertExpectations(t)
+			mockGateway.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should record declined payments", func(t *testing.T) {
+			setup()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(pendingPayment(), nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.Status == domain.PaymentFailed
+			})).Return(nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventFailed, "ch_1")
+
+			assert.NoError(t, err)
+			mockPaymentRepo.AssertExpectations(t)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should capture only after the payment and the order were recorded", func(t *testing.T) {
+			setup()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(pendingPayment(), nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(pendingOrder(), nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.ErrorContains(t, err, "connection reset")
+			mockGateway.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return the error if the capture fails, so the event is delivered again", func(t *testing.T) {
+			setup()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(pendingPayment(), nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(pendingOrder(), nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOrderRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.paid")).Return(nil).Once()
+			mockGateway.On("Capture", mock.Anything, "ch_1", idr("25000"), "payment-5-capture").Return(errors.New("provider unavailable")).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_1")
+
+			assert.ErrorContains(t, err, "provider unavailable")
+			mockGateway.AssertExpectations(t)
+		})
+
+		t.Run("should return an error for unknown payments", func(t *testing.T) {
+			setup()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_unknown").Return(nil, nil).Once()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), domain.PaymentEventAuthorized, "ch_unknown")
+
+			assert.ErrorIs(t, err, usecase.ErrPaymentNotFound)
+		})
+
+		t.Run("should reject unsupported events", func(t *testing.T) {
+			setup()
+
+			err := paymentUseCase.HandlePaymentEvent(context.Background(), "payment.disputed", "ch_1")
+
+			assert.ErrorIs(t, err, domain.ErrUnsupportedPaymentEvent)
+			mockPaymentRepo.AssertNotCalled(t, "FindByProviderPaymentIDForUpdate", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+}
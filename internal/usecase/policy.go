@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+)
+
+var (
+	ErrUnauthenticated = domain.NewError(domain.KindUnauthenticated, "authentication_required", "authentication required")
+	ErrForbidden       = domain.NewError(domain.KindForbidden, "forbidden", "not allowed to perform this operation")
+)
+
+// roleScopes is the access policy for users: the scopes granted by each role.
+// Every user may also place orders and manage carts for itself and read its own orders.
+// API keys are granted their scopes individually. Use cases check the policy before
+// doing any work, so the rules hold for every delivery mechanism.
+var roleScopes = map[domain.Role][]domain.Scope{
+	domain.RoleCustomer: {},
+	domain.RoleStaff:    {domain.ScopeReadAnyOrder, domain.ScopeTransitionOrders, domain.ScopeManageReturns, domain.ScopeManageShipments},
+	domain.RoleAdmin:    domain.Scopes,
+}
+
+// authorize checks that the principal in the context holds the scope.
+func authorize(ctx context.Context, scope domain.Scope) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+	if !granted(principal, scope) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeOwner checks that the principal in the context is the user owning a resource,
+// or holds the scope to access the resources of any user.
+func authorizeOwner(ctx context.Context, ownerID int64, scope domain.Scope) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+	isOwner := principal.UserID != 0 && principal.UserID == ownerID
+	if !isOwner && !granted(principal, scope) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func granted(principal *domain.Principal, scope domain.Scope) bool {
+	if principal.HasScope(scope) {
+		return true
+	}
+	for _, role := range principal.Roles {
+		for _, s := range roleScopes[role] {
+			if s == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
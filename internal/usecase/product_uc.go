@@ -2,41 +2,50 @@ package usecase
 
 import (
 	"context"
-	"errors"
 
 	"github.com/elokanugrah/go-order-system/internal/domain"
 	"github.com/elokanugrah/go-order-system/internal/dto"
 )
 
-var ErrProductNotFound = errors.New("product not found")
+var ErrProductNotFound = domain.NewError(domain.KindNotFound, "product_not_found", "product not found")
 
 type ProductUseCase struct {
-	productRepo ProductRepository
+	productRepo   ProductRepository
+	taxCalculator TaxCalculator
 }
 
-func NewProductUseCase(pr ProductRepository) *ProductUseCase {
+func NewProductUseCase(pr ProductRepository, tc TaxCalculator) *ProductUseCase {
 	return &ProductUseCase{
-		productRepo: pr,
+		productRepo:   pr,
+		taxCalculator: tc,
 	}
 }
 
 // CreateProduct handles the logic for creating a new product.
 func (uc *ProductUseCase) CreateProduct(ctx context.Context, input dto.CreateProductInput) (*domain.Product, error) {
+	if err := authorize(ctx, domain.ScopeManageProducts); err != nil {
+		return nil, err
+	}
+
 	// Validate input data.
 	if input.Name == "" {
-		return nil, errors.New("product name cannot be empty")
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "name", "cannot be empty")
 	}
-	if input.Price <= 0 {
-		return nil, errors.New("product price must be positive")
+	if !input.Price.IsPositive() {
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "price", "must be positive")
 	}
 	if input.Quantity < 0 {
-		return nil, errors.New("product quantity cannot be negative")
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "quantity", "cannot be negative")
+	}
+	if err := uc.checkTaxCategory(ctx, input.TaxCategory.OrDefault()); err != nil {
+		return nil, err
 	}
 
 	newProduct := &domain.Product{
-		Name:     input.Name,
-		Price:    input.Price,
-		Quantity: input.Quantity,
+		Name:        input.Name,
+		Price:       input.Price,
+		TaxCategory: input.TaxCategory.OrDefault(),
+		Quantity:    input.Quantity,
 	}
 
 	err := uc.productRepo.Save(ctx, newProduct)
@@ -81,6 +90,10 @@ func (uc *ProductUseCase) ListProducts(ctx context.Context, page, pageSize int)
 
 // UpdateProduct handles the logic for updating an existing product.
 func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, input dto.UpdateProductInput) (*domain.Product, error) {
+	if err := authorize(ctx, domain.ScopeManageProducts); err != nil {
+		return nil, err
+	}
+
 	productToUpdate, err := uc.productRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -90,19 +103,27 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, input dto
 	}
 
 	if input.Name == "" {
-		return nil, errors.New("product name cannot be empty")
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "name", "cannot be empty")
 	}
-	if input.Price <= 0 {
-		return nil, errors.New("product price must be positive")
+	if !input.Price.IsPositive() {
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "price", "must be positive")
 	}
 	if input.Quantity < 0 {
-		return nil, errors.New("product quantity cannot be negative")
+		return nil, domain.NewFieldError(domain.ErrInvalidProduct, "quantity", "cannot be negative")
+	}
+	if input.TaxCategory != "" {
+		if err := uc.checkTaxCategory(ctx, input.TaxCategory); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update the fields of the existing domain object.
 	productToUpdate.Name = input.Name
 	productToUpdate.Price = input.Price
 	productToUpdate.Quantity = input.Quantity
+	if input.TaxCategory != "" {
+		productToUpdate.TaxCategory = input.TaxCategory
+	}
 
 	err = uc.productRepo.Update(ctx, productToUpdate)
 	if err != nil {
@@ -114,6 +135,10 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, input dto
 
 // DeleteProduct handles the logic for deleting a product.
 func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
+	if err := authorize(ctx, domain.ScopeManageProducts); err != nil {
+		return err
+	}
+
 	product, err := uc.productRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
@@ -124,3 +149,15 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
 
 	return uc.productRepo.Delete(ctx, id)
 }
+
+// checkTaxCategory rejects a category the tax calculator has no rule for.
+func (uc *ProductUseCase) checkTaxCategory(ctx context.Context, category domain.TaxCategory) error {
+	known, err := uc.taxCalculator.KnowsCategory(ctx, category)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return domain.NewFieldError(domain.ErrInvalidProduct, "tax_category", "must be a known tax category")
+	}
+	return nil
+}
@@ -15,12 +15,14 @@ import (
 
 func TestProductUseCase(t *testing.T) {
 	var mockProductRepo *mocks.ProductRepository
+	var mockTaxCalculator *mocks.TaxCalculator
 	var productUseCase *usecase.ProductUseCase
 
 	// setup is a helper function to reset mocks for each test group.
 	setup := func() {
 		mockProductRepo = new(mocks.ProductRepository)
-		productUseCase = usecase.NewProductUseCase(mockProductRepo)
+		mockTaxCalculator = new(mocks.TaxCalculator)
+		productUseCase = usecase.NewProductUseCase(mockProductRepo, mockTaxCalculator)
 	}
 
 	t.Run("GetProductByID", func(t *testing.T) {
@@ -42,7 +44,8 @@ func TestProductUseCase(t *testing.T) {
 	t.Run("CreateProduct", func(t *testing.T) {
 		setup()
 		t.Run("should create product successfully with valid input", func(t *testing.T) {
-			input := dto.CreateProductInput{Name: "New Gadget", Price: 1500, Quantity: 100}
+			input := dto.CreateProductInput{Name: "New Gadget", Price: idr("1500"), Quantity: 100}
+			mockTaxCalculator.On("KnowsCategory", mock.Anything, domain.TaxCategoryStandard).Return(true, nil).Once()
 
 			// When Save is called, we tell the mock to do nothing and return no error.
 			// Use mock.MatchedBy to check if the argument passed to Save has the correct name.
@@ -50,7 +53,7 @@ func TestProductUseCase(t *testing.T) {
 				return p.Name == input.Name
 			})).Return(nil).Once()
 
-			product, err := productUseCase.CreateProduct(context.Background(), input)
+			product, err := productUseCase.CreateProduct(asUser(1, domain.RoleAdmin), input)
 
 			// Assert
 			assert.NoError(t, err)
@@ -60,15 +63,43 @@ func TestProductUseCase(t *testing.T) {
 		})
 
 		t.Run("should return error on invalid input", func(t *testing.T) {
-			input := dto.CreateProductInput{Name: "", Price: 1500, Quantity: 100} // Empty name
+			input := dto.CreateProductInput{Name: "", Price: idr("1500"), Quantity: 100} // Empty name
 
 			// don't set up the mock here because the function should fail before calling the repo.
-			product, err := productUseCase.CreateProduct(context.Background(), input)
+			product, err := productUseCase.CreateProduct(asUser(1, domain.RoleAdmin), input)
 
 			// Assert
 			assert.Error(t, err)
 			assert.Nil(t, product)
 		})
+
+		t.Run("should reject an unknown tax category", func(t *testing.T) {
+			setup()
+			input := dto.CreateProductInput{Name: "New Gadget", Price: idr("1500"), Quantity: 100, TaxCategory: "luxury"}
+			mockTaxCalculator.On("KnowsCategory", mock.Anything, domain.TaxCategory("luxury")).Return(false, nil).Once()
+
+			product, err := productUseCase.CreateProduct(asUser(1, domain.RoleAdmin), input)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+			var fieldErr *domain.FieldError
+			assert.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, "tax_category", fieldErr.Field)
+			assert.Nil(t, product)
+			mockProductRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+			mockTaxCalculator.AssertExpectations(t)
+		})
+
+		t.Run("should forbid non-admins to create products", func(t *testing.T) {
+			input := dto.CreateProductInput{Name: "New Gadget", Price: idr("1500"), Quantity: 100}
+
+			for _, role := range []domain.Role{domain.RoleCustomer, domain.RoleStaff} {
+				product, err := productUseCase.CreateProduct(asUser(2, role), input)
+
+				assert.ErrorIs(t, err, usecase.ErrForbidden, role)
+				assert.Nil(t, product)
+			}
+		})
 	})
 
 	t.Run("ListProducts", func(t *testing.T) {
@@ -93,14 +124,14 @@ func TestProductUseCase(t *testing.T) {
 	t.Run("UpdateProduct", func(t *testing.T) {
 		t.Run("should update product successfully", func(t *testing.T) {
 			setup()
-			input := dto.UpdateProductInput{Name: "Updated Name", Price: 200, Quantity: 20}
-			existingProduct := &domain.Product{ID: 1, Name: "Old Name", Price: 100, Quantity: 10}
+			input := dto.UpdateProductInput{Name: "Updated Name", Price: idr("200"), Quantity: 20}
+			existingProduct := &domain.Product{ID: 1, Name: "Old Name", Price: idr("100"), Quantity: 10}
 
 			// Mock the two repository calls needed for an update.
 			mockProductRepo.On("FindByID", mock.Anything, int64(1)).Return(existingProduct, nil).Once()
 			mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
 
-			updatedProduct, err := productUseCase.UpdateProduct(context.Background(), 1, input)
+			updatedProduct, err := productUseCase.UpdateProduct(asUser(1, domain.RoleAdmin), 1, input)
 
 			// Assert
 			assert.NoError(t, err)
@@ -110,14 +141,34 @@ func TestProductUseCase(t *testing.T) {
 			mockProductRepo.AssertExpectations(t)
 		})
 
+		t.Run("should reject an unknown tax category", func(t *testing.T) {
+			setup()
+			input := dto.UpdateProductInput{Name: "Updated Name", Price: idr("200"), Quantity: 20, TaxCategory: "luxury"}
+			existingProduct := &domain.Product{ID: 1, Name: "Old Name", Price: idr("100"), TaxCategory: domain.TaxCategoryStandard, Quantity: 10}
+
+			mockProductRepo.On("FindByID", mock.Anything, int64(1)).Return(existingProduct, nil).Once()
+			mockTaxCalculator.On("KnowsCategory", mock.Anything, domain.TaxCategory("luxury")).Return(false, nil).Once()
+
+			product, err := productUseCase.UpdateProduct(asUser(1, domain.RoleAdmin), 1, input)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+			var fieldErr *domain.FieldError
+			assert.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, "tax_category", fieldErr.Field)
+			assert.Nil(t, product)
+			assert.Equal(t, domain.TaxCategoryStandard, existingProduct.TaxCategory)
+			mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+
 		t.Run("should return not found error when updating non-existent product", func(t *testing.T) {
 			setup()
-			input := dto.UpdateProductInput{Name: "Updated Name", Price: 200, Quantity: 20}
+			input := dto.UpdateProductInput{Name: "Updated Name", Price: idr("200"), Quantity: 20}
 
 			// Mock FindByID to return "not found".
 			mockProductRepo.On("FindByID", mock.Anything, int64(99)).Return(nil, nil).Once()
 
-			product, err := productUseCase.UpdateProduct(context.Background(), 99, input)
+			product, err := productUseCase.UpdateProduct(asUser(1, domain.RoleAdmin), 99, input)
 
 			// Assert
 			assert.Error(t, err)
@@ -135,7 +186,7 @@ func TestProductUseCase(t *testing.T) {
 			mockProductRepo.On("FindByID", mock.Anything, int64(1)).Return(existingProduct, nil).Once()
 			mockProductRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
 
-			err := productUseCase.DeleteProduct(context.Background(), 1)
+			err := productUseCase.DeleteProduct(asUser(1, domain.RoleAdmin), 1)
 
 			// Assert
 			assert.NoError(t, err)
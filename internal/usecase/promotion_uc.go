@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+)
+
+var (
+	ErrCouponNotFound  = domain.NewError(domain.KindNotFound, "coupon_not_found", "coupon not found")
+	ErrCouponCodeTaken = domain.NewError(domain.KindConflict, "coupon_code_taken", "coupon code already exists")
+)
+
+type PromotionUseCase struct {
+	couponRepo CouponRepository
+}
+
+// OrderUseCase applies coupons through PromotionUseCase while placing an order.
+func NewPromotionUseCase(cr CouponRepository) *PromotionUseCase {
+	return &PromotionUseCase{
+		couponRepo: cr,
+	}
+}
+
+// CreateCoupon validates and stores a new coupon. Codes are case-insensitive and unique.
+func (uc *PromotionUseCase) CreateCoupon(ctx context.Context, input dto.CreateCouponInput) (*domain.Coupon, error) {
+	if err := authorize(ctx, domain.ScopeManageCoupons); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	coupon := &domain.Coupon{
+		Code:           domain.NormalizeCouponCode(input.Code),
+		Type:           input.Type,
+		Percent:        input.Percent,
+		Amount:         input.Amount,
+		MinSubtotal:    input.MinSubtotal,
+		ProductIDs:     input.ProductIDs,
+		MaxUses:        input.MaxUses,
+		MaxUsesPerUser: input.MaxUsesPerUser,
+		StartsAt:       input.StartsAt,
+		EndsAt:         input.EndsAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.couponRepo.FindByCode(ctx, coupon.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCouponCodeTaken
+	}
+
+	if err := uc.couponRepo.Save(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// GetCoupon retrieves a coupon by its code.
+func (uc *PromotionUseCase) GetCoupon(ctx context.Context, code string) (*domain.Coupon, error) {
+	if err := authorize(ctx, domain.ScopeManageCoupons); err != nil {
+		return nil, err
+	}
+
+	coupon, err := uc.couponRepo.FindByCode(ctx, domain.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+// applyCoupon locks the coupon, checks its usage limits for the order's user and
+// deducts its discount from the order. The lock is held until the transaction ends,
+// so concurrent orders see each other's redemptions. It must be called within a transaction.
+func (uc *PromotionUseCase) applyCoupon(txCtx context.Context, code string, order *domain.Order) (*domain.Coupon, error) {
+	coupon, err := uc.couponRepo.FindByCodeForUpdate(txCtx, domain.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, domain.Unprocessable(ErrCouponNotFound)
+	}
+
+	userUses, err := uc.couponRepo.CountRedemptionsByUser(txCtx, coupon.ID, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := coupon.CheckUsage(userUses); err != nil {
+		return nil, err
+	}
+
+	discount, err := coupon.Discount(order, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := order.ApplyDiscount(coupon.Code, discount); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// redeemCoupon counts the use of a coupon by a saved order.
+// It must be called within the transaction that applied the coupon.
+func (uc *PromotionUseCase) redeemCoupon(txCtx context.Context, coupon *domain.Coupon, order *domain.Order) error {
+	redemption := coupon.Redeem(order)
+
+	if err := uc.couponRepo.Update(txCtx, coupon); err != nil {
+		return err
+	}
+	return uc.couponRepo.SaveRedemption(txCtx, redemption)
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/events"
+)
+
+var ErrReturnNotFound = domain.NewError(domain.KindNotFound, "return_not_found", "return not found")
+
+// returnEventTypes maps a return status to the event recorded when a return enters it.
+var returnEventTypes = map[domain.ReturnStatus]events.Type{
+	domain.ReturnApproved: events.TypeReturnApproved,
+	domain.ReturnRejected: events.TypeReturnRejected,
+	domain.ReturnReceived: events.TypeReturnReceived,
+}
+
+type ReturnUseCase struct {
+	returnRepo  ReturnRepository
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+	txManager   TransactionManager
+	outboxRepo  OutboxRepository
+	payments    *PaymentUseCase
+}
+
+// Returns are refunded through PaymentUseCase, and their "returns.*" events are
+// written to the outbox and relayed to the message broker by OutboxRelay.
+func NewReturnUseCase(rr ReturnRepository, or OrderRepository, pr ProductRepository, tm TransactionManager, obr OutboxRepository, payments *PaymentUseCase) *ReturnUseCase {
+	return &ReturnUseCase{
+		returnRepo:  rr,
+		orderRepo:   or,
+		productRepo: pr,
+		txManager:   tm,
+		outboxRepo:  obr,
+		payments:    payments,
+	}
+}
+
+// RequestReturn asks to send back items of a paid, shipped or completed order and
+// records a "returns.requested" event. The refund is what was paid for the items.
+// Customers may only return items of their own orders.
+func (uc *ReturnUseCase) RequestReturn(ctx context.Context, input dto.CreateReturnInput) (*domain.Return, error) {
+	var rma *domain.Return
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Lock the order, so concurrent requests see each other's returns and cannot
+		// return the same units twice.
+		order, err := uc.orderRepo.FindByIDForUpdate(txCtx, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return ErrOrderNotFound
+		}
+		if err := authorizeOwner(ctx, order.UserID, domain.ScopeManageReturns); err != nil {
+			return err
+		}
+
+		existing, err := uc.returnRepo.FindByOrderID(txCtx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		items := make([]domain.ReturnItem, len(input.Items))
+		for i, item := range input.Items {
+			items[i] = domain.ReturnItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		rma, err = domain.NewReturn(order, items, input.Reason, domain.ReturnedQuantities(existing))
+		if err != nil {
+			return err
+		}
+
+		if err := uc.returnRepo.Save(txCtx, rma); err != nil {
+			return err
+		}
+
+		return enqueueEvent(txCtx, uc.outboxRepo, events.TypeReturnRequested, events.NewReturnRequested(rma))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rma, nil
+}
+
+// GetReturn retrieves a return with its items. Customers may only retrieve their own returns.
+func (uc *ReturnUseCase) GetReturn(ctx context.Context, id int64) (*domain.Return, error) {
+	rma, err := uc.returnRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rma == nil {
+		return nil, ErrReturnNotFound
+	}
+	if err := authorizeOwner(ctx, rma.UserID, domain.ScopeManageReturns); err != nil {
+		return nil, err
+	}
+	return rma, nil
+}
+
+// ListReturns lists the returns of an order. Customers may only list returns of their own orders.
+func (uc *ReturnUseCase) ListReturns(ctx context.Context, orderID int64) ([]domain.Return, error) {
+	order, err := uc.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := authorizeOwner(ctx, order.UserID, domain.ScopeManageReturns); err != nil {
+		return nil, err
+	}
+	return uc.returnRepo.FindByOrderID(ctx, orderID)
+}
+
+// ApproveReturn accepts a requested return and records a "returns.approved" event.
+func (uc *ReturnUseCase) ApproveReturn(ctx context.Context, id int64) (*domain.Return, error) {
+	return uc.transition(ctx, id, func(_ context.Context, rma *domain.Return) error {
+		return rma.Approve()
+	})
+}
+
+// RejectReturn declines a requested return and records a "returns.rejected" event.
+// Its items may be requested for return again.
+func (uc *ReturnUseCase) RejectReturn(ctx context.Context, id int64) (*domain.Return, error) {
+	return uc.transition(ctx, id, func(_ context.Context, rma *domain.Return) error {
+		return rma.Reject()
+	})
+}
+
+// ReceiveReturn records that the items of an approved return arrived. In the same
+// transaction the items are restocked, the refund of what was paid for them is
+// recorded on the payment, and a "returns.received" event is recorded; the refund is
+// sent to the payment provider once the transaction committed. If that fails, calling
+// ReceiveReturn again sends the refund again, and the provider pays it back only once.
+func (uc *ReturnUseCase) ReceiveReturn(ctx context.Context, id int64) (*domain.Return, error) {
+	if err := authorize(ctx, domain.ScopeManageReturns); err != nil {
+		return nil, err
+	}
+
+	rma, err := uc.returnRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rma == nil {
+		return nil, ErrReturnNotFound
+	}
+	if !rma.RefundPending() {
+		rma, err = uc.transition(ctx, id, uc.receive)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refundID, err := uc.payments.sendRefund(ctx, rma.OrderID, rma.RefundAmount, fmt.Sprintf("return-%d-refund", rma.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := rma.RecordRefund(refundID); err != nil {
+		return nil, err
+	}
+	if err := uc.returnRepo.UpdateStatus(ctx, rma); err != nil {
+		return nil, err
+	}
+
+	return rma, nil
+}
+
+// receive restocks the items of a return and reserves their refund. It must be called within a transaction.
+func (uc *ReturnUseCase) receive(txCtx context.Context, rma *domain.Return) error {
+	// Check before restocking and reserving the refund, which are not undone by the status check.
+	if err := rma.CanReceive(); err != nil {
+		return err
+	}
+
+	productIDs := make([]int64, len(rma.Items))
+	for i, item := range rma.Items {
+		productIDs[i] = item.ProductID
+	}
+	products, err := uc.productRepo.FindManyByIDsForUpdate(txCtx, productIDs)
+	if err != nil {
+		return err
+	}
+	productsByID := make(map[int64]*domain.Product, len(products))
+	for i := range products {
+		productsByID[products[i].ID] = &products[i]
+	}
+
+	for _, item := range rma.Items {
+		p, ok := productsByID[item.ProductID]
+		if !ok {
+			return fmt.Errorf("product %d of return %d not found", item.ProductID, rma.ID)
+		}
+		if err := p.IncreaseStock(item.Quantity); err != nil {
+			return err
+		}
+		if err := uc.productRepo.Update(txCtx, p); err != nil {
+			return err
+		}
+	}
+
+	refunded, err := uc.payments.reserveRefund(txCtx, rma.OrderID, rma.RefundAmount)
+	if err != nil {
+		return err
+	}
+	return rma.Receive(refunded)
+}
+
+// transition locks a return, applies change to it, persists it and records the
+// event of its new status, all in one transaction.
+func (uc *ReturnUseCase) transition(ctx context.Context, id int64, change func(txCtx context.Context, rma *domain.Return) error) (*domain.Return, error) {
+	if err := authorize(ctx, domain.ScopeManageReturns); err != nil {
+		return nil, err
+	}
+
+	var rma *domain.Return
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		rma, err = uc.returnRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if rma == nil {
+			return ErrReturnNotFound
+		}
+
+		if err := change(txCtx, rma); err != nil {
+			return err
+		}
+
+		if err := uc.returnRepo.UpdateStatus(txCtx, rma); err != nil {
+			return err
+		}
+
+		return enqueueEvent(txCtx, uc.outboxRepo, returnEventTypes[rma.Status], events.NewReturnStatusChanged(rma))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rma, nil
+}
@@ -0,0 +1,314 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReturnUseCase(t *testing.T) {
+	var mockReturnRepo *mocks.ReturnRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockProductRepo *mocks.ProductRepository
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockPaymentRepo *mocks.PaymentRepository
+	var mockGateway *mocks.PaymentGateway
+	var returnUseCase *usecase.ReturnUseCase
+
+	setup := func() {
+		mockReturnRepo = new(mocks.ReturnRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockPaymentRepo = new(mocks.PaymentRepository)
+		mockGateway = new(mocks.PaymentGateway)
+		mockGateway.On("Name").Return("fake").Maybe()
+
+		txManager := new(mocks.TransactionManager)
+		txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Maybe()
+
+		orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, txManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+		paymentUseCase := usecase.NewPaymentUseCase(mockPaymentRepo, mockOrderRepo, txManager, mockGateway, orderUseCase)
+		returnUseCase = usecase.NewReturnUseCase(mockReturnRepo, mockOrderRepo, mockProductRepo, txManager, mockOutboxRepo, paymentUseCase)
+	}
+
+	shippedOrder := func() *domain.Order {
+		return &domain.Order{
+			ID:          10,
+			UserID:      123,
+			Status:      domain.StatusShipped,
+			TotalAmount: idr("2000000"),
+			OrderItems: []domain.OrderItem{
+				{Product: domain.Product{ID: 1}, Quantity: 2, PriceAtOrder: idr("750000")},
+				{Product: domain.Product{ID: 2}, Quantity: 1, PriceAtOrder: idr("500000")},
+			},
+		}
+	}
+	approvedReturn := func() *domain.Return {
+		return &domain.Return{
+			ID:           7,
+			OrderID:      10,
+			UserID:       123,
+			Status:       domain.ReturnApproved,
+			Items:        []domain.ReturnItem{{ProductID: 1, Quantity: 2, PriceAtOrder: idr("750000")}},
+			RefundAmount: idr("1500000"),
+		}
+	}
+	capturedPayment := func() *domain.Payment {
+		return &domain.Payment{ID: 5, OrderID: 10, Provider: "fake", ProviderPaymentID: "ch_1", Amount: idr("2000000"), Refunded: idr("0"), Status: domain.PaymentCaptured}
+	}
+	staff := asUser(1, domain.RoleStaff)
+
+	t.Run("RequestReturn", func(t *testing.T) {
+		t.Run("should request the return of items of the caller's order", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(shippedOrder(), nil).Once()
+			mockReturnRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(nil, nil).Once()
+			mockReturnRepo.On("Save", mock.Anything, mock.MatchedBy(func(r *domain.Return) bool {
+				return r.OrderID == 10 && r.UserID == 123 && r.Status == domain.ReturnRequested && len(r.Items) == 1
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("returns.requested")).Return(nil).Once()
+
+			rma, err := returnUseCase.RequestReturn(asUser(123, domain.RoleCustomer), dto.CreateReturnInput{
+				OrderID: 10,
+				Reason:  "too small",
+				Items:   []dto.CreateReturnItemInput{{ProductID: 1, Quantity: 1}},
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, idr("750000"), rma.RefundAmount)
+			assert.Equal(t, "too small", rma.Reason)
+			mockReturnRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should not return items that are already being returned", func(t *testing.T) {
+			setup()
+			existing := []domain.Return{{Status: domain.ReturnRequested, Items: []domain.ReturnItem{{ProductID: 2, Quantity: 1}}}}
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(shippedOrder(), nil).Once()
+			mockReturnRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(existing, nil).Once()
+
+			_, err := returnUseCase.RequestReturn(asUser(123, domain.RoleCustomer), dto.CreateReturnInput{
+				OrderID: 10,
+				Items:   []dto.CreateReturnItemInput{{ProductID: 2, Quantity: 1}},
+			})
+
+			assert.ErrorIs(t, err, domain.ErrInvalidReturn)
+			mockReturnRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should forbid customers to return items of orders of other users", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(shippedOrder(), nil).Once()
+
+			_, err := returnUseCase.RequestReturn(asUser(456, domain.RoleCustomer), dto.CreateReturnInput{
+				OrderID: 10,
+				Items:   []dto.CreateReturnItemInput{{ProductID: 1, Quantity: 1}},
+			})
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockReturnRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("ApproveReturn", func(t *testing.T) {
+		t.Run("should approve a requested return", func(t *testing.T) {
+			setup()
+			rma := approvedReturn()
+			rma.Status = domain.ReturnRequested
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(rma, nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Return) bool {
+				return r.Status == domain.ReturnApproved
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("returns.approved")).Return(nil).Once()
+
+			_, err := returnUseCase.ApproveReturn(staff, 7)
+
+			assert.NoError(t, err)
+			mockReturnRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should forbid customers to approve returns", func(t *testing.T) {
+			setup()
+
+			_, err := returnUseCase.ApproveReturn(asUser(123, domain.RoleCustomer), 7)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockReturnRepo.AssertNotCalled(t, "FindByIDForUpdate", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return an error for unknown returns", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(nil, nil).Once()
+
+			_, err := returnUseCase.ApproveReturn(staff, 7)
+
+			assert.ErrorIs(t, err, usecase.ErrReturnNotFound)
+		})
+	})
+
+	t.Run("RejectReturn", func(t *testing.T) {
+		t.Run("should not reject an approved return", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+
+			_, err := returnUseCase.RejectReturn(staff, 7)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidReturnTransition)
+			mockReturnRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("ReceiveReturn", func(t *testing.T) {
+		t.Run("should restock the items and refund their price", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Quantity: 3}}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+				return p.ID == 1 && p.Quantity == 5
+			})).Return(nil).Once()
+			mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return([]domain.Payment{*capturedPayment()}, nil).Twice()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(capturedPayment(), nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.Refunded == idr("1500000")
+			})).Return(nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Return) bool {
+				return r.Status == domain.ReturnReceived && r.RefundID == ""
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("returns.received")).Return(nil).Once()
+			mockGateway.On("Refund", mock.Anything, "ch_1", idr("1500000"), "return-7-refund").Return("re_1", nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Return) bool {
+				return r.Status == domain.ReturnReceived && r.RefundID == "re_1"
+			})).Return(nil).Once()
+
+			rma, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.ReturnReceived, rma.Status)
+			assert.Equal(t, "re_1", rma.RefundID)
+			mockProductRepo.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+			mockGateway.AssertExpectations(t)
+			mockReturnRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should refund no more than is left on the payment", func(t *testing.T) {
+			setup()
+			payment := capturedPayment()
+			payment.Refunded = idr("600000")
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Quantity: 3}}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+			mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return([]domain.Payment{*payment}, nil).Twice()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(payment, nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.Refunded == idr("2000000")
+			})).Return(nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Twice()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("returns.received")).Return(nil).Once()
+			mockGateway.On("Refund", mock.Anything, "ch_1", idr("1400000"), "return-7-refund").Return("re_1", nil).Once()
+
+			rma, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			require.NoError(t, err)
+			assert.Equal(t, idr("1400000"), rma.RefundAmount)
+			mockGateway.AssertExpectations(t)
+			mockPaymentRepo.AssertExpectations(t)
+		})
+
+		t.Run("should keep the return received if the refund fails", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Quantity: 3}}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+			mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return([]domain.Payment{*capturedPayment()}, nil).Twice()
+			mockPaymentRepo.On("FindByProviderPaymentIDForUpdate", mock.Anything, "fake", "ch_1").Return(capturedPayment(), nil).Once()
+			mockPaymentRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("returns.received")).Return(nil).Once()
+			mockGateway.On("Refund", mock.Anything, "ch_1", idr("1500000"), "return-7-refund").Return("", errors.New("provider unavailable")).Once()
+
+			_, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			assert.ErrorContains(t, err, "provider unavailable")
+			mockReturnRepo.AssertExpectations(t)
+		})
+
+		t.Run("should only send the refund again for received returns whose refund failed", func(t *testing.T) {
+			setup()
+			rma := approvedReturn()
+			rma.Status = domain.ReturnReceived
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(rma, nil).Once()
+			mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return([]domain.Payment{*capturedPayment()}, nil).Once()
+			mockGateway.On("Refund", mock.Anything, "ch_1", idr("1500000"), "return-7-refund").Return("re_1", nil).Once()
+			mockReturnRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Return) bool {
+				return r.RefundID == "re_1"
+			})).Return(nil).Once()
+
+			received, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			require.NoError(t, err)
+			assert.Equal(t, "re_1", received.RefundID)
+			mockReturnRepo.AssertNotCalled(t, "FindByIDForUpdate", mock.Anything, mock.Anything)
+			mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockPaymentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockOutboxRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should not restock returns that were not approved", func(t *testing.T) {
+			setup()
+			rma := approvedReturn()
+			rma.Status = domain.ReturnRequested
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(rma, nil).Once()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(rma, nil).Once()
+
+			_, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidReturnTransition)
+			mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockGateway.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("should fail if the order has no captured payment", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockReturnRepo.On("FindByIDForUpdate", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+			mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Quantity: 3}}, nil).Once()
+			mockProductRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+			mockPaymentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(nil, nil).Once()
+
+			_, err := returnUseCase.ReceiveReturn(staff, 7)
+
+			assert.ErrorIs(t, err, usecase.ErrNoCapturedPayment)
+			mockReturnRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+			mockGateway.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("GetReturn", func(t *testing.T) {
+		t.Run("should forbid customers to read returns of other users", func(t *testing.T) {
+			setup()
+			mockReturnRepo.On("FindByID", mock.Anything, int64(7)).Return(approvedReturn(), nil).Once()
+
+			_, err := returnUseCase.GetReturn(asUser(456, domain.RoleCustomer), 7)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+		})
+	})
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/events"
+)
+
+var ErrShipmentNotFound = domain.NewError(domain.KindNotFound, "shipment_not_found", "shipment not found")
+
+// shipmentEventTypes maps a shipment status to the event recorded when a shipment enters it.
+var shipmentEventTypes = map[domain.ShipmentStatus]events.Type{
+	domain.ShipmentInTransit: events.TypeShipmentInTransit,
+	domain.ShipmentDelivered: events.TypeShipmentDelivered,
+}
+
+type ShipmentUseCase struct {
+	shipmentRepo ShipmentRepository
+	orderRepo    OrderRepository
+	txManager    TransactionManager
+	outboxRepo   OutboxRepository
+	orders       *OrderUseCase
+}
+
+// Orders are moved to shipped and completed through OrderUseCase as their shipments
+// progress, and the "shipments.*" events are written to the outbox and relayed to the
+// message broker by OutboxRelay.
+func NewShipmentUseCase(sr ShipmentRepository, or OrderRepository, tm TransactionManager, obr OutboxRepository, orders *OrderUseCase) *ShipmentUseCase {
+	return &ShipmentUseCase{
+		shipmentRepo: sr,
+		orderRepo:    or,
+		txManager:    tm,
+		outboxRepo:   obr,
+		orders:       orders,
+	}
+}
+
+// CreateShipment records that items of a paid order were handed over to a carrier and
+// records a "shipments.created" event. Once all items of the order are in shipments,
+// the order moves to shipped in the same transaction.
+func (uc *ShipmentUseCase) CreateShipment(ctx context.Context, input dto.CreateShipmentInput) (*domain.Shipment, error) {
+	if err := authorize(ctx, domain.ScopeManageShipments); err != nil {
+		return nil, err
+	}
+
+	var shipment *domain.Shipment
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Lock the order, so concurrent shipments cannot ship the same items twice.
+		order, err := uc.orderRepo.FindByIDForUpdate(txCtx, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return ErrOrderNotFound
+		}
+
+		shipments, err := uc.shipmentRepo.FindByOrderID(txCtx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		items := make([]domain.ShipmentItem, len(input.Items))
+		for i, item := range input.Items {
+			items[i] = domain.ShipmentItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		shipment, err = domain.NewShipment(order, input.Carrier, input.TrackingNumber, items, domain.ShippedQuantities(shipments))
+		if err != nil {
+			return err
+		}
+
+		if err := uc.shipmentRepo.Save(txCtx, shipment); err != nil {
+			return err
+		}
+		if err := enqueueEvent(txCtx, uc.outboxRepo, events.TypeShipmentCreated, events.NewShipmentCreated(shipment)); err != nil {
+			return err
+		}
+
+		if !domain.FullyShipped(order, domain.ShippedQuantities(append(shipments, *shipment))) {
+			return nil
+		}
+		return uc.orders.changeStatus(txCtx, order, domain.StatusShipped)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// UpdateShipmentStatus records the tracking status of a shipment and the event of
+// the new status. Once all shipments of a shipped order are delivered, the order
+// moves to completed in the same transaction.
+func (uc *ShipmentUseCase) UpdateShipmentStatus(ctx context.Context, id int64, status domain.ShipmentStatus) (*domain.Shipment, error) {
+	if err := authorize(ctx, domain.ScopeManageShipments); err != nil {
+		return nil, err
+	}
+
+	var shipment *domain.Shipment
+
+	err := uc.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		shipment, err = uc.shipmentRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if shipment == nil {
+			return ErrShipmentNotFound
+		}
+
+		if err := shipment.UpdateStatus(status); err != nil {
+			return err
+		}
+		if err := uc.shipmentRepo.UpdateStatus(txCtx, shipment); err != nil {
+			return err
+		}
+		if err := enqueueEvent(txCtx, uc.outboxRepo, shipmentEventTypes[shipment.Status], events.NewShipmentStatusChanged(shipment)); err != nil {
+			return err
+		}
+
+		if shipment.Status != domain.ShipmentDelivered {
+			return nil
+		}
+		return uc.completeIfDelivered(txCtx, shipment.OrderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// completeIfDelivered moves a shipped order to completed if all of its shipments were
+// delivered. It must be called within a transaction.
+func (uc *ShipmentUseCase) completeIfDelivered(txCtx context.Context, orderID int64) error {
+	// Lock the order, so that of concurrent deliveries the last one sees the others.
+	order, err := uc.orderRepo.FindByIDForUpdate(txCtx, orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return ErrOrderNotFound
+	}
+	if order.Status != domain.StatusShipped {
+		// Items of the order still have to be shipped.
+		return nil
+	}
+
+	shipments, err := uc.shipmentRepo.FindByOrderID(txCtx, orderID)
+	if err != nil {
+		return err
+	}
+	if !domain.AllDelivered(shipments) {
+		return nil
+	}
+	return uc.orders.changeStatus(txCtx, order, domain.StatusCompleted)
+}
+
+// GetShipment retrieves a shipment with its items. Customers may only retrieve
+// shipments of their own orders.
+func (uc *ShipmentUseCase) GetShipment(ctx context.Context, id int64) (*domain.Shipment, error) {
+	shipment, err := uc.shipmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if shipment == nil {
+		return nil, ErrShipmentNotFound
+	}
+
+	order, err := uc.orderRepo.FindByID(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrShipmentNotFound
+	}
+	if err := authorizeOwner(ctx, order.UserID, domain.ScopeReadAnyOrder); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// ListShipments lists the shipments of an order. Customers may only list shipments
+// of their own orders.
+func (uc *ShipmentUseCase) ListShipments(ctx context.Context, orderID int64) ([]domain.Shipment, error) {
+	order, err := uc.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := authorizeOwner(ctx, order.UserID, domain.ScopeReadAnyOrder); err != nil {
+		return nil, err
+	}
+	return uc.shipmentRepo.FindByOrderID(ctx, orderID)
+}
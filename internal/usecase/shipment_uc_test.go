@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipmentUseCase(t *testing.T) {
+	var mockShipmentRepo *mocks.ShipmentRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockOutboxRepo *mocks.OutboxRepository
+	var shipmentUseCase *usecase.ShipmentUseCase
+
+	setup := func() {
+		mockShipmentRepo = new(mocks.ShipmentRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+
+		txManager := new(mocks.TransactionManager)
+		txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Maybe()
+
+		orderUseCase := usecase.NewOrderUseCase(mockOrderRepo, new(mocks.ProductRepository), txManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), knownUsers(), new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+		shipmentUseCase = usecase.NewShipmentUseCase(mockShipmentRepo, mockOrderRepo, txManager, mockOutboxRepo, orderUseCase)
+	}
+
+	paidOrder := func() *domain.Order {
+		return &domain.Order{
+			ID:     10,
+			UserID: 123,
+			Status: domain.StatusPaid,
+			OrderItems: []domain.OrderItem{
+				{Product: domain.Product{ID: 1}, Quantity: 2},
+				{Product: domain.Product{ID: 2}, Quantity: 1},
+			},
+		}
+	}
+	shipmentOf := func(id int64, status domain.ShipmentStatus, items ...domain.ShipmentItem) domain.Shipment {
+		return domain.Shipment{ID: id, OrderID: 10, Carrier: "JNE", TrackingNumber: "JNE123", Status: status, Items: items}
+	}
+	staff := asUser(1, domain.RoleStaff)
+	input := dto.CreateShipmentInput{
+		OrderID:        10,
+		Carrier:        "JNE",
+		TrackingNumber: "JNE123",
+		Items:          []dto.CreateShipmentItemInput{{ProductID: 1, Quantity: 1}},
+	}
+
+	t.Run("CreateShipment", func(t *testing.T) {
+		t.Run("should ship part of the order without moving it to shipped", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(nil, nil).Once()
+			mockShipmentRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *domain.Shipment) bool {
+				return s.OrderID == 10 && s.Carrier == "JNE" && s.Status == domain.ShipmentShipped && len(s.Items) == 1
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.created")).Return(nil).Once()
+
+			shipment, err := shipmentUseCase.CreateShipment(staff, input)
+
+			require.NoError(t, err)
+			assert.Equal(t, "JNE123", shipment.TrackingNumber)
+			mockShipmentRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should move the order to shipped once all items are in shipments", func(t *testing.T) {
+			setup()
+			existing := []domain.Shipment{shipmentOf(1, domain.ShipmentInTransit, domain.ShipmentItem{ProductID: 1, Quantity: 1}, domain.ShipmentItem{ProductID: 2, Quantity: 1})}
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(existing, nil).Once()
+			mockShipmentRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.created")).Return(nil).Once()
+			mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+				return o.Status == domain.StatusShipped
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.shipped")).Return(nil).Once()
+
+			_, err := shipmentUseCase.CreateShipment(staff, input)
+
+			assert.NoError(t, err)
+			mockOrderRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should not ship items twice", func(t *testing.T) {
+			setup()
+			existing := []domain.Shipment{shipmentOf(1, domain.ShipmentShipped, domain.ShipmentItem{ProductID: 1, Quantity: 2})}
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(existing, nil).Once()
+
+			_, err := shipmentUseCase.CreateShipment(staff, input)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+			mockShipmentRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should forbid customers to create shipments", func(t *testing.T) {
+			setup()
+
+			_, err := shipmentUseCase.CreateShipment(asUser(123, domain.RoleCustomer), input)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockOrderRepo.AssertNotCalled(t, "FindByIDForUpdate", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return an error for unknown orders", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(nil, nil).Once()
+
+			_, err := shipmentUseCase.CreateShipment(staff, input)
+
+			assert.ErrorIs(t, err, usecase.ErrOrderNotFound)
+		})
+	})
+
+	t.Run("UpdateShipmentStatus", func(t *testing.T) {
+		t.Run("should record a shipment in transit", func(t *testing.T) {
+			setup()
+			shipment := shipmentOf(1, domain.ShipmentShipped)
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(&shipment, nil).Once()
+			mockShipmentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(s *domain.Shipment) bool {
+				return s.Status == domain.ShipmentInTransit
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.in_transit")).Return(nil).Once()
+
+			_, err := shipmentUseCase.UpdateShipmentStatus(staff, 1, domain.ShipmentInTransit)
+
+			assert.NoError(t, err)
+			mockShipmentRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+			mockOrderRepo.AssertNotCalled(t, "FindByIDForUpdate", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should complete a shipped order once all shipments are delivered", func(t *testing.T) {
+			setup()
+			order := paidOrder()
+			order.Status = domain.StatusShipped
+			shipment := shipmentOf(2, domain.ShipmentInTransit)
+			delivered := []domain.Shipment{shipmentOf(1, domain.ShipmentDelivered), shipmentOf(2, domain.ShipmentDelivered)}
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(2)).Return(&shipment, nil).Once()
+			mockShipmentRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.delivered")).Return(nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(order, nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(delivered, nil).Once()
+			mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+				return o.Status == domain.StatusCompleted
+			})).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.completed")).Return(nil).Once()
+
+			result, err := shipmentUseCase.UpdateShipmentStatus(staff, 2, domain.ShipmentDelivered)
+
+			require.NoError(t, err)
+			assert.NotNil(t, result.DeliveredAt)
+			mockOrderRepo.AssertExpectations(t)
+			mockOutboxRepo.AssertExpectations(t)
+		})
+
+		t.Run("should not complete the order while other shipments are on their way", func(t *testing.T) {
+			setup()
+			order := paidOrder()
+			order.Status = domain.StatusShipped
+			shipment := shipmentOf(2, domain.ShipmentInTransit)
+			shipments := []domain.Shipment{shipmentOf(1, domain.ShipmentInTransit), shipmentOf(2, domain.ShipmentDelivered)}
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(2)).Return(&shipment, nil).Once()
+			mockShipmentRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.delivered")).Return(nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(order, nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return(shipments, nil).Once()
+
+			_, err := shipmentUseCase.UpdateShipmentStatus(staff, 2, domain.ShipmentDelivered)
+
+			assert.NoError(t, err)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should not complete an order that is not fully shipped", func(t *testing.T) {
+			setup()
+			shipment := shipmentOf(1, domain.ShipmentShipped)
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(&shipment, nil).Once()
+			mockShipmentRepo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil).Once()
+			mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("shipments.delivered")).Return(nil).Once()
+			mockOrderRepo.On("FindByIDForUpdate", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+
+			_, err := shipmentUseCase.UpdateShipmentStatus(staff, 1, domain.ShipmentDelivered)
+
+			assert.NoError(t, err)
+			mockShipmentRepo.AssertNotCalled(t, "FindByOrderID", mock.Anything, mock.Anything)
+			mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should reject invalid transitions", func(t *testing.T) {
+			setup()
+			shipment := shipmentOf(1, domain.ShipmentDelivered)
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(&shipment, nil).Once()
+
+			_, err := shipmentUseCase.UpdateShipmentStatus(staff, 1, domain.ShipmentInTransit)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidShipmentTransition)
+			mockShipmentRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return an error for unknown shipments", func(t *testing.T) {
+			setup()
+			mockShipmentRepo.On("FindByIDForUpdate", mock.Anything, int64(1)).Return(nil, nil).Once()
+
+			_, err := shipmentUseCase.UpdateShipmentStatus(staff, 1, domain.ShipmentInTransit)
+
+			assert.ErrorIs(t, err, usecase.ErrShipmentNotFound)
+		})
+	})
+
+	t.Run("ListShipments", func(t *testing.T) {
+		t.Run("should list the shipments of the caller's order", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+			mockShipmentRepo.On("FindByOrderID", mock.Anything, int64(10)).Return([]domain.Shipment{shipmentOf(1, domain.ShipmentShipped)}, nil).Once()
+
+			shipments, err := shipmentUseCase.ListShipments(asUser(123, domain.RoleCustomer), 10)
+
+			assert.NoError(t, err)
+			assert.Len(t, shipments, 1)
+		})
+
+		t.Run("should forbid customers to list shipments of orders of other users", func(t *testing.T) {
+			setup()
+			mockOrderRepo.On("FindByID", mock.Anything, int64(10)).Return(paidOrder(), nil).Once()
+
+			_, err := shipmentUseCase.ListShipments(asUser(456, domain.RoleCustomer), 10)
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockShipmentRepo.AssertNotCalled(t, "FindByOrderID", mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+)
+
+var (
+	ErrUserNotFound    = domain.NewError(domain.KindNotFound, "user_not_found", "user not found")
+	ErrEmailTaken      = domain.NewError(domain.KindConflict, "email_taken", "email address already in use")
+	ErrUserHasOrders   = domain.NewError(domain.KindConflict, "user_has_orders", "user has orders and cannot be deleted")
+	ErrAddressNotFound = domain.NewError(domain.KindNotFound, "address_not_found", "address not found")
+)
+
+type UserUseCase struct {
+	userRepo    UserRepository
+	addressRepo AddressRepository
+	orderRepo   OrderRepository
+}
+
+// OrderUseCase resolves shipping addresses through UserUseCase while placing an order.
+func NewUserUseCase(ur UserRepository, ar AddressRepository, or OrderRepository) *UserUseCase {
+	return &UserUseCase{
+		userRepo:    ur,
+		addressRepo: ar,
+		orderRepo:   or,
+	}
+}
+
+// CreateUser validates and stores a new user. Email addresses are unique.
+func (uc *UserUseCase) CreateUser(ctx context.Context, input dto.CreateUserInput) (*domain.User, error) {
+	if err := authorize(ctx, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	user, err := domain.NewUser(input.Email, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkEmailAvailable(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUser retrieves a user. Customers may only retrieve themselves.
+func (uc *UserUseCase) GetUser(ctx context.Context, id int64) (*domain.User, error) {
+	if err := authorizeOwner(ctx, id, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	return uc.findUser(ctx, id)
+}
+
+// ListUsers handles listing all users with pagination.
+func (uc *UserUseCase) ListUsers(ctx context.Context, page, pageSize int) ([]domain.User, error) {
+	if err := authorize(ctx, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 { // Limit page size to a max of 100.
+		pageSize = 10
+	}
+
+	return uc.userRepo.FindAll(ctx, pageSize, (page-1)*pageSize)
+}
+
+// UpdateUser replaces the details of a user. Customers may only update themselves.
+func (uc *UserUseCase) UpdateUser(ctx context.Context, id int64, input dto.UpdateUserInput) (*domain.User, error) {
+	if err := authorizeOwner(ctx, id, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.Update(input.Email, input.Name); err != nil {
+		return nil, err
+	}
+	if err := uc.checkEmailAvailable(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteUser deletes a user and their addresses. Users who placed orders are kept,
+// so the order history stays complete.
+func (uc *UserUseCase) DeleteUser(ctx context.Context, id int64) error {
+	if err := authorize(ctx, domain.ScopeManageUsers); err != nil {
+		return err
+	}
+
+	if _, err := uc.findUser(ctx, id); err != nil {
+		return err
+	}
+	orders, err := uc.orderRepo.FindByUserID(ctx, id, 1, 0)
+	if err != nil {
+		return err
+	}
+	if len(orders) > 0 {
+		return ErrUserHasOrders
+	}
+
+	return uc.userRepo.Delete(ctx, id)
+}
+
+// CreateAddress saves a new address for a user.
+func (uc *UserUseCase) CreateAddress(ctx context.Context, userID int64, input dto.AddressInput) (*domain.Address, error) {
+	if err := authorizeOwner(ctx, userID, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	address, err := domain.NewAddress(userID, input.Label, input.Address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.addressRepo.Save(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// ListAddresses returns the saved addresses of a user.
+func (uc *UserUseCase) ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error) {
+	if err := authorizeOwner(ctx, userID, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return uc.addressRepo.FindByUserID(ctx, userID)
+}
+
+// GetAddress retrieves a saved address of a user.
+func (uc *UserUseCase) GetAddress(ctx context.Context, userID, addressID int64) (*domain.Address, error) {
+	if err := authorizeOwner(ctx, userID, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	return uc.findAddress(ctx, userID, addressID)
+}
+
+// UpdateAddress replaces a saved address of a user. Orders already shipped
+// to it keep the address they were placed with.
+func (uc *UserUseCase) UpdateAddress(ctx context.Context, userID, addressID int64, input dto.AddressInput) (*domain.Address, error) {
+	if err := authorizeOwner(ctx, userID, domain.ScopeManageUsers); err != nil {
+		return nil, err
+	}
+
+	address, err := uc.findAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if err := address.Update(input.Label, input.Address); err != nil {
+		return nil, err
+	}
+
+	if err := uc.addressRepo.Update(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// DeleteAddress deletes a saved address of a user.
+func (uc *UserUseCase) DeleteAddress(ctx context.Context, userID, addressID int64) error {
+	if err := authorizeOwner(ctx, userID, domain.ScopeManageUsers); err != nil {
+		return err
+	}
+
+	if _, err := uc.findAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+
+	return uc.addressRepo.Delete(ctx, addressID)
+}
+
+// shippingAddress returns the address an order of the user is shipped to: a copy of
+// the saved address addressID, or the inline address, or nil if neither is given.
+// It also checks that the user exists. Missing users and addresses are unprocessable,
+// as they are named by the order rather than by the request URL.
+func (uc *UserUseCase) shippingAddress(ctx context.Context, userID, addressID int64, inline *domain.PostalAddress) (*domain.PostalAddress, error) {
+	if _, err := uc.findUser(ctx, userID); err != nil {
+		return nil, domain.Unprocessable(err)
+	}
+
+	switch {
+	case addressID != 0 && inline != nil:
+		return nil, domain.NewFieldError(domain.ErrInvalidAddress, "shipping_address", "cannot be given together with shipping_address_id")
+	case addressID != 0:
+		address, err := uc.findAddress(ctx, userID, addressID)
+		if err != nil {
+			return nil, domain.Unprocessable(err)
+		}
+		snapshot := address.PostalAddress
+		return &snapshot, nil
+	case inline != nil:
+		if err := inline.Validate(); err != nil {
+			return nil, err
+		}
+		snapshot := *inline
+		return &snapshot, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (uc *UserUseCase) findUser(ctx context.Context, id int64) (*domain.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// findAddress retrieves an address, treating addresses of other users as not found.
+func (uc *UserUseCase) findAddress(ctx context.Context, userID, addressID int64) (*domain.Address, error) {
+	address, err := uc.addressRepo.FindByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil || address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// checkEmailAvailable returns ErrEmailTaken if another user has the email address of user.
+func (uc *UserUseCase) checkEmailAvailable(ctx context.Context, user *domain.User) error {
+	existing, err := uc.userRepo.FindByEmail(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return ErrEmailTaken
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elokanugrah/go-order-system/internal/domain"
+	"github.com/elokanugrah/go-order-system/internal/dto"
+	"github.com/elokanugrah/go-order-system/internal/usecase"
+	"github.com/elokanugrah/go-order-system/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// homeAddress returns a valid postal address for tests.
+func homeAddress() domain.PostalAddress {
+	return domain.PostalAddress{
+		RecipientName: "Budi Santoso",
+		Phone:         "+6281234567890",
+		Line1:         "Jl. Sudirman No. 1",
+		City:          "Jakarta",
+		PostalCode:    "10220",
+		Country:       "ID",
+	}
+}
+
+func TestUserUseCase(t *testing.T) {
+	var mockUserRepo *mocks.UserRepository
+	var mockAddressRepo *mocks.AddressRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var userUseCase *usecase.UserUseCase
+
+	setup := func() {
+		mockUserRepo = new(mocks.UserRepository)
+		mockAddressRepo = new(mocks.AddressRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		userUseCase = usecase.NewUserUseCase(mockUserRepo, mockAddressRepo, mockOrderRepo)
+	}
+
+	budi := func() *domain.User {
+		return &domain.User{ID: 123, Email: "budi@example.com", Name: "Budi"}
+	}
+
+	t.Run("CreateUser", func(t *testing.T) {
+		t.Run("should store a user with a normalized email", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByEmail", mock.Anything, "budi@example.com").Return(nil, nil).Once()
+			mockUserRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+
+			user, err := userUseCase.CreateUser(asUser(1, domain.RoleAdmin), dto.CreateUserInput{Email: "Budi@Example.com", Name: "Budi"})
+
+			require.NoError(t, err)
+			assert.Equal(t, "budi@example.com", user.Email)
+			mockUserRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject an email that is already in use", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByEmail", mock.Anything, "budi@example.com").Return(budi(), nil).Once()
+
+			_, err := userUseCase.CreateUser(asUser(1, domain.RoleAdmin), dto.CreateUserInput{Email: "budi@example.com", Name: "Budi"})
+
+			assert.ErrorIs(t, err, usecase.ErrEmailTaken)
+			mockUserRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should forbid customers to create users", func(t *testing.T) {
+			setup()
+
+			_, err := userUseCase.CreateUser(asUser(123, domain.RoleCustomer), dto.CreateUserInput{Email: "siti@example.com", Name: "Siti"})
+
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+		})
+	})
+
+	t.Run("GetUser", func(t *testing.T) {
+		t.Run("should let customers retrieve themselves only", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+
+			user, err := userUseCase.GetUser(asUser(123, domain.RoleCustomer), 123)
+			require.NoError(t, err)
+			assert.Equal(t, "budi@example.com", user.Email)
+
+			_, err = userUseCase.GetUser(asUser(456, domain.RoleCustomer), 123)
+			assert.ErrorIs(t, err, usecase.ErrForbidden)
+			mockUserRepo.AssertExpectations(t)
+		})
+
+		t.Run("should return not found error when the user does not exist", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(999)).Return(nil, nil).Once()
+
+			_, err := userUseCase.GetUser(asUser(1, domain.RoleAdmin), 999)
+
+			assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+		})
+	})
+
+	t.Run("UpdateUser", func(t *testing.T) {
+		t.Run("should reject an email that belongs to another user", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+			mockUserRepo.On("FindByEmail", mock.Anything, "siti@example.com").Return(&domain.User{ID: 456, Email: "siti@example.com"}, nil).Once()
+
+			_, err := userUseCase.UpdateUser(asUser(123, domain.RoleCustomer), 123, dto.UpdateUserInput{Email: "siti@example.com", Name: "Budi"})
+
+			assert.ErrorIs(t, err, usecase.ErrEmailTaken)
+			mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should keep the user's own email", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+			mockUserRepo.On("FindByEmail", mock.Anything, "budi@example.com").Return(budi(), nil).Once()
+			mockUserRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+
+			user, err := userUseCase.UpdateUser(asUser(123, domain.RoleCustomer), 123, dto.UpdateUserInput{Email: "budi@example.com", Name: "Budi Santoso"})
+
+			require.NoError(t, err)
+			assert.Equal(t, "Budi Santoso", user.Name)
+			mockUserRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		t.Run("should keep users who placed orders", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+			mockOrderRepo.On("FindByUserID", mock.Anything, int64(123), 1, 0).Return([]domain.Order{{ID: 1, UserID: 123}}, nil).Once()
+
+			err := userUseCase.DeleteUser(asUser(1, domain.RoleAdmin), 123)
+
+			assert.ErrorIs(t, err, usecase.ErrUserHasOrders)
+			mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should delete users without orders", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+			mockOrderRepo.On("FindByUserID", mock.Anything, int64(123), 1, 0).Return(nil, nil).Once()
+			mockUserRepo.On("Delete", mock.Anything, int64(123)).Return(nil).Once()
+
+			err := userUseCase.DeleteUser(asUser(1, domain.RoleAdmin), 123)
+
+			assert.NoError(t, err)
+			mockUserRepo.AssertExpectations(t)
+		})
+	})
+
+	t.Run("Addresses", func(t *testing.T) {
+		t.Run("should save a valid address for the user", func(t *testing.T) {
+			setup()
+			mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(budi(), nil).Once()
+			mockAddressRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Address")).Return(nil).Once()
+
+			address, err := userUseCase.CreateAddress(asUser(123, domain.RoleCustomer), 123, dto.AddressInput{Label: "Home", Address: homeAddress()})
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(123), address.UserID)
+			mockAddressRepo.AssertExpectations(t)
+		})
+
+		t.Run("should reject an invalid address", func(t *testing.T) {
+			setup()
+			invalid := homeAddress()
+			invalid.PostalCode = ""
+
+			_, err := userUseCase.CreateAddress(asUser(123, domain.RoleCustomer), 123, dto.AddressInput{Address: invalid})
+
+			assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+			mockAddressRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should not reveal the addresses of another user", func(t *testing.T) {
+			setup()
+			mockAddressRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Address{ID: 5, UserID: 456, PostalAddress: homeAddress()}, nil).Once()
+
+			_, err := userUseCase.GetAddress(asUser(123, domain.RoleCustomer), 123, 5)
+
+			assert.ErrorIs(t, err, usecase.ErrAddressNotFound)
+		})
+	})
+}
+
+func TestOrderUseCase_CreateOrder_ShippingAddress(t *testing.T) {
+	var mockProductRepo *mocks.ProductRepository
+	var mockOrderRepo *mocks.OrderRepository
+	var mockTxManager *mocks.TransactionManager
+	var mockOutboxRepo *mocks.OutboxRepository
+	var mockUserRepo *mocks.UserRepository
+	var mockAddressRepo *mocks.AddressRepository
+	var orderUseCase *usecase.OrderUseCase
+
+	setup := func() {
+		mockProductRepo = new(mocks.ProductRepository)
+		mockOrderRepo = new(mocks.OrderRepository)
+		mockTxManager = new(mocks.TransactionManager)
+		mockOutboxRepo = new(mocks.OutboxRepository)
+		mockUserRepo = new(mocks.UserRepository)
+		mockAddressRepo = new(mocks.AddressRepository)
+
+		users := usecase.NewUserUseCase(mockUserRepo, mockAddressRepo, mockOrderRepo)
+		orderUseCase = usecase.NewOrderUseCase(mockOrderRepo, mockProductRepo, mockTxManager, mockOutboxRepo, new(mocks.IdempotencyRepository), new(mocks.RateProvider), usecase.NewPromotionUseCase(new(mocks.CouponRepository)), zeroTax(), users, new(mocks.PaymentRepository), new(mocks.ShipmentRepository), new(mocks.ReturnRepository))
+
+		mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).Maybe()
+	}
+
+	// expectPlaced sets up a successful order of one product by user 123.
+	expectPlaced := func() {
+		mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(&domain.User{ID: 123}, nil).Once()
+		mockProductRepo.On("FindManyByIDsForUpdate", mock.Anything, []int64{1}).Return([]domain.Product{{ID: 1, Name: "Product A", Price: idr("10000"), Quantity: 10}}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockOrderRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		mockOutboxRepo.On("Save", mock.Anything, outboxMessageFor("orders.created")).Return(nil).Once()
+	}
+
+	items := []dto.CreateOrderItemInput{{ProductID: 1, Quantity: 1}}
+
+	t.Run("should snapshot a saved address of the user", func(t *testing.T) {
+		setup()
+		expectPlaced()
+		saved := &domain.Address{ID: 5, UserID: 123, Label: "Home", PostalAddress: homeAddress()}
+		mockAddressRepo.On("FindByID", mock.Anything, int64(5)).Return(saved, nil).Once()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), dto.CreateOrderInput{UserID: 123, Items: items, ShippingAddressID: 5})
+
+		require.NoError(t, err)
+		require.NotNil(t, order.ShippingAddress)
+		assert.Equal(t, homeAddress(), *order.ShippingAddress)
+
+		// Editing the saved address afterwards does not move the order.
+		saved.City = "Bandung"
+		assert.Equal(t, "Jakarta", order.ShippingAddress.City)
+	})
+
+	t.Run("should accept an inline address", func(t *testing.T) {
+		setup()
+		expectPlaced()
+		inline := homeAddress()
+
+		order, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), dto.CreateOrderInput{UserID: 123, Items: items, ShippingAddress: &inline})
+
+		require.NoError(t, err)
+		assert.Equal(t, &inline, order.ShippingAddress)
+	})
+
+	t.Run("should reject an address of another user", func(t *testing.T) {
+		setup()
+		mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(&domain.User{ID: 123}, nil).Once()
+		mockAddressRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Address{ID: 5, UserID: 456, PostalAddress: homeAddress()}, nil).Once()
+
+		_, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), dto.CreateOrderInput{UserID: 123, Items: items, ShippingAddressID: 5})
+
+		assert.ErrorIs(t, err, usecase.ErrAddressNotFound)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject both a saved and an inline address", func(t *testing.T) {
+		setup()
+		mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(&domain.User{ID: 123}, nil).Once()
+		inline := homeAddress()
+
+		_, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), dto.CreateOrderInput{UserID: 123, Items: items, ShippingAddressID: 5, ShippingAddress: &inline})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+	})
+
+	t.Run("should reject orders of unknown users", func(t *testing.T) {
+		setup()
+		mockUserRepo.On("FindByID", mock.Anything, int64(123)).Return(nil, nil).Once()
+
+		_, err := orderUseCase.CreateOrder(asUser(123, domain.RoleCustomer), dto.CreateOrderInput{UserID: 123, Items: items})
+
+		assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+		mockOrderRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
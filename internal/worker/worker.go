@@ -0,0 +1,235 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/events"
+	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrPermanent marks failures that will never succeed on retry. Handlers wrap it
+// (fmt.Errorf("%w: ...", worker.ErrPermanent)) to send a message straight to the dead-letter queue.
+var ErrPermanent = errors.New("permanent failure")
+
+// Handler processes a single event. A returned error triggers a delayed retry,
+// unless it wraps ErrPermanent.
+type Handler func(ctx context.Context, env *events.Envelope) error
+
+// Channel is the subset of *amqp.Channel used by the worker.
+type Channel interface {
+	messagebroker.QueueDeclarer
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// Config controls how many messages are processed at once and how failures are retried.
+type Config struct {
+	// ConsumerName prefixes the consumer tag of every queue.
+	ConsumerName string
+	// Concurrency is the maximum number of handlers running at the same time.
+	Concurrency int
+	// Prefetch is the number of unacknowledged messages the broker may push to the worker.
+	Prefetch int
+	// MaxRetries is the number of retries before a message is dead-lettered.
+	MaxRetries int
+	// RetryDelay is how long a failed message waits before it is delivered again.
+	RetryDelay time.Duration
+}
+
+// Worker consumes the queues of all registered event types and dispatches
+// every message to its handler.
+type Worker struct {
+	ch       Channel
+	cfg      Config
+	handlers map[events.Type]Handler
+}
+
+func New(ch Channel, cfg Config) *Worker {
+	if cfg.ConsumerName == "" {
+		cfg.ConsumerName = "worker"
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Prefetch < cfg.Concurrency {
+		cfg.Prefetch = cfg.Concurrency
+	}
+
+	return &Worker{
+		ch:       ch,
+		cfg:      cfg,
+		handlers: make(map[events.Type]Handler),
+	}
+}
+
+// Handle registers the handler for an event type. The event is consumed from the
+// queue named after its type. Registering a type twice replaces the handler.
+func (w *Worker) Handle(eventType events.Type, h Handler) {
+	w.handlers[eventType] = h
+}
+
+// Run consumes all registered queues until the context is cancelled or a delivery
+// channel is closed by the broker. Before returning it stops consuming and waits for
+// every in-flight handler to finish, so the caller can safely close the channel.
+// Messages that were prefetched but not started are returned to their queue when
+// the channel is closed.
+func (w *Worker) Run(ctx context.Context) error {
+	if len(w.handlers) == 0 {
+		return errors.New("no handlers registered")
+	}
+
+	if err := w.ch.Qos(w.cfg.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	var (
+		inFlight  sync.WaitGroup
+		consumers sync.WaitGroup
+		errOnce   sync.Once
+		runErr    error
+	)
+	sem := make(chan struct{}, w.cfg.Concurrency)
+
+	// Handlers keep running during shutdown, only the consumption stops.
+	handlerCtx := context.WithoutCancel(ctx)
+	consumeCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var tags []string
+	for eventType, h := range w.handlers {
+		queueName := string(eventType)
+		if err := messagebroker.DeclareQueue(w.ch, queueName); err != nil {
+			return err
+		}
+
+		tag := w.cfg.ConsumerName + "-" + queueName
+		deliveries, err := w.ch.Consume(
+			queueName,
+			tag,   // consumer name
+			false, // auto-ack
+			false, // exclusive
+			false, // no-local
+			false, // no-wait
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register a consumer for %s: %w", queueName, err)
+		}
+		tags = append(tags, tag)
+
+		consumers.Add(1)
+		go func(eventType events.Type, h Handler, deliveries <-chan amqp.Delivery) {
+			defer consumers.Done()
+			for {
+				select {
+				case <-consumeCtx.Done():
+					return
+				case d, ok := <-deliveries:
+					if !ok {
+						// Closed by the broker, unless we are already shutting down.
+						if consumeCtx.Err() == nil {
+							errOnce.Do(func() { runErr = fmt.Errorf("delivery channel for %s closed", eventType) })
+							stop()
+						}
+						return
+					}
+
+					// Wait for a free slot, the message stays unacked until it is handled.
+					select {
+					case sem <- struct{}{}:
+					case <-consumeCtx.Done():
+						return
+					}
+
+					inFlight.Add(1)
+					go func() {
+						defer inFlight.Done()
+						defer func() { <-sem }()
+						w.handle(handlerCtx, eventType, h, d)
+					}()
+				}
+			}
+		}(eventType, h, deliveries)
+	}
+
+	log.Printf("[WORKER] Consuming %d queue(s) with concurrency %d and prefetch %d", len(tags), w.cfg.Concurrency, w.cfg.Prefetch)
+
+	<-consumeCtx.Done()
+
+	// Stop the broker from pushing more messages, then drain in-flight handlers.
+	for _, tag := range tags {
+		if err := w.ch.Cancel(tag, false); err != nil {
+			log.Printf("[WORKER] WARN: Failed to cancel consumer %s: %v", tag, err)
+		}
+	}
+	consumers.Wait()
+	log.Println("[WORKER] Waiting for in-flight handlers to finish...")
+	inFlight.Wait()
+
+	return runErr
+}
+
+// handle runs the handler for a delivery and settles it. Successful messages are acked,
+// failed ones are scheduled on the retry queue until MaxRetries is reached, and
+// malformed, permanently failing or exhausted messages are rejected to the dead-letter queue.
+func (w *Worker) handle(ctx context.Context, eventType events.Type, h Handler, d amqp.Delivery) {
+	err := w.process(ctx, eventType, h, d.Body)
+	if err == nil {
+		if err := d.Ack(false); err != nil {
+			log.Printf("[WORKER] ERROR: Failed to ack message: %v", err)
+		}
+		return
+	}
+
+	retries := messagebroker.RetryCount(d.Headers)
+	if errors.Is(err, ErrPermanent) || retries >= w.cfg.MaxRetries {
+		log.Printf("[WORKER] ERROR: Dead-lettering %s message after %d retries: %v", eventType, retries, err)
+		if err := d.Nack(false, false); err != nil {
+			log.Printf("[WORKER] ERROR: Failed to reject message: %v", err)
+		}
+		return
+	}
+
+	log.Printf("[WORKER] WARN: Processing %s failed, retry %d/%d in %s: %v", eventType, retries+1, w.cfg.MaxRetries, w.cfg.RetryDelay, err)
+	err = w.ch.PublishWithContext(ctx,
+		"", // exchange (default)
+		messagebroker.RetryQueueName(string(eventType)),
+		false, // mandatory
+		false, // immediate
+		messagebroker.RetryPublishing(d, w.cfg.RetryDelay),
+	)
+	if err != nil {
+		// Could not schedule the retry, put the message straight back instead of losing it.
+		log.Printf("[WORKER] ERROR: Failed to schedule retry: %v", err)
+		if err := d.Nack(false, true); err != nil {
+			log.Printf("[WORKER] ERROR: Failed to requeue message: %v", err)
+		}
+		return
+	}
+
+	if err := d.Ack(false); err != nil {
+		log.Printf("[WORKER] ERROR: Failed to ack message: %v", err)
+	}
+}
+
+// process decodes the envelope and calls the handler. Envelopes of an unknown
+// type or version, or of a different type than the queue, are permanent failures.
+func (w *Worker) process(ctx context.Context, eventType events.Type, h Handler, body []byte) error {
+	env, err := events.Decode(body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	if env.Type != eventType {
+		return fmt.Errorf("%w: unexpected event type %s on queue %s", ErrPermanent, env.Type, eventType)
+	}
+
+	return h(ctx, env)
+}
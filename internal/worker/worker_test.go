@@ -0,0 +1,213 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elokanugrah/go-order-system/internal/events"
+	"github.com/elokanugrah/go-order-system/internal/messagebroker"
+	"github.com/elokanugrah/go-order-system/internal/worker"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannel is an in-memory stand-in for *amqp.Channel.
+type fakeChannel struct {
+	mu         sync.Mutex
+	deliveries map[string]chan amqp.Delivery
+	published  []string
+	publishErr error
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{deliveries: make(map[string]chan amqp.Delivery)}
+}
+
+func (f *fakeChannel) queue(name string) chan amqp.Delivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.deliveries[name]; !ok {
+		f.deliveries[name] = make(chan amqp.Delivery, 100)
+	}
+	return f.deliveries[name]
+}
+
+func (f *fakeChannel) ExchangeDeclare(string, string, bool, bool, bool, bool, amqp.Table) error {
+	return nil
+}
+
+func (f *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, _ amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (f *fakeChannel) QueueBind(string, string, string, bool, amqp.Table) error { return nil }
+
+func (f *fakeChannel) Qos(int, int, bool) error { return nil }
+
+func (f *fakeChannel) Consume(queue, _ string, _, _, _, _ bool, _ amqp.Table) (<-chan amqp.Delivery, error) {
+	return f.queue(queue), nil
+}
+
+func (f *fakeChannel) Cancel(string, bool) error { return nil }
+
+func (f *fakeChannel) PublishWithContext(_ context.Context, _, key string, _, _ bool, _ amqp.Publishing) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.publishErr != nil {
+		return f.publishErr
+	}
+	f.published = append(f.published, key)
+	return nil
+}
+
+// fakeAcknowledger records how a delivery was settled.
+type fakeAcknowledger struct {
+	settled chan string
+}
+
+func (a *fakeAcknowledger) Ack(uint64, bool) error { a.settled <- "ack"; return nil }
+
+func (a *fakeAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	if requeue {
+		a.settled <- "requeue"
+	} else {
+		a.settled <- "dead-letter"
+	}
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(_ uint64, requeue bool) error { return a.Nack(0, false, requeue) }
+
+func orderCreatedBody(t *testing.T, orderID int64) []byte {
+	env, err := events.New(context.Background(), events.TypeOrderCreated, events.OrderCreated{OrderID: orderID})
+	require.NoError(t, err)
+	body, err := json.Marshal(env)
+	require.NoError(t, err)
+	return body
+}
+
+func TestWorker_Settlement(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          func(t *testing.T) []byte
+		retries       int32
+		handlerErr    error
+		publishErr    error
+		wantSettle    string
+		wantPublished []string
+	}{
+		{name: "should ack a processed message", body: func(t *testing.T) []byte { return orderCreatedBody(t, 1) }, wantSettle: "ack"},
+		{name: "should schedule a retry on failure", body: func(t *testing.T) []byte { return orderCreatedBody(t, 1) }, handlerErr: errors.New("smtp down"), wantSettle: "ack", wantPublished: []string{"orders.created.retry"}},
+		{name: "should dead-letter when retries are exhausted", body: func(t *testing.T) []byte { return orderCreatedBody(t, 1) }, retries: 3, handlerErr: errors.New("smtp down"), wantSettle: "dead-letter"},
+		{name: "should dead-letter a permanent failure", body: func(t *testing.T) []byte { return orderCreatedBody(t, 1) }, handlerErr: fmt.Errorf("%w: bad data", worker.ErrPermanent), wantSettle: "dead-letter"},
+		{name: "should dead-letter an unknown version", body: func(*testing.T) []byte {
+			return []byte(`{"event_id":"1","type":"orders.created","version":99,"payload":{}}`)
+		}, wantSettle: "dead-letter"},
+		{name: "should requeue when the retry cannot be scheduled", body: func(t *testing.T) []byte { return orderCreatedBody(t, 1) }, handlerErr: errors.New("smtp down"), publishErr: errors.New("channel closed"), wantSettle: "requeue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := newFakeChannel()
+			ch.publishErr = tt.publishErr
+			w := worker.New(ch, worker.Config{Concurrency: 1, MaxRetries: 3, RetryDelay: time.Second})
+			w.Handle(events.TypeOrderCreated, func(context.Context, *events.Envelope) error { return tt.handlerErr })
+
+			ack := &fakeAcknowledger{settled: make(chan string, 1)}
+			ch.queue("orders.created") <- amqp.Delivery{
+				Acknowledger: ack,
+				Headers:      amqp.Table{messagebroker.RetryCountHeader: tt.retries},
+				Body:         tt.body(t),
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- w.Run(ctx) }()
+
+			select {
+			case settled := <-ack.settled:
+				assert.Equal(t, tt.wantSettle, settled)
+			case <-time.After(2 * time.Second):
+				t.Fatal("message was not settled")
+			}
+
+			cancel()
+			assert.NoError(t, <-done)
+			assert.Equal(t, tt.wantPublished, ch.published)
+		})
+	}
+}
+
+func TestWorker_ConcurrencyLimitAndGracefulShutdown(t *testing.T) {
+	ch := newFakeChannel()
+	w := worker.New(ch, worker.Config{Concurrency: 2, Prefetch: 10})
+
+	var running, maxRunning int32
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	w.Handle(events.TypeOrderCreated, func(context.Context, *events.Envelope) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		started <- struct{}{}
+		<-release
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	ack := &fakeAcknowledger{settled: make(chan string, 10)}
+	for i := 0; i < 5; i++ {
+		ch.queue("orders.created") <- amqp.Delivery{Acknowledger: ack, Body: orderCreatedBody(t, int64(i+1))}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	// Two handlers start, the third has to wait for a free slot.
+	<-started
+	<-started
+	select {
+	case <-started:
+		t.Fatal("more handlers started than the concurrency limit")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Shutting down must wait for the in-flight handlers.
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Run returned before in-flight handlers finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after handlers finished")
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	assert.Len(t, ack.settled, 2) // Only the started messages were acked, the rest stay queued.
+}
+
+func TestWorker_RunWithoutHandlers(t *testing.T) {
+	w := worker.New(newFakeChannel(), worker.Config{})
+
+	err := w.Run(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,14 @@
+-- migration/000002_create_outbox.up.sql
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "queue_name" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" text NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz
+);
+
+-- Only unsent messages are ever polled by the relay.
+CREATE INDEX "outbox_pending_idx" ON "outbox" ("next_attempt_at") WHERE "sent_at" IS NULL;
\ No newline at end of file
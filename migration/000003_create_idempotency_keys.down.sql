@@ -0,0 +1,2 @@
+-- migration/000003_create_idempotency_keys.down.sql
+DROP TABLE IF EXISTS "idempotency_keys";
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- migration/000003_create_idempotency_keys.up.sql
+CREATE TABLE "idempotency_keys" (
+  "key" varchar(255) PRIMARY KEY,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
\ No newline at end of file
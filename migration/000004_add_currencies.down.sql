@@ -0,0 +1,11 @@
+-- migration/000004_add_currencies.down.sql
+DROP TABLE IF EXISTS "exchange_rates";
+
+ALTER TABLE "order_items"
+  DROP COLUMN IF EXISTS "exchange_rate",
+  DROP COLUMN IF EXISTS "base_currency",
+  DROP COLUMN IF EXISTS "base_price";
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "currency";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "currency";
\ No newline at end of file
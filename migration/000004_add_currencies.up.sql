@@ -0,0 +1,22 @@
+-- migration/000004_add_currencies.up.sql
+ALTER TABLE "products" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE "orders" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'IDR';
+
+-- Snapshot of the conversion applied to each item, so historical orders stay reproducible.
+ALTER TABLE "order_items"
+  ADD COLUMN "base_price" decimal(10, 2),
+  ADD COLUMN "base_currency" varchar(3) NOT NULL DEFAULT 'IDR',
+  ADD COLUMN "exchange_rate" decimal(20, 8) NOT NULL DEFAULT 1;
+
+UPDATE "order_items" SET "base_price" = "price_at_order";
+
+ALTER TABLE "order_items" ALTER COLUMN "base_price" SET NOT NULL;
+
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar(3) NOT NULL,
+  "to_currency" varchar(3) NOT NULL,
+  "rate" decimal(20, 8) NOT NULL CHECK ("rate" > 0),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
\ No newline at end of file
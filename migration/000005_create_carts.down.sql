@@ -0,0 +1,4 @@
+-- migration/000005_create_carts.down.sql
+DROP TABLE IF EXISTS "cart_items";
+
+DROP TABLE IF EXISTS "carts";
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- migration/000005_create_carts.up.sql
+CREATE TABLE "carts" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT 'IDR',
+  "status" varchar NOT NULL DEFAULT 'open',
+  "order_id" bigint REFERENCES "orders" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "cart_items" (
+  "id" bigserial PRIMARY KEY,
+  "cart_id" bigint NOT NULL REFERENCES "carts" ("id") ON DELETE CASCADE,
+  "product_id" bigint NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+  "quantity" integer NOT NULL CHECK ("quantity" > 0),
+  UNIQUE ("cart_id", "product_id")
+);
+
+CREATE INDEX ON "carts" ("user_id");
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- migration/000006_create_coupons.down.sql
+ALTER TABLE "orders"
+  DROP COLUMN IF EXISTS "coupon_code",
+  DROP COLUMN IF EXISTS "discount_amount",
+  DROP COLUMN IF EXISTS "subtotal";
+
+DROP TABLE IF EXISTS "coupon_redemptions";
+
+DROP TABLE IF EXISTS "coupons";
@@ -0,0 +1,39 @@
+-- migration/000006_create_coupons.up.sql
+CREATE TABLE "coupons" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL UNIQUE,
+  "type" varchar NOT NULL,
+  "percent" integer NOT NULL DEFAULT 0,
+  "amount" decimal(10, 2) NOT NULL DEFAULT 0,
+  "min_subtotal" decimal(10, 2) NOT NULL DEFAULT 0,
+  "currency" varchar(3) NOT NULL DEFAULT 'IDR',
+  "product_ids" bigint[] NOT NULL DEFAULT '{}',
+  "max_uses" integer NOT NULL DEFAULT 0,
+  "max_uses_per_user" integer NOT NULL DEFAULT 0,
+  "used_count" integer NOT NULL DEFAULT 0,
+  "starts_at" timestamptz,
+  "ends_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "coupon_redemptions" (
+  "id" bigserial PRIMARY KEY,
+  "coupon_id" bigint NOT NULL REFERENCES "coupons" ("id"),
+  "user_id" bigint NOT NULL,
+  "order_id" bigint NOT NULL REFERENCES "orders" ("id"),
+  "discount_amount" decimal(10, 2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "coupon_redemptions" ("coupon_id", "user_id");
+
+-- Existing orders had no discount, so their subtotal is their total.
+ALTER TABLE "orders"
+  ADD COLUMN "subtotal" decimal(10, 2),
+  ADD COLUMN "discount_amount" decimal(10, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN "coupon_code" varchar NOT NULL DEFAULT '';
+
+UPDATE "orders" SET "subtotal" = "total_amount";
+
+ALTER TABLE "orders" ALTER COLUMN "subtotal" SET NOT NULL;
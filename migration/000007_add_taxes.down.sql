@@ -0,0 +1,10 @@
+-- migration/000007_add_taxes.down.sql
+ALTER TABLE "orders"
+  DROP COLUMN IF EXISTS "tax_region",
+  DROP COLUMN IF EXISTS "tax_amount";
+
+ALTER TABLE "order_items"
+  DROP COLUMN IF EXISTS "tax_amount",
+  DROP COLUMN IF EXISTS "tax_rate";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "tax_category";
@@ -0,0 +1,11 @@
+-- migration/000007_add_taxes.up.sql
+ALTER TABLE "products" ADD COLUMN "tax_category" varchar NOT NULL DEFAULT 'standard';
+
+-- Existing orders were placed before taxes were charged.
+ALTER TABLE "order_items"
+  ADD COLUMN "tax_rate" decimal(12, 8) NOT NULL DEFAULT 0,
+  ADD COLUMN "tax_amount" decimal(10, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE "orders"
+  ADD COLUMN "tax_amount" decimal(10, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN "tax_region" varchar NOT NULL DEFAULT '';
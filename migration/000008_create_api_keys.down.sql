@@ -0,0 +1,2 @@
+-- migration/000008_create_api_keys.down.sql
+DROP TABLE IF EXISTS "api_keys";
@@ -0,0 +1,13 @@
+-- migration/000008_create_api_keys.up.sql
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "prefix" varchar NOT NULL UNIQUE,
+  "secret_hash" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL DEFAULT '{}',
+  "expires_at" timestamptz,
+  "last_used_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
@@ -0,0 +1,8 @@
+-- migration/000009_create_users.down.sql
+ALTER TABLE "orders"
+  DROP COLUMN IF EXISTS "shipping_address",
+  DROP CONSTRAINT IF EXISTS "orders_user_id_fkey";
+
+DROP TABLE IF EXISTS "addresses";
+
+DROP TABLE IF EXISTS "users";
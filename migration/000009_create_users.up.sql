@@ -0,0 +1,38 @@
+-- migration/000009_create_users.up.sql
+CREATE TABLE "users" (
+  "id" bigserial PRIMARY KEY,
+  "email" varchar NOT NULL UNIQUE,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "addresses" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "label" varchar NOT NULL DEFAULT '',
+  "recipient_name" varchar NOT NULL,
+  "phone" varchar NOT NULL,
+  "line1" varchar NOT NULL,
+  "line2" varchar NOT NULL DEFAULT '',
+  "city" varchar NOT NULL,
+  "province" varchar NOT NULL DEFAULT '',
+  "postal_code" varchar NOT NULL,
+  "country" varchar(2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "addresses" ("user_id");
+
+-- Orders were placed by user IDs without a users table. Create a placeholder user
+-- for each of them, so the foreign key holds for the existing orders.
+INSERT INTO "users" ("id", "email", "name")
+SELECT DISTINCT "user_id", 'user-' || "user_id" || '@placeholder.invalid', 'User ' || "user_id"
+FROM "orders";
+
+SELECT setval(pg_get_serial_sequence('users', 'id'), COALESCE((SELECT MAX("id") FROM "users"), 0) + 1, false);
+
+ALTER TABLE "orders"
+  ADD CONSTRAINT "orders_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id"),
+  ADD COLUMN "shipping_address" jsonb;
@@ -0,0 +1,2 @@
+-- migration/000010_create_payments.down.sql
+DROP TABLE IF EXISTS "payments";
@@ -0,0 +1,15 @@
+-- migration/000010_create_payments.up.sql
+CREATE TABLE "payments" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" bigint NOT NULL REFERENCES "orders" ("id"),
+  "provider" varchar NOT NULL,
+  "provider_payment_id" varchar NOT NULL,
+  "amount" decimal(10, 2) NOT NULL,
+  "currency" varchar(3) NOT NULL,
+  "status" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("provider", "provider_payment_id")
+);
+
+CREATE INDEX ON "payments" ("order_id");
@@ -0,0 +1,6 @@
+-- migration/000011_create_returns.down.sql
+DROP TABLE IF EXISTS "return_items";
+
+DROP TABLE IF EXISTS "returns";
+
+ALTER TABLE "payments" DROP COLUMN IF EXISTS "refunded_amount";
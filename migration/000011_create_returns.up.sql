@@ -0,0 +1,27 @@
+-- migration/000011_create_returns.up.sql
+ALTER TABLE "payments" ADD COLUMN "refunded_amount" decimal(10, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE "returns" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" bigint NOT NULL REFERENCES "orders" ("id"),
+  "user_id" bigint NOT NULL REFERENCES "users" ("id"),
+  "reason" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL,
+  "refund_amount" decimal(10, 2) NOT NULL,
+  "currency" varchar(3) NOT NULL,
+  "refund_id" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "returns" ("order_id");
+
+CREATE TABLE "return_items" (
+  "id" bigserial PRIMARY KEY,
+  "return_id" bigint NOT NULL REFERENCES "returns" ("id") ON DELETE CASCADE,
+  "product_id" bigint NOT NULL REFERENCES "products" ("id"),
+  "quantity" integer NOT NULL CHECK ("quantity" > 0),
+  "price_at_order" decimal(10, 2) NOT NULL
+);
+
+CREATE INDEX ON "return_items" ("return_id");
@@ -0,0 +1,4 @@
+-- migration/000012_create_shipments.down.sql
+DROP TABLE IF EXISTS "shipment_items";
+
+DROP TABLE IF EXISTS "shipments";
@@ -0,0 +1,23 @@
+-- migration/000012_create_shipments.up.sql
+CREATE TABLE "shipments" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" bigint NOT NULL REFERENCES "orders" ("id"),
+  "carrier" varchar NOT NULL,
+  "tracking_number" varchar NOT NULL,
+  "status" varchar NOT NULL,
+  "shipped_at" timestamptz NOT NULL,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "shipments" ("order_id");
+
+CREATE TABLE "shipment_items" (
+  "id" bigserial PRIMARY KEY,
+  "shipment_id" bigint NOT NULL REFERENCES "shipments" ("id") ON DELETE CASCADE,
+  "product_id" bigint NOT NULL REFERENCES "products" ("id"),
+  "quantity" integer NOT NULL CHECK ("quantity" > 0)
+);
+
+CREATE INDEX ON "shipment_items" ("shipment_id");
@@ -0,0 +1 @@
+ALTER TABLE "carts" DROP CONSTRAINT IF EXISTS "carts_user_id_fkey";
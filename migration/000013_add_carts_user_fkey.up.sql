@@ -0,0 +1,11 @@
+-- Carts were created for user IDs without checking them. Create a placeholder user
+-- for each unknown one, like 000009 did for orders, so the foreign key holds.
+INSERT INTO "users" ("id", "email", "name")
+SELECT DISTINCT "user_id", 'user-' || "user_id" || '@placeholder.invalid', 'User ' || "user_id"
+FROM "carts"
+WHERE "user_id" NOT IN (SELECT "id" FROM "users");
+
+SELECT setval(pg_get_serial_sequence('users', 'id'), COALESCE((SELECT MAX("id") FROM "users"), 0) + 1, false);
+
+ALTER TABLE "carts"
+  ADD CONSTRAINT "carts_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
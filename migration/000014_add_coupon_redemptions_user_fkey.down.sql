@@ -0,0 +1 @@
+ALTER TABLE "coupon_redemptions" DROP CONSTRAINT IF EXISTS "coupon_redemptions_user_id_fkey";
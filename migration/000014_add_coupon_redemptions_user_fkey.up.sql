@@ -0,0 +1,11 @@
+-- Redemptions recorded the user ID of their order without a foreign key. Create a
+-- placeholder user for each unknown one, like 000009 did for orders.
+INSERT INTO "users" ("id", "email", "name")
+SELECT DISTINCT "user_id", 'user-' || "user_id" || '@placeholder.invalid', 'User ' || "user_id"
+FROM "coupon_redemptions"
+WHERE "user_id" NOT IN (SELECT "id" FROM "users");
+
+SELECT setval(pg_get_serial_sequence('users', 'id'), COALESCE((SELECT MAX("id") FROM "users"), 0) + 1, false);
+
+ALTER TABLE "coupon_redemptions"
+  ADD CONSTRAINT "coupon_redemptions_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id");